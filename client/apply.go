@@ -0,0 +1,240 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"ocopea/kubernetes/client/v1"
+	"reflect"
+	"strings"
+)
+
+// Annotation holding the configuration last applied to an object, kubectl uses the same annotation
+// so objects can be applied by either
+const LastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// Metadata fields set by the api server, never applied
+var serverSetMetadataFields = []string{
+	"uid", "resourceVersion", "creationTimestamp", "deletionTimestamp", "selfLink", "generation",
+}
+
+type ApplyOperation string
+
+const (
+	ApplyCreated    ApplyOperation = "created"
+	ApplyConfigured ApplyOperation = "configured"
+	ApplyUnchanged  ApplyOperation = "unchanged"
+)
+
+// ApplyResult describes what applying an object did
+type ApplyResult struct {
+	// The object as returned by the server
+	Object    Unstructured
+	Operation ApplyOperation
+
+	// The merge patch sent to the server, nil unless the object was configured
+	Patch map[string]interface{}
+}
+
+// Returns the api version and kind of typed objects, objects created in code usually leave them empty
+func typeMetaOf(obj interface{}) (string, string) {
+	objType := reflect.TypeOf(obj)
+	for key, kind := range manifestKinds {
+		if kind.newObject != nil && reflect.TypeOf(kind.newObject()) == objType {
+			group := strings.SplitN(key, "/", 2)[0]
+			if group == "" {
+				return kind.resource.Version, key[1:]
+			}
+			return group + "/" + kind.resource.Version, key[len(group)+1:]
+		}
+	}
+	return "", ""
+}
+
+// Removes null values and the maps left empty from the object, those are not set by the desired configuration
+func pruneUnset(obj map[string]interface{}) {
+	for key, value := range obj {
+		if nested, ok := value.(map[string]interface{}); ok {
+			pruneUnset(nested)
+			if len(nested) == 0 {
+				delete(obj, key)
+			}
+		} else if value == nil {
+			delete(obj, key)
+		}
+	}
+}
+
+// Returns a copy of typed objects with the fields the server defaults set, so fields left unset by the caller
+// but serialized with their zero value, e.g. the target ports of services, don't differ from the live object
+func withServerDefaults(obj interface{}) interface{} {
+	switch o := obj.(type) {
+	case *v1.Pod:
+		defaulted := o.DeepCopy()
+		defaulted.SetDefaults()
+		return defaulted
+	case *v1.ReplicationController:
+		defaulted := o.DeepCopy()
+		defaulted.SetDefaults()
+		return defaulted
+	case *v1.Service:
+		defaulted := o.DeepCopy()
+		defaulted.SetDefaults()
+		return defaulted
+	case *v1.PersistentVolume:
+		defaulted := o.DeepCopy()
+		defaulted.SetDefaults()
+		return defaulted
+	}
+	return obj
+}
+
+// Returns the desired configuration of the object to apply, without status and fields the server sets.
+// Typed objects are defaulted like the server does, see withServerDefaults
+func desiredConfiguration(obj interface{}) (Unstructured, error) {
+	u, err := ToUnstructured(withServerDefaults(obj))
+	if err != nil {
+		return nil, err
+	}
+	if u.GetKind() == "" || u.GetAPIVersion() == "" {
+		apiVersion, kind := typeMetaOf(obj)
+		if kind == "" {
+			return nil, fmt.Errorf("object %s of type %T is missing its kind or apiVersion", u.GetName(), obj)
+		}
+		u["apiVersion"] = apiVersion
+		u["kind"] = kind
+	}
+	delete(u, "status")
+	if metadata, ok := u["metadata"].(map[string]interface{}); ok {
+		for _, field := range serverSetMetadataFields {
+			delete(metadata, field)
+		}
+		if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+			delete(annotations, LastAppliedConfigAnnotation)
+		}
+	}
+	pruneUnset(u)
+	return u, nil
+}
+
+// Returns the configuration last applied to the live object, nil when it was never applied
+func lastAppliedConfiguration(live Unstructured) (map[string]interface{}, error) {
+	lastApplied := live.GetAnnotations()[LastAppliedConfigAnnotation]
+	if lastApplied == "" {
+		return nil, nil
+	}
+	original := Unstructured{}
+	if err := decodeUnstructured(strings.NewReader(lastApplied), &original); err != nil {
+		return nil, fmt.Errorf("Failed parsing %s annotation - %s", LastAppliedConfigAnnotation, err.Error())
+	}
+	return original, nil
+}
+
+// Returns true in case every field set in desired has the same value in live. Live objects
+// hold fields defaulted by the server, so those are not considered a difference
+func isContainedIn(desired interface{}, live interface{}) bool {
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, value := range d {
+			if !isContainedIn(value, l[key]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok || len(l) != len(d) {
+			return false
+		}
+		for i := range d {
+			if !isContainedIn(d[i], l[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(desired, live)
+}
+
+// Computes a json merge patch taking the live object to the desired configuration. Fields found in the
+// original (last applied) configuration and removed from the desired one are deleted, fields that were
+// never applied (e.g. set by the server or by other clients) are kept. Lists are replaced as a whole
+func threeWayMergePatch(original map[string]interface{}, desired map[string]interface{}, live map[string]interface{}) map[string]interface{} {
+	patch := make(map[string]interface{})
+	for key, desiredValue := range desired {
+		liveValue, inLive := live[key]
+		originalValue, inOriginal := original[key]
+		desiredMap, desiredIsMap := desiredValue.(map[string]interface{})
+		liveMap, liveIsMap := liveValue.(map[string]interface{})
+		if desiredIsMap && liveIsMap {
+			originalMap, _ := originalValue.(map[string]interface{})
+			if nested := threeWayMergePatch(originalMap, desiredMap, liveMap); len(nested) > 0 {
+				patch[key] = nested
+			}
+		} else if !inLive || !isContainedIn(desiredValue, liveValue) ||
+			(inOriginal && !reflect.DeepEqual(originalValue, desiredValue)) {
+			patch[key] = desiredValue
+		}
+	}
+	for key := range original {
+		if _, inDesired := desired[key]; !inDesired {
+			if _, inLive := live[key]; inLive {
+				patch[key] = nil
+			}
+		}
+	}
+	return patch
+}
+
+// Applies the desired configuration of the object (typed, e.g. *v1.Service, or Unstructured) declaratively.
+// A missing object is created, otherwise only the difference between the live object and the desired one is
+// patched, using the configuration last applied to tell the fields removed from the desired configuration from
+// fields set by others. The applied configuration is kept in the LastAppliedConfigAnnotation annotation
+func (c *Client) Apply(obj interface{}) (*ApplyResult, error) {
+	desired, err := desiredConfiguration(obj)
+	if err != nil {
+		return nil, fmt.Errorf("Failed applying object - %s", err.Error())
+	}
+	name := desired.GetName()
+	lastApplied, err := json.Marshal(desired)
+	if err != nil {
+		return nil, fmt.Errorf("Failed formatting %s %s to json - %s", desired.GetKind(), name, err.Error())
+	}
+	SetNestedField(desired, string(lastApplied), "metadata", "annotations", LastAppliedConfigAnnotation)
+
+	kind := manifestKindOf(desired.GetAPIVersion(), desired.GetKind())
+	r := c.manifestResource(manifestObject{object: desired, kind: kind})
+	live, err := r.Get(name)
+	if err != nil {
+		if !IsNotFound(err) {
+			return nil, fmt.Errorf("Failed getting %s %s - %s", desired.GetKind(), name, err.Error())
+		}
+		created, err := r.Create(desired)
+		if err != nil {
+			return nil, fmt.Errorf("Failed creating %s %s - %s", desired.GetKind(), name, err.Error())
+		}
+		return &ApplyResult{Object: created, Operation: ApplyCreated}, nil
+	}
+
+	original, err := lastAppliedConfiguration(live)
+	if err != nil {
+		return nil, fmt.Errorf("Failed applying %s %s - %s", desired.GetKind(), name, err.Error())
+	}
+	patch := threeWayMergePatch(original, desired, live)
+	if len(patch) == 0 {
+		return &ApplyResult{Object: live, Operation: ApplyUnchanged}, nil
+	}
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return nil, fmt.Errorf("Failed formatting patch of %s %s - %s", desired.GetKind(), name, err.Error())
+	}
+	patched, err := r.Patch(name, MergePatchType, data)
+	if err != nil {
+		return nil, fmt.Errorf("Failed patching %s %s - %s", desired.GetKind(), name, err.Error())
+	}
+	return &ApplyResult{Object: patched, Operation: ApplyConfigured, Patch: patch}, nil
+}
@@ -0,0 +1,62 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"encoding/json"
+	"ocopea/kubernetes/client/v1"
+	"strings"
+	"testing"
+)
+
+func parseTestObject(t *testing.T, s string) map[string]interface{} {
+	obj := Unstructured{}
+	if err := decodeUnstructured(strings.NewReader(s), &obj); err != nil {
+		t.Fatal(err)
+	}
+	return obj
+}
+
+func TestThreeWayMergePatch(t *testing.T) {
+	original := parseTestObject(t, `{"metadata":{"labels":{"app":"orcs","tier":"web"}},"spec":{"replicas":1,"ports":[{"port":80}]}}`)
+	desired := parseTestObject(t, `{"metadata":{"labels":{"app":"orcs"}},"spec":{"replicas":2,"ports":[{"port":80}]}}`)
+	live := parseTestObject(t,
+		`{"metadata":{"labels":{"app":"orcs","tier":"web","team":"nazgul"},"uid":"1"},"spec":{"replicas":1,"ports":[{"port":80,"protocol":"TCP"}]}}`)
+
+	patch, _ := json.Marshal(threeWayMergePatch(original, desired, live))
+	expected := `{"metadata":{"labels":{"tier":null}},"spec":{"replicas":2}}`
+	if string(patch) != expected {
+		t.Errorf("expected patch %s, got %s", expected, string(patch))
+	}
+
+	// Without a last applied configuration nothing is deleted
+	patch, _ = json.Marshal(threeWayMergePatch(nil, desired, live))
+	if string(patch) != `{"spec":{"replicas":2}}` {
+		t.Errorf("unexpected patch without original %s", string(patch))
+	}
+
+	if patch := threeWayMergePatch(desired, desired, live); len(patch) != 1 {
+		t.Errorf("expected only replicas to be patched, got %v", patch)
+	}
+}
+
+func TestDesiredConfiguration(t *testing.T) {
+	svc := &v1.Service{ObjectMeta: v1.ObjectMeta{
+		Name:            "orcs",
+		ResourceVersion: "7",
+		Annotations:     map[string]string{LastAppliedConfigAnnotation: "{}"},
+	}}
+	desired, err := desiredConfiguration(svc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := json.Marshal(desired)
+	// Fields the server defaults are set, like in the live object
+	expected := `{"apiVersion":"v1","kind":"Service","metadata":{"name":"orcs"},"spec":{"sessionAffinity":"None","type":"ClusterIP"}}`
+	if string(b) != expected {
+		t.Errorf("expected desired configuration %s, got %s", expected, string(b))
+	}
+
+	if _, err = desiredConfiguration(&v1.Binding{}); err == nil {
+		t.Errorf("expected objects of unknown kind to fail")
+	}
+}
@@ -0,0 +1,142 @@
+// Code generated by typegen from swagger.json. DO NOT EDIT.
+
+// Package v1 holds the types of the apps/v1 api group, Deployments
+package v1
+
+import (
+	"ocopea/kubernetes/client/types"
+	"ocopea/kubernetes/client/unversioned"
+	"ocopea/kubernetes/client/v1"
+)
+
+// Deployment enables declarative updates for Pods and ReplicaSets.
+type Deployment struct {
+	unversioned.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	v1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the desired behavior of the Deployment.
+	Spec DeploymentSpec `json:"spec,omitempty"`
+
+	// Most recently observed status of the Deployment.
+	Status DeploymentStatus `json:"status,omitempty"`
+}
+
+// DeploymentList is a list of Deployments.
+type DeploymentList struct {
+	unversioned.TypeMeta `json:",inline"`
+	// Standard list metadata.
+	unversioned.ListMeta `json:"metadata,omitempty"`
+
+	// Items is the list of Deployments.
+	Items []Deployment `json:"items"`
+}
+
+// DeploymentSpec is the specification of the desired behavior of the Deployment.
+type DeploymentSpec struct {
+	// Minimum number of seconds for which a newly created pod should be ready without any of its container
+	// crashing, for it to be considered available. Defaults to 0 (pod will be considered available as soon
+	// as it is ready)
+	MinReadySeconds int `json:"minReadySeconds,omitempty"`
+
+	// Indicates that the deployment is paused.
+	Paused bool `json:"paused,omitempty"`
+
+	// The maximum time in seconds for a deployment to make progress before it is considered to be failed.
+	// The deployment controller will continue to process failed deployments and a condition with a
+	// ProgressDeadlineExceeded reason will be surfaced in the deployment status. Note that progress will
+	// not be estimated during the time a deployment is paused. Defaults to 600s.
+	ProgressDeadlineSeconds *int `json:"progressDeadlineSeconds,omitempty"`
+
+	// Number of desired pods. This is a pointer to distinguish between explicit zero and not specified.
+	// Defaults to 1.
+	Replicas *int `json:"replicas,omitempty"`
+
+	// The number of old ReplicaSets to retain to allow rollback. This is a pointer to distinguish between
+	// explicit zero and not specified. Defaults to 10.
+	RevisionHistoryLimit *int `json:"revisionHistoryLimit,omitempty"`
+
+	// Label selector for pods. Existing ReplicaSets whose pods are selected by this will be the ones
+	// affected by this deployment. It must match the pod template's labels.
+	Selector *unversioned.LabelSelector `json:"selector"`
+
+	// The deployment strategy to use to replace existing pods with new ones.
+	Strategy DeploymentStrategy `json:"strategy,omitempty"`
+
+	// Template describes the pods that will be created. The only allowed template.spec.restartPolicy value
+	// is "Always".
+	Template v1.PodTemplateSpec `json:"template"`
+}
+
+// DeploymentStrategy describes how to replace existing pods with new ones.
+type DeploymentStrategy struct {
+	// Rolling update config params. Present only if DeploymentStrategyType = RollingUpdate.
+	RollingUpdate *RollingUpdateDeployment `json:"rollingUpdate,omitempty"`
+
+	// Type of deployment. Can be "Recreate" or "RollingUpdate". Default is RollingUpdate.
+	Type string `json:"type,omitempty"`
+}
+
+// Spec to control the desired behavior of rolling update.
+type RollingUpdateDeployment struct {
+	// The maximum number of pods that can be scheduled above the desired number of pods. Value can be an
+	// absolute number (ex: 5) or a percentage of desired pods (ex: 10%). This can not be 0 if
+	// MaxUnavailable is 0. Absolute number is calculated from percentage by rounding up. Defaults to 25%.
+	MaxSurge *types.IntOrString `json:"maxSurge,omitempty"`
+
+	// The maximum number of pods that can be unavailable during the update. Value can be an absolute
+	// number (ex: 5) or a percentage of desired pods (ex: 10%). Absolute number is calculated from
+	// percentage by rounding down. This can not be 0 if MaxSurge is 0. Defaults to 25%.
+	MaxUnavailable *types.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// DeploymentStatus is the most recently observed status of the Deployment.
+type DeploymentStatus struct {
+	// Total number of available pods (ready for at least minReadySeconds) targeted by this deployment.
+	AvailableReplicas int `json:"availableReplicas,omitempty"`
+
+	// Count of hash collisions for the Deployment. The Deployment controller uses this field as a
+	// collision avoidance mechanism when it needs to create the name for the newest ReplicaSet.
+	CollisionCount *int `json:"collisionCount,omitempty"`
+
+	// Represents the latest available observations of a deployment's current state.
+	Conditions []DeploymentCondition `json:"conditions,omitempty"`
+
+	// The generation observed by the deployment controller.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// readyReplicas is the number of pods targeted by this Deployment with a Ready Condition.
+	ReadyReplicas int `json:"readyReplicas,omitempty"`
+
+	// Total number of non-terminated pods targeted by this deployment (their labels match the selector).
+	Replicas int `json:"replicas,omitempty"`
+
+	// Total number of unavailable pods targeted by this deployment. This is the total number of pods that
+	// are still required for the deployment to have 100% available capacity. They may either be pods that
+	// are running but not yet available or pods that still have not been created.
+	UnavailableReplicas int `json:"unavailableReplicas,omitempty"`
+
+	// Total number of non-terminated pods targeted by this deployment that have the desired template spec.
+	UpdatedReplicas int `json:"updatedReplicas,omitempty"`
+}
+
+// DeploymentCondition describes the state of a deployment at a certain point.
+type DeploymentCondition struct {
+	// Last time the condition transitioned from one status to another.
+	LastTransitionTime unversioned.Time `json:"lastTransitionTime,omitempty"`
+
+	// The last time this condition was updated.
+	LastUpdateTime unversioned.Time `json:"lastUpdateTime,omitempty"`
+
+	// A human readable message indicating details about the transition.
+	Message string `json:"message,omitempty"`
+
+	// The reason for the condition's last transition.
+	Reason string `json:"reason,omitempty"`
+
+	// Type of deployment condition.
+	Type string `json:"type"`
+
+	// Status of the condition, one of True, False, Unknown.
+	Status string `json:"status"`
+}
@@ -0,0 +1,372 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CredentialProvider supplies the credentials of requests to the api server. Providers may refresh their credentials,
+// e.g. tokens that expire, see ExecCredentialProvider, OIDCTokenProvider and FileTokenProvider
+type CredentialProvider interface {
+	// Sets the credentials on a request, e.g. the Authorization header
+	Authenticate(req *http.Request) error
+
+	// Called when the api server rejected the credentials, providers drop the credentials they cached so the
+	// following requests use fresh ones
+	Invalidate()
+}
+
+// ImpersonationConfig identifies the user requests are sent on behalf of. The credentials of the client must be
+// allowed to impersonate the user and groups (the impersonate verb on users and groups)
+type ImpersonationConfig struct {
+	UserName string
+	Groups   []string
+
+	// Extra fields of the user, e.g. scopes
+	Extra map[string][]string
+}
+
+// Sets the credentials of the client on a request to the api server, along with the impersonation headers
+func (c *Client) authenticate(req *http.Request) error {
+	if c.Credentials != nil {
+		if err := c.Credentials.Authenticate(req); err != nil {
+			return fmt.Errorf("Failed getting credentials - %s", err.Error())
+		}
+	} else if len(c.SslToken) > 0 {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.SslToken))
+	} else if len(c.UserName) > 0 {
+		req.SetBasicAuth(c.UserName, c.Password)
+	}
+
+	if c.Impersonation != nil {
+		req.Header.Set("Impersonate-User", c.Impersonation.UserName)
+		for _, group := range c.Impersonation.Groups {
+			req.Header.Add("Impersonate-Group", group)
+		}
+		for key, values := range c.Impersonation.Extra {
+			for _, value := range values {
+				req.Header.Add("Impersonate-Extra-"+url.PathEscape(key), value)
+			}
+		}
+	}
+	return nil
+}
+
+// Returns a copy of the client sending requests on behalf of the user, so cluster RBAC and audit logs reflect
+// the user rather than the credentials of the client. The copy shares the credentials of the client
+func (c *Client) Impersonate(userName string, groups []string) ClientInterface {
+	impersonated := *c
+	impersonated.Impersonation = &ImpersonationConfig{UserName: userName, Groups: groups}
+	return &impersonated
+}
+
+// StaticTokenProvider authenticates with a bearer token that never changes
+type StaticTokenProvider struct {
+	Token string
+}
+
+func (p *StaticTokenProvider) Authenticate(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+p.Token)
+	return nil
+}
+
+func (p *StaticTokenProvider) Invalidate() {}
+
+// BasicAuthProvider authenticates with a user name and password
+type BasicAuthProvider struct {
+	UserName string
+	Password string
+}
+
+func (p *BasicAuthProvider) Authenticate(req *http.Request) error {
+	req.SetBasicAuth(p.UserName, p.Password)
+	return nil
+}
+
+func (p *BasicAuthProvider) Invalidate() {}
+
+// Tokens are refreshed a bit before they expire, so requests in flight don't carry an expired token
+const tokenExpiryMargin = 30 * time.Second
+
+// cachedToken keeps a bearer token until it expires, fetching a new one when needed
+type cachedToken struct {
+	lock   sync.Mutex
+	token  string
+	expiry time.Time
+
+	// Fetches a new token along with its expiry, a zero expiry means the token does not expire
+	fetch func() (string, time.Time, error)
+}
+
+func (t *cachedToken) authenticate(req *http.Request) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.token == "" || (!t.expiry.IsZero() && time.Now().Add(tokenExpiryMargin).After(t.expiry)) {
+		token, expiry, err := t.fetch()
+		if err != nil {
+			return err
+		}
+		t.token, t.expiry = token, expiry
+	}
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	return nil
+}
+
+func (t *cachedToken) invalidate() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.token = ""
+}
+
+// The ExecCredential objects exec credential plugins read and print
+type execCredential struct {
+	APIVersion string                `json:"apiVersion"`
+	Kind       string                `json:"kind"`
+	Spec       execCredentialSpec    `json:"spec"`
+	Status     *execCredentialStatus `json:"status,omitempty"`
+}
+
+type execCredentialSpec struct {
+	Interactive bool `json:"interactive"`
+}
+
+type execCredentialStatus struct {
+	Token               string `json:"token,omitempty"`
+	ExpirationTimestamp string `json:"expirationTimestamp,omitempty"`
+}
+
+// ExecCredentialProvider authenticates with the token printed by an exec credential plugin, the same plugins
+// kubectl runs (e.g. aws-iam-authenticator). The plugin is run again once the token expires
+type ExecCredentialProvider struct {
+	Command string
+	Args    []string
+
+	// Environment variables set for the plugin in addition to the environment of the process, e.g. AWS_PROFILE=ocopea
+	Env []string
+
+	APIVersion string
+	cache      cachedToken
+}
+
+// Constructs a provider running the plugin command with the args, speaking client.authentication.k8s.io/v1beta1
+func NewExecCredentialProvider(command string, args ...string) *ExecCredentialProvider {
+	p := &ExecCredentialProvider{Command: command, Args: args, APIVersion: "client.authentication.k8s.io/v1beta1"}
+	p.cache.fetch = p.run
+	return p
+}
+
+func (p *ExecCredentialProvider) Authenticate(req *http.Request) error {
+	return p.cache.authenticate(req)
+}
+
+func (p *ExecCredentialProvider) Invalidate() {
+	p.cache.invalidate()
+}
+
+// Runs the plugin, returning the token it printed
+func (p *ExecCredentialProvider) run() (string, time.Time, error) {
+	execInfo, err := json.Marshal(execCredential{APIVersion: p.APIVersion, Kind: "ExecCredential"})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	cmd := exec.Command(p.Command, p.Args...)
+	cmd.Env = append(append(os.Environ(), p.Env...), "KUBERNETES_EXEC_INFO="+string(execInfo))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("Failed running credential plugin %s - %s %s", p.Command, err.Error(), stderr.String())
+	}
+
+	var credential execCredential
+	if err = json.Unmarshal(output, &credential); err != nil {
+		return "", time.Time{}, fmt.Errorf("Failed parsing output of credential plugin %s - %s", p.Command, err.Error())
+	}
+	if credential.Kind != "ExecCredential" || credential.Status == nil || credential.Status.Token == "" {
+		return "", time.Time{}, fmt.Errorf("credential plugin %s did not return an ExecCredential with a token", p.Command)
+	}
+	var expiry time.Time
+	if credential.Status.ExpirationTimestamp != "" {
+		if expiry, err = time.Parse(time.RFC3339, credential.Status.ExpirationTimestamp); err != nil {
+			return "", time.Time{}, fmt.Errorf("Failed parsing token expiry of credential plugin %s - %s", p.Command, err.Error())
+		}
+	}
+	return credential.Status.Token, expiry, nil
+}
+
+// OIDCTokenProvider authenticates with an OpenID Connect id token, using the refresh token to get a new id token
+// from the issuer once the id token expires, like the oidc auth provider of kubectl
+type OIDCTokenProvider struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+
+	// The refresh token is replaced in case the issuer rotates refresh tokens
+	RefreshToken string
+
+	httpClient *http.Client
+	cache      cachedToken
+}
+
+// Constructs a provider starting with the id token, which may be empty to refresh on the first request
+func NewOIDCTokenProvider(issuerURL string, clientID string, clientSecret string, idToken string, refreshToken string) *OIDCTokenProvider {
+	p := &OIDCTokenProvider{
+		IssuerURL:    issuerURL,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RefreshToken: refreshToken,
+		httpClient:   &http.Client{Timeout: 30 * time.Second},
+	}
+	p.cache.fetch = p.refresh
+	if idToken != "" {
+		p.cache.token = idToken
+		p.cache.expiry, _ = jwtExpiry(idToken)
+	}
+	return p
+}
+
+func (p *OIDCTokenProvider) Authenticate(req *http.Request) error {
+	return p.cache.authenticate(req)
+}
+
+func (p *OIDCTokenProvider) Invalidate() {
+	p.cache.invalidate()
+}
+
+// Returns the expiry found in the exp claim of a json web token
+func jwtExpiry(token string) (time.Time, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, fmt.Errorf("invalid json web token")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Failed decoding json web token payload - %s", err.Error())
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err = json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}, fmt.Errorf("Failed parsing json web token claims - %s", err.Error())
+	}
+	if claims.Exp == 0 {
+		return time.Time{}, nil
+	}
+	return time.Unix(claims.Exp, 0), nil
+}
+
+// Discovers the token endpoint of the issuer
+func (p *OIDCTokenProvider) tokenEndpoint() (string, error) {
+	resp, err := p.httpClient.Get(strings.TrimSuffix(p.IssuerURL, "/") + "/.well-known/openid-configuration")
+	if err != nil {
+		return "", fmt.Errorf("Failed discovering oidc issuer %s - %s", p.IssuerURL, err.Error())
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Failed discovering oidc issuer %s, received status %s", p.IssuerURL, resp.Status)
+	}
+	var configuration struct {
+		TokenEndpoint string `json:"token_endpoint"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&configuration); err != nil || configuration.TokenEndpoint == "" {
+		return "", fmt.Errorf("oidc issuer %s did not return a token endpoint", p.IssuerURL)
+	}
+	return configuration.TokenEndpoint, nil
+}
+
+// Exchanges the refresh token for a new id token
+func (p *OIDCTokenProvider) refresh() (string, time.Time, error) {
+	if p.RefreshToken == "" {
+		return "", time.Time{}, fmt.Errorf("oidc id token expired and no refresh token is available")
+	}
+	tokenEndpoint, err := p.tokenEndpoint()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {p.RefreshToken},
+		"client_id":     {p.ClientID},
+	}
+	if p.ClientSecret != "" {
+		form.Set("client_secret", p.ClientSecret)
+	}
+	resp, err := p.httpClient.PostForm(tokenEndpoint, form)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("Failed refreshing oidc token - %s", err.Error())
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", time.Time{}, fmt.Errorf("Failed refreshing oidc token, received status %s", resp.Status)
+	}
+
+	var tokens struct {
+		IDToken      string `json:"id_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int64  `json:"expires_in"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&tokens); err != nil {
+		return "", time.Time{}, fmt.Errorf("Failed parsing oidc token response - %s", err.Error())
+	}
+	if tokens.IDToken == "" {
+		return "", time.Time{}, fmt.Errorf("oidc token response has no id token")
+	}
+	if tokens.RefreshToken != "" {
+		p.RefreshToken = tokens.RefreshToken
+	}
+	expiry, err := jwtExpiry(tokens.IDToken)
+	if (err != nil || expiry.IsZero()) && tokens.ExpiresIn > 0 {
+		expiry = time.Now().Add(time.Duration(tokens.ExpiresIn) * time.Second)
+	}
+	return tokens.IDToken, expiry, nil
+}
+
+// FileTokenProvider authenticates with the token found in a file, e.g. the service account token mounted in pods.
+// The file is watched for changes and read again whenever it changes, since kubernetes rotates mounted tokens
+type FileTokenProvider struct {
+	Path string
+
+	lock    sync.Mutex
+	token   string
+	modTime time.Time
+}
+
+func NewFileTokenProvider(path string) *FileTokenProvider {
+	return &FileTokenProvider{Path: path}
+}
+
+func (p *FileTokenProvider) Authenticate(req *http.Request) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	info, err := os.Stat(p.Path)
+	if err != nil {
+		return fmt.Errorf("Failed reading token file %s - %s", p.Path, err.Error())
+	}
+	if p.token == "" || !info.ModTime().Equal(p.modTime) {
+		content, err := ioutil.ReadFile(p.Path)
+		if err != nil {
+			return fmt.Errorf("Failed reading token file %s - %s", p.Path, err.Error())
+		}
+		p.token = strings.TrimSpace(string(content))
+		p.modTime = info.ModTime()
+	}
+	req.Header.Set("Authorization", "Bearer "+p.token)
+	return nil
+}
+
+func (p *FileTokenProvider) Invalidate() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.token = ""
+}
@@ -0,0 +1,161 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Returns an unsigned json web token expiring at the given time
+func testJWT(expiry time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"sub":"ocopea","exp":%d}`, expiry.Unix())))
+	return "eyJhbGciOiJub25lIn0." + payload + ".c2ln"
+}
+
+func authorizationOf(t *testing.T, provider CredentialProvider) string {
+	req, _ := http.NewRequest("GET", "http://kubernetes/api/v1", nil)
+	if err := provider.Authenticate(req); err != nil {
+		t.Fatal(err)
+	}
+	return req.Header.Get("Authorization")
+}
+
+func TestImpersonate(t *testing.T) {
+	var headers http.Header
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+		w.Write([]byte(`{"items":[]}`))
+	}))
+	defer s.Close()
+
+	c, err := NewClientWithCredentials(s.URL, "ocopea", &StaticTokenProvider{Token: "psb-token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	impersonated := c.Impersonate("alice", []string{"developers", "ocopea-users"})
+	if _, err = impersonated.ListPodsInfo(nil); err != nil {
+		t.Fatal(err)
+	}
+	if headers.Get("Authorization") != "Bearer psb-token" || headers.Get("Impersonate-User") != "alice" ||
+		!reflect.DeepEqual(headers["Impersonate-Group"], []string{"developers", "ocopea-users"}) {
+		t.Errorf("unexpected impersonation headers %v", headers)
+	}
+
+	if _, err = c.ListPodsInfo(nil); err != nil {
+		t.Fatal(err)
+	}
+	if headers.Get("Impersonate-User") != "" {
+		t.Errorf("expected the original client not to impersonate, got %v", headers)
+	}
+}
+
+func TestExecCredentialProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "exec-credential")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// The plugin counts its runs, printing a token that expires right away on the first run
+	counter := filepath.Join(dir, "runs")
+	plugin := filepath.Join(dir, "plugin.sh")
+	script := `#!/bin/sh
+printf x >> ` + counter + `
+runs=$(wc -c < ` + counter + ` | tr -d ' ')
+case "$KUBERNETES_EXEC_INFO" in *ExecCredential*) ;; *) exit 1 ;; esac
+if [ "$runs" = "1" ]; then expiry="` + time.Now().Format(time.RFC3339) + `"; else expiry="` + time.Now().Add(time.Hour).Format(time.RFC3339) + `"; fi
+echo "{\"apiVersion\":\"client.authentication.k8s.io/v1beta1\",\"kind\":\"ExecCredential\",\"status\":{\"token\":\"token-$runs\",\"expirationTimestamp\":\"$expiry\"}}"
+`
+	if err = ioutil.WriteFile(plugin, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	provider := NewExecCredentialProvider(plugin)
+	if token := authorizationOf(t, provider); token != "Bearer token-1" {
+		t.Errorf("expected the token of the first run, got %s", token)
+	}
+	// The first token expired, so the plugin is run again, the second one is cached until invalidated
+	for _, expected := range []string{"Bearer token-2", "Bearer token-2"} {
+		if token := authorizationOf(t, provider); token != expected {
+			t.Errorf("expected %s, got %s", expected, token)
+		}
+	}
+	provider.Invalidate()
+	if token := authorizationOf(t, provider); token != "Bearer token-3" {
+		t.Errorf("expected a new token after invalidating, got %s", token)
+	}
+}
+
+func TestOIDCTokenProvider(t *testing.T) {
+	refreshedToken := testJWT(time.Now().Add(time.Hour))
+	var issuer *httptest.Server
+	issuer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			fmt.Fprintf(w, `{"issuer":%q,"token_endpoint":%q}`, issuer.URL, issuer.URL+"/token")
+		case "/token":
+			if r.PostFormValue("grant_type") != "refresh_token" || r.PostFormValue("refresh_token") != "refresh-1" ||
+				r.PostFormValue("client_id") != "ocopea" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fmt.Fprintf(w, `{"id_token":%q,"refresh_token":"refresh-2","expires_in":3600}`, refreshedToken)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer issuer.Close()
+
+	// The id token is used until it expires
+	idToken := testJWT(time.Now().Add(time.Hour))
+	provider := NewOIDCTokenProvider(issuer.URL, "ocopea", "", idToken, "refresh-1")
+	if token := authorizationOf(t, provider); token != "Bearer "+idToken {
+		t.Errorf("expected the initial id token, got %s", token)
+	}
+
+	provider = NewOIDCTokenProvider(issuer.URL, "ocopea", "", testJWT(time.Now().Add(-time.Minute)), "refresh-1")
+	if token := authorizationOf(t, provider); token != "Bearer "+refreshedToken {
+		t.Errorf("expected the expired id token to be refreshed, got %s", token)
+	}
+	if provider.RefreshToken != "refresh-2" {
+		t.Errorf("expected the rotated refresh token to be kept, got %s", provider.RefreshToken)
+	}
+}
+
+func TestFileTokenProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "token-file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "token")
+	if err = ioutil.WriteFile(path, []byte("token-1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	provider := NewFileTokenProvider(path)
+	if token := authorizationOf(t, provider); token != "Bearer token-1" {
+		t.Errorf("expected the token of the file, got %s", token)
+	}
+
+	// Rotating the token, the modification time is moved explicitly since file systems may have a coarse resolution
+	if err = ioutil.WriteFile(path, []byte("token-2"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	rotated := time.Now().Add(time.Minute)
+	if err = os.Chtimes(path, rotated, rotated); err != nil {
+		t.Fatal(err)
+	}
+	if token := authorizationOf(t, provider); !strings.HasSuffix(token, "token-2") {
+		t.Errorf("expected the rotated token, got %s", token)
+	}
+}
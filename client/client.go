@@ -10,6 +10,7 @@ package client
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
@@ -21,6 +22,7 @@ import (
 	"net/http"
 	"ocopea/kubernetes/client/types"
 	"ocopea/kubernetes/client/v1"
+	"ocopea/kubernetes/client/validation"
 	"time"
 )
 
@@ -31,6 +33,22 @@ type Client struct {
 	SslToken   string
 	UserName   string
 	Password   string
+
+	// Objects are validated before being submitted unless validation is skipped
+	SkipValidation bool
+
+	// Supplies the credentials of requests when set, instead of SslToken or UserName and Password
+	Credentials CredentialProvider
+
+	// Requests are sent on behalf of the impersonated user when set, see Impersonate
+	Impersonation *ImpersonationConfig
+
+	// Mutating calls are recorded along with the previous state of the objects they change when set, see Undo
+	Journal JournalSink
+
+	// Pods and events are listed and watched in the protobuf wire format when set, which is much cheaper to decode
+	// than json. Api servers not supporting it respond with json
+	Protobuf bool
 }
 
 // Constructs a new client object
@@ -55,39 +73,79 @@ func NewClient(url string, namespace string, userName string, password string, c
 		caCertPool.AppendCertsFromPEM(caCert)
 	}
 
+	c := &Client{Url: url, Namespace: namespace, httpClient: newHttpClient(), UserName: userName, Password: password, SslToken: sslToken}
+	if err := c.testConnection(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Constructs a new client object authenticating with the credential provider, e.g. a FileTokenProvider
+// reading the rotated service account token
+func NewClientWithCredentials(url string, namespace string, credentials CredentialProvider) (*Client, error) {
+	c := &Client{Url: url, Namespace: namespace, httpClient: newHttpClient(), Credentials: credentials}
+	if err := c.testConnection(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func newHttpClient() http.Client {
 	tr := &http.Transport{
 		TLSClientConfig: &tls.Config{
 			InsecureSkipVerify: true,
 		},
 	}
-	httpClient := http.Client{Transport: tr}
-	c := &Client{Url: url, Namespace: namespace, httpClient: httpClient, UserName: userName, Password: password, SslToken: sslToken}
+	return http.Client{Transport: tr}
+}
 
+func (c *Client) testConnection() error {
 	r, err := c.doHttpNoNS("GET", "", nil)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	defer r.Body.Close()
 	if r.StatusCode == 200 {
-		return c, nil
+		return nil
 	} else {
-		return nil, errors.New(fmt.Sprintf("Failed testing k8s connection, received status %s", r.Status))
+		return errors.New(fmt.Sprintf("Failed testing k8s connection, received status %s", r.Status))
 	}
 }
 
 func (c *Client) CreateNamespace(ns *v1.Namespace, force bool) (*v1.Namespace, error) {
 	respNs := &v1.Namespace{}
+	if err := c.validate("namespace", ns.Name, validation.ValidateNamespace(ns)); err != nil {
+		return respNs, err
+	}
 	err := c.createEntity("namespaces", ns.Name, ns, respNs, force)
 	return respNs, err
 }
 
 func (c *Client) CreateReplicationController(rc *v1.ReplicationController, force bool) (*v1.ReplicationController, error) {
 	respRc := &v1.ReplicationController{}
+	if err := c.validate("replication controller", rc.Name, validation.ValidateReplicationController(rc)); err != nil {
+		return respRc, err
+	}
 	err := c.createEntity("replicationcontrollers", rc.Name, rc, respRc, force)
 	return respRc, err
 }
 
+// Fails with a readable error in case validation found invalid fields, so they won't end up as a 422 from the server
+func (c *Client) validate(kind string, name string, errs validation.ErrorList) error {
+	if c.SkipValidation {
+		return nil
+	}
+	return validationError(kind, name, errs)
+}
+
+func validationError(kind string, name string, errs validation.ErrorList) error {
+	if err := errs.ToError(); err != nil {
+		return fmt.Errorf("invalid %s %s - %s", kind, name, err.Error())
+	}
+	return nil
+}
+
 func (c *Client) structToReader(s interface{}) (io.Reader, error) {
 	b := new(bytes.Buffer)
 	enc := json.NewEncoder(b)
@@ -117,6 +175,8 @@ func isEntityTypeNamespaceLevel(entityTypeName string) bool {
 	switch entityTypeName {
 	case "namespaces":
 		fallthrough
+	case "nodes":
+		fallthrough
 	case "persistentvolumes":
 		return false
 	default:
@@ -149,7 +209,7 @@ func (c *Client) createEntity(entityTypeName string, entityName string, entityTo
 
 		// We support create force meaning we are fine if already exist
 		if resp.StatusCode == http.StatusConflict && force {
-			log.Printf("conflict creating " + resourceName + ", force mode, getting info only")
+			log.Println("conflict creating " + resourceName + ", force mode, getting info only")
 			err = c.getEntityInfo(entityTypeName, entityName, responseEntityPtr)
 			if err != nil {
 				return fmt.Errorf("resource %s already exist but failed reading info of the existing entity - %s", resourceName, err.Error())
@@ -179,23 +239,68 @@ func (c *Client) createEntity(entityTypeName string, entityName string, entityTo
 
 }
 
+func (c *Client) updateEntity(entityTypeName string, entityName string, entityToUpdatePtr interface{}, responseEntityPtr interface{}) error {
+	httpMethod := "PUT"
+	resourceName := entityTypeName + "/" + entityName
+
+	r, err := c.structToReader(entityToUpdatePtr)
+	if err != nil {
+		return fmt.Errorf("Failed formatting entity %s to json - %s", resourceName, err.Error())
+	}
+	var resp *http.Response
+	if isEntityTypeNamespaceLevel(entityTypeName) {
+		resp, err = c.doHttp(httpMethod, resourceName, r)
+	} else {
+		resp, err = c.doHttpNoNS(httpMethod, resourceName, r)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Failed updating k8s entity %s - %s", resourceName, err.Error())
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		contents, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("http error updating %s - %s - %s", resourceName, resp.Status, contents)
+	}
+
+	dec := json.NewDecoder(resp.Body)
+	return dec.Decode(responseEntityPtr)
+}
+
 func (c *Client) CreateService(svc *v1.Service, force bool) (*v1.Service, error) {
 	respSvc := &v1.Service{}
+	if err := c.validate("service", svc.Name, validation.ValidateService(svc)); err != nil {
+		return respSvc, err
+	}
 	err := c.createEntity("services", svc.Name, svc, respSvc, force)
 	return respSvc, err
 }
 
 func (c *Client) CreatePersistentVolume(pv *v1.PersistentVolume, force bool) (*v1.PersistentVolume, error) {
 	respPv := &v1.PersistentVolume{}
+	if err := c.validate("persistent volume", pv.Name, validation.ValidatePersistentVolume(pv)); err != nil {
+		return respPv, err
+	}
 	err := c.createEntity("persistentvolumes", pv.Name, pv, respPv, force)
 	return respPv, err
 }
 
+func (c *Client) CreatePersistentVolumeClaim(pvc *v1.PersistentVolumeClaim, force bool) (*v1.PersistentVolumeClaim, error) {
+	respPvc := &v1.PersistentVolumeClaim{}
+	if err := c.validate("persistent volume claim", pvc.Name, validation.ValidatePersistentVolumeClaim(pvc)); err != nil {
+		return respPvc, err
+	}
+	err := c.createEntity("persistentvolumeclaims", pvc.Name, pvc, respPvc, force)
+	return respPvc, err
+}
+
 func (c *Client) ListPodsInfo(labelFilters map[string]string) ([]*v1.Pod, error) {
 
 	respPodList := &v1.PodList{}
-	err := c.getEntityInfo("pods"+buildLabelsQueryString(labelFilters), "", respPodList)
-	// Listing all services in namespace
+	path := "/api/v1/namespaces/" + c.Namespace + "/pods" + buildLabelsQueryString(labelFilters)
+	err := c.list(path, respPodList, &respPodList.TypeMeta)
 	if err != nil {
 		return nil, fmt.Errorf("Failed listing k8s pods - %s", err.Error())
 	}
@@ -208,23 +313,9 @@ func (c *Client) ListPodsInfo(labelFilters map[string]string) ([]*v1.Pod, error)
 	return podList, nil
 }
 
+// Lists the events of an entity, sorted by the time they last occurred at
 func (c *Client) ListEntityEvents(entityUid types.UID) ([]*v1.Event, error) {
-
-	respEventList := &v1.EventList{}
-	err := c.getEntityInfo(fmt.Sprintf("events?fieldSelector=involvedObject.uid=%s", entityUid), "", respEventList)
-
-	// Listing all events for entity uid
-	if err != nil {
-		return nil, err
-	}
-
-	eventList := make([]*v1.Event, 0)
-	if respEventList.Items != nil {
-		for i := range respEventList.Items {
-			eventList = append(eventList, &respEventList.Items[i])
-		}
-	}
-	return eventList, nil
+	return c.ListEvents(EventQuery{InvolvedObjectUID: entityUid})
 }
 
 func buildLabelsQueryString(labelFilters map[string]string) string {
@@ -325,12 +416,18 @@ func (c *Client) GetPersistentVolumeInfo(persistentVolumeName string) (*v1.Persi
 	log.Println(string(str))
 
 	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("Failed getting k8s pv info for pv %s - %s", persistentVolumeName, respPv)
+		return nil, fmt.Errorf("Failed getting k8s pv info for pv %s - %v", persistentVolumeName, respPv)
 	}
 
 	return &respPv, nil
 }
 
+func (c *Client) GetPersistentVolumeClaimInfo(pvcName string) (*v1.PersistentVolumeClaim, error) {
+	pvc := &v1.PersistentVolumeClaim{}
+	err := c.getEntityInfo("persistentvolumeclaims", pvcName, pvc)
+	return pvc, err
+}
+
 func (c *Client) GetReplicationControllerInfo(rcName string) (*v1.ReplicationController, error) {
 	rc := &v1.ReplicationController{}
 	err := c.getEntityInfo("replicationcontrollers", rcName, rc)
@@ -484,39 +581,62 @@ func (c *Client) DeleteNamespaceAndWaitForTermination(nsName string, maxRetries
 	if err != nil {
 		return err
 	}
-	nsStillTerminating := true
-
-	for retries := maxRetries; nsStillTerminating && retries > 0; retries-- {
-		time.Sleep(sleepDuration)
-		log.Printf("Waiting for namespace %s to vanish, %d/%d\n", nsName, maxRetries-retries, maxRetries)
-		nsStillTerminating, err = c.CheckNamespaceExist(nsName)
-		if err != nil {
-			return err
-		}
-	}
 
-	// If service did not start by now, fail the deployment
-	if nsStillTerminating {
+	_, err = WaitFor(context.Background(), c.NamespaceGetter(nsName), NamespaceDeleted, WaitOptions{
+		Description: "namespace " + nsName + " to vanish",
+		Timeout:     time.Duration(maxRetries) * sleepDuration,
+		Interval:    sleepDuration,
+		Watch:       c.WatchTrigger(coreResource("namespaces"), false, nil),
+		Progress:    logWaitProgress("Waiting for namespace " + nsName + " to vanish"),
+	})
+	if IsWaitTimeout(err) {
 		return fmt.Errorf("Namespace %s failed to terminate even after %d retries", nsName, maxRetries)
 	}
+	return err
+}
 
-	return nil
+// Returns a progress callback logging the given message along with the attempt number and the time waited so far
+func logWaitProgress(message string) func(obj interface{}, attempt int, elapsed time.Duration) {
+	return func(obj interface{}, attempt int, elapsed time.Duration) {
+		log.Printf("%s, attempt %d, %s\n", message, attempt, elapsed.Round(time.Second))
+	}
 }
 
 func (c *Client) DeleteNamespace(nsName string) error {
 	return c.deleteEntity("namespaces/" + nsName)
 }
 
+// Deletes the replication controller along with its pods, see DeleteReplicationControllerWithOptions
 func (c *Client) DeleteReplicationController(rcName string) error {
-	return c.deleteEntity("namespaces/" + c.Namespace + "/" + "replicationcontrollers/" + rcName)
+	return c.DeleteReplicationControllerWithOptions(rcName, nil)
 }
 func (c *Client) DeleteService(serviceName string) error {
 	return c.deleteEntity("namespaces/" + c.Namespace + "/" + "services/" + serviceName)
 }
 
+func (c *Client) DeletePersistentVolumeClaim(pvcName string) error {
+	return c.deleteEntity("namespaces/" + c.Namespace + "/" + "persistentvolumeclaims/" + pvcName)
+}
+
 func (c *Client) deleteEntity(relativeUrl string) error {
+	return c.deleteEntityWithOptions(relativeUrl, nil)
+}
+
+// Deletes the entity, options are sent as the request body when given
+func (c *Client) deleteEntityWithOptions(relativeUrl string, options *v1.DeleteOptions) error {
 	httpMethod := "DELETE"
-	resp, err := c.doHttpNoNS(httpMethod, relativeUrl, nil)
+	var body io.Reader
+	if options != nil {
+		typedOptions := *options
+		typedOptions.Kind = "DeleteOptions"
+		typedOptions.APIVersion = "v1"
+		r, err := c.structToReader(&typedOptions)
+		if err != nil {
+			return fmt.Errorf("Failed formatting delete options for %s - %s", relativeUrl, err.Error())
+		}
+		body = r
+	}
+	resp, err := c.doHttpNoNS(httpMethod, relativeUrl, body)
 	if err != nil {
 		return fmt.Errorf("Failed deleting %s - %s", relativeUrl, err.Error())
 	}
@@ -546,89 +666,66 @@ func (c *Client) doHttp(method string, resource string, r io.Reader) (*http.Resp
 }
 
 func (c *Client) doHttpNoNS(method string, resource string, r io.Reader) (*http.Response, error) {
-	req, err := http.NewRequest(method, c.Url+"/api/v1/"+resource, r)
-	if err != nil {
-		return nil, fmt.Errorf("failed %s request on %s - %s", method, resource, err.Error())
-	}
+	return c.doHttpPath(method, "/api/v1/"+resource, r, "application/json")
+}
 
-	// Setting authentication
-	if len(c.SslToken) > 0 {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.SslToken))
-	} else if len(c.UserName) > 0 {
-		req.SetBasicAuth(c.UserName, c.Password)
+// Sends a request to the given path of the api server, path is relative to the client url (e.g. /apis/batch/v1/jobs).
+// Mutating requests are recorded in the journal of the client when set
+func (c *Client) doHttpPath(method string, resource string, r io.Reader, contentType string) (*http.Response, error) {
+	if c.Journal != nil {
+		if target := journalTargetOf(method, resource); target != nil {
+			return c.doJournaledHttp(target, method, resource, r, contentType)
+		}
 	}
+	return c.sendHttp(method, resource, r, contentType)
+}
 
-	req.Header.Set("Content-Type", "application/json")
-
-	response, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	log.Printf("%s on %s returned %d\n", method, resource, response.StatusCode)
-	return response, err
+func (c *Client) sendHttp(method string, resource string, r io.Reader, contentType string) (*http.Response, error) {
+	return c.sendHttpAccepting(method, resource, r, contentType, "")
 }
 
-func (c *Client) RunOneOffTask(name string, containerName string, additionalVars []v1.EnvVar) error {
-	// Building rc spec
+// Sends a request asking for a response in one of the accepted media types, the server default is used when empty
+func (c *Client) sendHttpAccepting(
+	method string,
+	resource string,
+	r io.Reader,
+	contentType string,
+	accept string) (*http.Response, error) {
 
-	pod := &v1.Pod{}
-	pod.ObjectMeta = v1.ObjectMeta{Name: name}
-	pod.ObjectMeta.Labels = make(map[string]string)
-	pod.Labels["app"] = "bootstrap"
-	pod.ObjectMeta.Labels["nazKind"] = "sys"
-
-	containerSpec := v1.Container{}
-	containerSpec.Name = "bootstrap"
-	containerSpec.Image = containerName
-	containerSpec.ImagePullPolicy = v1.PullIfNotPresent
-	containerSpec.Ports = []v1.ContainerPort{{ContainerPort: 8000}}
-	containerSpec.Env = additionalVars
-	containers := []v1.Container{containerSpec}
-
-	pod.Spec = v1.PodSpec{RestartPolicy: v1.RestartPolicyNever}
-	pod.Spec.Containers = containers
-
-	// The pod
-	createdPod, err := c.CreatePod(pod, false)
+	req, err := http.NewRequest(method, c.Url+resource, r)
 	if err != nil {
-		return fmt.Errorf("Failed creating task pod %s - %s", name, err.Error())
+		return nil, fmt.Errorf("failed %s request on %s - %s", method, resource, err.Error())
 	}
 
-	// Deleting this pod so it won't stay there forever
-	defer c.DeletePod(createdPod.Name)
-
-	// Wait until pod has been launched and finished
-
-	for retries := 60; createdPod.Status.Phase != v1.PodSucceeded &&
-		createdPod.Status.Phase != v1.PodFailed &&
-		retries > 0; retries-- {
-		log.Printf("Waiting task to execute... %d", retries)
-		time.Sleep(3 * time.Second)
-		createdPod, err = c.GetPodInfo(createdPod.Name)
-		if err != nil {
-			return fmt.Errorf("Failed getting task pod %s info - %s", name, err.Error())
-		}
+	if err = c.authenticate(req); err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
 	}
 
-	//todo:tail logs in a go routine as we go...
-	podLog, err := c.GetPodLogs(createdPod.Name)
+	response, err := c.httpClient.Do(req)
 	if err != nil {
-		log.Printf("Failed retreiving task pod %s logs, %s", createdPod.Name, err.Error())
+		return nil, err
 	}
-	fmt.Printf("Task Pod Logs\n%s", string(podLog))
-
-	if createdPod.Status.Phase != v1.PodSucceeded &&
-		createdPod.Status.Phase != v1.PodFailed {
-		return fmt.Errorf("task pod %s failed to finish in a timely fashion", name)
-	} else if createdPod.Status.Phase != v1.PodSucceeded {
-		return fmt.Errorf("task pod %s has miserably failed", name)
+	if response.StatusCode == http.StatusUnauthorized && c.Credentials != nil {
+		c.Credentials.Invalidate()
 	}
+	log.Printf("%s on %s returned %d\n", method, resource, response.StatusCode)
+	return response, err
+}
 
-	return err
+// Runs a bootstrap task pod, see RunTask for streaming the logs and reading the result of the task
+func (c *Client) RunOneOffTask(name string, containerName string, additionalVars []v1.EnvVar) error {
+	return runOneOffTask(c, name, containerName, additionalVars)
 }
 
 func (c *Client) CreatePod(pod *v1.Pod, force bool) (*v1.Pod, error) {
 	respPod := &v1.Pod{}
+	if err := c.validate("pod", pod.Name, validation.ValidatePod(pod)); err != nil {
+		return respPod, err
+	}
 	err := c.createEntity("pods", pod.Name, pod, respPod, force)
 	return respPod, err
 }
@@ -640,20 +737,24 @@ func (c *Client) TestVolume(volumeName string) (bool, *v1.PersistentVolume, erro
 	if err != nil {
 		return false, nil, fmt.Errorf("Failed getting k8s pv for %s - %s", volumeName, err.Error())
 	}
+	ready, err := isVolumeReady(pv)
+	return ready, pv, err
+}
 
+func isVolumeReady(pv *v1.PersistentVolume) (bool, error) {
 	switch pv.Status.Phase {
 	case v1.VolumePending:
-		return false, pv, nil
+		return false, nil
 	case v1.VolumeAvailable:
-		return true, pv, nil
+		return true, nil
 	case v1.VolumeBound:
-		return true, pv, nil
+		return true, nil
 	case v1.VolumeReleased:
-		return false, pv, fmt.Errorf("volume %s is released - %s", volumeName, pv.Status.Message)
+		return false, fmt.Errorf("volume %s is released - %s", pv.Name, pv.Status.Message)
 	case v1.VolumeFailed:
-		return false, pv, fmt.Errorf("volume %s is failed - %s", volumeName, pv.Status.Message)
+		return false, fmt.Errorf("volume %s is failed - %s", pv.Name, pv.Status.Message)
 	default:
-		return false, pv, fmt.Errorf("volume %s is %s - %s", volumeName, pv.Status.Phase, pv.Status.Message)
+		return false, fmt.Errorf("volume %s is %s - %s", pv.Name, pv.Status.Phase, pv.Status.Message)
 	}
 }
 
@@ -665,47 +766,48 @@ func (c *Client) TestService(serviceName string) (bool, *v1.Service, error) {
 		return false, nil, fmt.Errorf("Failed getting k8s service for %s - %s", serviceName, err.Error())
 	}
 
+	if svc.Spec.Type == v1.ServiceTypeClusterIP {
+		// todo, find how..
+		time.Sleep(5 * time.Second)
+	}
+	ready, err := isServiceReady(svc)
+	return ready, svc, err
+}
+
+func isServiceReady(svc *v1.Service) (bool, error) {
 	if svc.Spec.Type == v1.ServiceTypeLoadBalancer {
 		return len(svc.Status.LoadBalancer.Ingress) > 0 && (len(svc.Status.LoadBalancer.Ingress[0].IP) > 0 ||
-				len(svc.Status.LoadBalancer.Ingress[0].Hostname) > 0),
-			svc, nil
+			len(svc.Status.LoadBalancer.Ingress[0].Hostname) > 0), nil
 	} else if svc.Spec.Type == v1.ServiceTypeNodePort {
 		return len(svc.Spec.Ports) > 0 &&
-				svc.Spec.Ports[0].NodePort > 0,
-			svc, nil
+			svc.Spec.Ports[0].NodePort > 0, nil
 	} else if svc.Spec.Type == v1.ServiceTypeClusterIP {
-		// todo, find how..
-		time.Sleep(5 * time.Second)
-		return true, svc, nil
+		return true, nil
 	} else {
-		return false, svc, fmt.Errorf("Unsupported k8s service type %s for service %s", svc.Spec.Type, serviceName)
+		return false, fmt.Errorf("Unsupported k8s service type %s for service %s", svc.Spec.Type, svc.Name)
 	}
-
 }
 func (c *Client) WaitForServiceToStart(serviceName string, maxRetries int, sleepDuration time.Duration) (*v1.Service, error) {
-	serviceReady := false
-
-	var svc *v1.Service
-	var err error
-	for retries := maxRetries; !serviceReady && retries > 0; retries-- {
-		if retries != maxRetries {
-			time.Sleep(sleepDuration)
-		}
-
-		log.Printf("Waiting for service %s to start serving, %d/%d\n", serviceName, maxRetries-retries, maxRetries)
-		serviceReady, svc, err = c.TestService(serviceName)
-		if err != nil {
-			return nil, fmt.Errorf("Failed getting k8s service for %s - %s", serviceName, err.Error())
-		}
-	}
-
-	// If service did not start by now, fail the deployment
-	if !serviceReady {
-		return svc, fmt.Errorf("Service %s failed to start after %d retries", serviceName, maxRetries)
+	obj, err := WaitFor(context.Background(), c.ServiceGetter(serviceName), ServiceReady, WaitOptions{
+		Description: "service " + serviceName + " to start serving",
+		Timeout:     time.Duration(maxRetries) * sleepDuration,
+		Interval:    sleepDuration,
+		Watch:       c.WatchTrigger(coreResource("services"), true, nil),
+		Progress:    logWaitProgress("Waiting for service " + serviceName + " to start serving"),
+	})
+	if IsWaitTimeout(err) {
+		// If service did not start by now, fail the deployment
+		return obj.(*v1.Service), fmt.Errorf("Service %s failed to start after %d retries", serviceName, maxRetries)
+	} else if err != nil {
+		return nil, fmt.Errorf("Failed getting k8s service for %s - %s", serviceName, err.Error())
+	}
+
+	svc := obj.(*v1.Service)
+	if svc.Spec.Type == v1.ServiceTypeClusterIP {
+		// todo, find how..
+		time.Sleep(5 * time.Second)
 	}
-
 	return svc, nil
-
 }
 
 func (c *Client) DeployReplicationController(
@@ -722,21 +824,23 @@ func (c *Client) DeployReplicationController(
 	log.Printf("%s replication controller has been deployed successfully\n", rc.Name)
 
 	// Now waiting for replication controller to schedule a single replication
-	for retries := 60; rc.Status.Replicas == 0 && retries > 0; retries-- {
-		rc, err = c.GetReplicationControllerInfo(rc.Name)
-		if err != nil {
+	if rc.Status.Replicas == 0 {
+		obj, err := WaitFor(context.Background(), c.ReplicationControllerGetter(rc.Name), ReplicationControllerHasReplicas, WaitOptions{
+			Description: "replication controller " + rc.Name + " to create replicas",
+			Timeout:     60 * time.Second,
+			Watch:       c.WatchTrigger(coreResource("replicationcontrollers"), true, nil),
+		})
+		if IsWaitTimeout(err) {
+			return nil, fmt.Errorf(
+				"Replication controller %s failed creating replicas after waiting for 60 seconds",
+				rc.Name)
+		} else if err != nil {
 			return nil, fmt.Errorf(
 				"Failed getting k8s replication controller for %s - %s",
 				serviceName,
 				err.Error())
 		}
-		time.Sleep(1 * time.Second)
-	}
-
-	if rc.Status.Replicas == 0 {
-		return nil, fmt.Errorf(
-			"Replication controller %s failed creating replicas after waiting for 60 seconds",
-			rc.Name)
+		rc = obj.(*v1.ReplicationController)
 	}
 
 	log.Printf(
@@ -763,122 +867,86 @@ func (c *Client) DeployReplicationController(
 }
 
 func (c *Client) waitForPodToBeRunning(pod *v1.Pod) error {
-	var err error = nil
 	var numberOfEventsEncountered int = 0
 	// now we want to see that the stupid pod is really starting!
-	for retries := 900; pod.Status.Phase != "Running" && retries > 0; retries-- {
-		pod, err = c.GetPodInfo(pod.Name)
-		if err != nil {
-			return fmt.Errorf(
-				"Failed getting pod %s while waiting for it to be a sweetheart and run - %s",
-				pod.Name,
-				err.Error())
-		}
-		if pod.Status.ContainerStatuses[0].State.Waiting != nil &&
-			pod.Status.ContainerStatuses[0].State.Waiting.Reason == "ErrImagePull" {
-			return fmt.Errorf(
-				"Pod %s failed to start. failed pulling image %s - %s",
-				pod.Name,
-				pod.Status.ContainerStatuses[0].Image,
-				pod.Status.ContainerStatuses[0].State.Waiting.Message)
-
-		} else if pod.Status.ContainerStatuses[0].State.Terminated != nil {
-			break
-		}
-
-		// Getting pod events in order to print to console progress
-		podEvents, err := c.ListEntityEvents(pod.UID)
-
-		// In case we have an error when collecting events, skip it, we this is for logging only
-		if err != nil {
-			log.Printf(
-				"Failed listing pod %s events while waiting for it to start, oh well - %s\n",
-				pod.Name,
-				err.Error())
-		}
-
-		// In case we encounter new events, we log them
-		if len(podEvents) > numberOfEventsEncountered {
-
-			// slicing and printing only newly encountered events
-			for _, newEvent := range podEvents[numberOfEventsEncountered:] {
-				if newEvent.Reason == "Pulling" {
-					fmt.Printf(
-						"pod %s is pulling an image from docker registry. "+
-							"this might take a while, please be patient...\n%s\n",
-						pod.Name,
-						newEvent.Message)
-				} else {
-					fmt.Printf("pod %s: %s - %s\n", pod.Name, newEvent.Reason, newEvent.Message)
-				}
+	obj, err := WaitFor(context.Background(), c.PodGetter(pod.Name), PodRunning, WaitOptions{
+		Description: "pod " + pod.Name + " to run",
+		Timeout:     15 * time.Minute,
+		Watch:       c.WatchTrigger(coreResource("pods"), true, pod.Labels),
+		Progress: func(obj interface{}, attempt int, elapsed time.Duration) {
+			// Getting pod events in order to print to console progress
+			podEvents, err := c.ListEntityEvents(obj.(*v1.Pod).UID)
+
+			// In case we have an error when collecting events, skip it, we this is for logging only
+			if err != nil {
+				log.Printf(
+					"Failed listing pod %s events while waiting for it to start, oh well - %s\n",
+					pod.Name,
+					err.Error())
 			}
 
-			// Updating events already printed to log
-			numberOfEventsEncountered = len(podEvents)
-		}
+			// In case we encounter new events, we log them
+			if len(podEvents) > numberOfEventsEncountered {
+
+				// slicing and printing only newly encountered events
+				for _, newEvent := range podEvents[numberOfEventsEncountered:] {
+					if newEvent.Reason == "Pulling" {
+						fmt.Printf(
+							"pod %s is pulling an image from docker registry. "+
+								"this might take a while, please be patient...\n%s\n",
+							pod.Name,
+							newEvent.Message)
+					} else {
+						fmt.Printf("pod %s: %s - %s\n", pod.Name, newEvent.Reason, newEvent.Message)
+					}
+				}
 
-		time.Sleep(1 * time.Second)
-	}
+				// Updating events already printed to log
+				numberOfEventsEncountered = len(podEvents)
+			}
+		},
+	})
 
-	if pod.Status.Phase == "Running" {
+	if err == nil {
 		time.Sleep(3 * time.Second)
 		log.Printf("pod %s is now running, yey\n", pod.Name)
 		return nil
-	} else {
-
-		additionalErrorMessage := ""
-		// Enriching error message
-		if pod.Status.ContainerStatuses != nil &&
-			len(pod.Status.ContainerStatuses) > 0 {
-			if pod.Status.ContainerStatuses[0].State.Waiting != nil {
-				additionalErrorMessage +=
-					fmt.Sprintf(
-						". container still waiting. reason:%s; message:%s",
-						pod.Status.ContainerStatuses[0].State.Waiting.Reason,
-						pod.Status.ContainerStatuses[0].State.Waiting.Message)
-			} else if pod.Status.ContainerStatuses[0].State.Terminated != nil {
-				additionalErrorMessage +=
-					fmt.Sprintf(
-						". container terminated. reason:%s; message:%s; exit code:%d",
-						pod.Status.ContainerStatuses[0].State.Terminated.Reason,
-						pod.Status.ContainerStatuses[0].State.Terminated.Message,
-						pod.Status.ContainerStatuses[0].State.Terminated.ExitCode,
-					)
-			}
-		}
+	} else if IsWaitTimeout(err) {
+		pod = obj.(*v1.Pod)
 		return fmt.Errorf(
 			"Pod %s did not start after 15 freakin' minutes and found in phase %s%s",
 			pod.Name,
 			pod.Status.Phase,
-			additionalErrorMessage)
+			describeContainerState(pod))
+	} else {
+		// Either failed getting the pod or the pod won't ever run
+		return err
 	}
 }
 
 func (c *Client) waitForReplicationControllerPodToSchedule(
 	rc *v1.ReplicationController) (*v1.Pod, error) {
 	log.Printf("searching for pods scheduled by replication controller %s\n", rc.Name)
-	for retries := 60; retries > 0; retries-- {
 
-		// Searching for the single pod scheduled by the rc
-		rcPods, err := c.ListPodsInfo(rc.Spec.Selector)
-		if err != nil {
-			return nil, fmt.Errorf(
-				"Failed searching for pods scheduled for rc %s - %s",
-				rc.Name,
-				err.Error())
-		}
-		if len(rcPods) == 0 {
-			log.Printf("Could not yet find pods associated with replication controller %s\n", rc.Name)
-		} else {
-			thePod := rcPods[0]
-			log.Printf("Found Pod %s, scheduled for rc %s\n", thePod.Name, rc.Name)
-			return thePod, nil
-		}
-		time.Sleep(1 * time.Second)
+	// Searching for the single pod scheduled by the rc
+	obj, err := WaitFor(context.Background(), c.PodListGetter(rc.Spec.Selector), PodsScheduled, WaitOptions{
+		Description: "pods of replication controller " + rc.Name + " to be scheduled",
+		Timeout:     60 * time.Second,
+		Watch:       c.WatchTrigger(coreResource("pods"), true, rc.Spec.Selector),
+		Progress:    logWaitProgress("Could not yet find pods associated with replication controller " + rc.Name),
+	})
+	if IsWaitTimeout(err) {
+		return nil, fmt.Errorf(
+			"Failed finding pod scheduled for replication controller %s after waiting for 60 seconds",
+			rc.Name)
+	} else if err != nil {
+		return nil, fmt.Errorf(
+			"Failed searching for pods scheduled for rc %s - %s",
+			rc.Name,
+			err.Error())
 	}
 
-	return nil, fmt.Errorf(
-		"Failed finding pod scheduled for replication controller %s after waiting for 60 seconds",
-		rc.Name)
-
+	thePod := obj.([]*v1.Pod)[0]
+	log.Printf("Found Pod %s, scheduled for rc %s\n", thePod.Name, rc.Name)
+	return thePod, nil
 }
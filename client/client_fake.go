@@ -0,0 +1,1091 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"ocopea/kubernetes/client/types"
+	"ocopea/kubernetes/client/unversioned"
+	"ocopea/kubernetes/client/v1"
+	"ocopea/kubernetes/client/validation"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FakeAction is a call recorded by the FakeClient, e.g. {"create", "services", "orcs"}
+type FakeAction struct {
+	Verb     string
+	Resource string
+	Name     string
+}
+
+func (a FakeAction) String() string {
+	if a.Name == "" {
+		return a.Verb + " " + a.Resource
+	}
+	return a.Verb + " " + a.Resource + "/" + a.Name
+}
+
+type fakeObjectKey struct {
+	resource  string
+	namespace string
+	name      string
+}
+
+// FakeClient implements ClientInterface on top of in-memory objects, for testing code that uses the client.
+// Created objects are visible to later gets and lists, deletes remove them and every call is recorded as an action.
+// Nothing is waited for: services and volumes are ready, pods are running and tasks succeed unless scripted otherwise
+type FakeClient struct {
+	Namespace string
+
+	// Objects are validated before being created, like the real client does, unless validation is skipped
+	SkipValidation bool
+
+	lock            sync.Mutex
+	objects         map[fakeObjectKey][]byte
+	actions         []FakeAction
+	errors          map[string]error
+	notReadyChecks  map[fakeObjectKey]int
+	podPhases       map[string]v1.PodPhase
+	podLogs         map[string]string
+	podFiles        map[string]map[string][]byte
+	resourceVersion int
+}
+
+var _ ClientInterface = &FakeClient{}
+
+// Constructs a new fake client working in the given namespace, seeded with the given v1 objects
+func NewFakeClient(namespace string, objects ...interface{}) *FakeClient {
+	f := &FakeClient{
+		Namespace:      namespace,
+		objects:        make(map[fakeObjectKey][]byte),
+		errors:         make(map[string]error),
+		notReadyChecks: make(map[fakeObjectKey]int),
+		podPhases:      make(map[string]v1.PodPhase),
+		podLogs:        make(map[string]string),
+		podFiles:       make(map[string]map[string][]byte),
+	}
+	if err := f.Add(objects...); err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// Adds v1 objects (e.g. *v1.Node or *v1.Event) without recording actions, namespaced objects are added to the
+// namespace of the client. Copies of the objects are stored, the fields the api server sets are only filled when missing
+func (f *FakeClient) Add(objects ...interface{}) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	for _, obj := range objects {
+		resource, _ := fakeResourceOf(obj)
+		if resource == "" {
+			return fmt.Errorf("Failed adding object of unsupported type %T", obj)
+		}
+		copied := reflect.New(reflect.TypeOf(obj).Elem()).Interface()
+		copyFakeObject(obj, copied)
+		_, meta := fakeResourceOf(copied)
+		if err := f.create(resource, copied, meta, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the calls made so far
+func (f *FakeClient) Actions() []FakeAction {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return append([]FakeAction{}, f.actions...)
+}
+
+func (f *FakeClient) ClearActions() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.actions = nil
+}
+
+// Makes every call with the verb on the resource fail with err until errors are cleared.
+// Verb is one of create, update, get, list, delete or logs, "*" matches any verb or resource
+func (f *FakeClient) InjectError(verb string, resource string, err error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.errors[verb+" "+resource] = err
+}
+
+func (f *FakeClient) ClearErrors() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.errors = make(map[string]error)
+}
+
+// Makes the readiness checks of a service or a persistent volume (resource "services" or "persistentvolumes")
+// report it is not ready for the given number of checks
+func (f *FakeClient) DelayReadiness(resource string, name string, checks int) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.notReadyChecks[f.key(resource, name)] = checks
+}
+
+// Sets the phase of a pod, may be called before the pod is created, e.g. for pods of a replication controller
+// that are named <rc name>-<replica index> or for one off tasks
+func (f *FakeClient) SetPodPhase(podName string, phase v1.PodPhase) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.podPhases[podName] = phase
+	pod := &v1.Pod{}
+	if f.get("pods", podName, pod) == nil {
+		pod.Status.Phase = phase
+		f.store("pods", pod, &pod.ObjectMeta)
+	}
+}
+
+// Sets the logs returned for a pod
+func (f *FakeClient) SetPodLogs(podName string, logs string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.podLogs[podName] = logs
+}
+
+func fakeResourceOf(obj interface{}) (string, *v1.ObjectMeta) {
+	switch o := obj.(type) {
+	case *v1.Namespace:
+		return "namespaces", &o.ObjectMeta
+	case *v1.Node:
+		return "nodes", &o.ObjectMeta
+	case *v1.PersistentVolume:
+		return "persistentvolumes", &o.ObjectMeta
+	case *v1.PersistentVolumeClaim:
+		return "persistentvolumeclaims", &o.ObjectMeta
+	case *v1.Pod:
+		return "pods", &o.ObjectMeta
+	case *v1.Service:
+		return "services", &o.ObjectMeta
+	case *v1.ReplicationController:
+		return "replicationcontrollers", &o.ObjectMeta
+	case *v1.Event:
+		return "events", &o.ObjectMeta
+	case *v1.ResourceQuota:
+		return "resourcequotas", &o.ObjectMeta
+	}
+	return "", nil
+}
+
+func (f *FakeClient) key(resource string, name string) fakeObjectKey {
+	if isEntityTypeNamespaceLevel(resource) {
+		return fakeObjectKey{resource, f.Namespace, name}
+	}
+	return fakeObjectKey{resource, "", name}
+}
+
+// Records an action, returns the injected error for it if any. Lock must be held
+func (f *FakeClient) record(verb string, resource string, name string) error {
+	f.actions = append(f.actions, FakeAction{Verb: verb, Resource: resource, Name: name})
+	for _, errorKey := range []string{verb + " " + resource, verb + " *", "* " + resource, "* *"} {
+		if err, found := f.errors[errorKey]; found {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *FakeClient) validate(kind string, name string, errs validation.ErrorList) error {
+	if f.SkipValidation {
+		return nil
+	}
+	return validationError(kind, name, errs)
+}
+
+func fakeStatusError(method string, resource string, name string, statusCode int) *StatusError {
+	return &StatusError{
+		Method:     method,
+		Resource:   resource + "/" + name,
+		StatusCode: statusCode,
+		Status:     strconv.Itoa(statusCode) + " " + http.StatusText(statusCode),
+	}
+}
+
+// Stores the object, filling the fields set by the api server on it.
+// In force mode an already existing object is read into obj instead. Lock must be held
+func (f *FakeClient) create(resource string, obj interface{}, meta *v1.ObjectMeta, force bool) error {
+	if _, found := f.objects[f.key(resource, meta.Name)]; found {
+		if force {
+			return f.get(resource, meta.Name, obj)
+		}
+		return fakeStatusError("POST", resource, meta.Name, http.StatusConflict)
+	}
+	if isEntityTypeNamespaceLevel(resource) {
+		meta.Namespace = f.Namespace
+	}
+	if meta.UID == "" {
+		meta.UID = types.UID(fmt.Sprintf("fake-%s-%s", resource, meta.Name))
+	}
+	if meta.CreationTimestamp.IsZero() {
+		meta.CreationTimestamp = unversioned.Now()
+	}
+	f.store(resource, obj, meta)
+	return nil
+}
+
+// Lock must be held
+func (f *FakeClient) store(resource string, obj interface{}, meta *v1.ObjectMeta) {
+	f.resourceVersion++
+	meta.ResourceVersion = strconv.Itoa(f.resourceVersion)
+	data, _ := json.Marshal(obj)
+	f.objects[f.key(resource, meta.Name)] = data
+}
+
+// Reads a copy of a stored object into objPtr. Lock must be held
+func (f *FakeClient) get(resource string, name string, objPtr interface{}) error {
+	data, found := f.objects[f.key(resource, name)]
+	if !found {
+		return fakeStatusError("GET", resource, name, http.StatusNotFound)
+	}
+	return decodeFakeObject(data, objPtr)
+}
+
+// Lock must be held
+func (f *FakeClient) delete(resource string, name string) error {
+	key := f.key(resource, name)
+	if _, found := f.objects[key]; !found {
+		return fakeStatusError("DELETE", resource, name, http.StatusNotFound)
+	}
+	delete(f.objects, key)
+	if resource == "namespaces" {
+		for k := range f.objects {
+			if k.namespace == name {
+				delete(f.objects, k)
+			}
+		}
+	}
+	return nil
+}
+
+// Returns the stored objects of the resource in the namespace of the client, sorted by name. Lock must be held
+func (f *FakeClient) list(resource string) [][]byte {
+	var keys []fakeObjectKey
+	for key := range f.objects {
+		if key.resource == resource && key == f.key(resource, key.name) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].name < keys[j].name })
+	items := make([][]byte, 0, len(keys))
+	for _, key := range keys {
+		items = append(items, f.objects[key])
+	}
+	return items
+}
+
+func decodeFakeObject(data []byte, objPtr interface{}) error {
+	value := reflect.ValueOf(objPtr).Elem()
+	value.Set(reflect.Zero(value.Type()))
+	return json.Unmarshal(data, objPtr)
+}
+
+// Copies src into the object dstPtr points to, so stored objects are never shared with callers
+func copyFakeObject(src interface{}, dstPtr interface{}) {
+	data, _ := json.Marshal(src)
+	decodeFakeObject(data, dstPtr)
+}
+
+// Returns true in case the readiness of the object should be reported as not ready. Lock must be held
+func (f *FakeClient) delayedReadiness(resource string, name string) bool {
+	key := f.key(resource, name)
+	if f.notReadyChecks[key] > 0 {
+		f.notReadyChecks[key]--
+		return true
+	}
+	return false
+}
+
+func (f *FakeClient) CreateNamespace(ns *v1.Namespace, force bool) (*v1.Namespace, error) {
+	respNs := &v1.Namespace{}
+	if err := f.validate("namespace", ns.Name, validation.ValidateNamespace(ns)); err != nil {
+		return respNs, err
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if err := f.record("create", "namespaces", ns.Name); err != nil {
+		return respNs, err
+	}
+	copyFakeObject(ns, respNs)
+	respNs.Status.Phase = v1.NamespaceActive
+	return respNs, f.create("namespaces", respNs, &respNs.ObjectMeta, force)
+}
+
+// Creates the replication controller along with its pods, named <rc name>-<replica index>
+func (f *FakeClient) CreateReplicationController(rc *v1.ReplicationController, force bool) (*v1.ReplicationController, error) {
+	respRc := &v1.ReplicationController{}
+	if err := f.validate("replication controller", rc.Name, validation.ValidateReplicationController(rc)); err != nil {
+		return respRc, err
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if err := f.record("create", "replicationcontrollers", rc.Name); err != nil {
+		return respRc, err
+	}
+	copyFakeObject(rc, respRc)
+	respRc.SetDefaults()
+	if _, found := f.objects[f.key("replicationcontrollers", rc.Name)]; found {
+		return respRc, f.create("replicationcontrollers", respRc, &respRc.ObjectMeta, force)
+	}
+
+	replicas := 1
+	if respRc.Spec.Replicas != nil {
+		replicas = *respRc.Spec.Replicas
+	}
+	for i := 0; i < replicas && respRc.Spec.Template != nil; i++ {
+		pod := &v1.Pod{ObjectMeta: respRc.Spec.Template.ObjectMeta, Spec: respRc.Spec.Template.Spec}
+		pod.Name = fmt.Sprintf("%s-%d", respRc.Name, i)
+		f.createPod(pod)
+	}
+	respRc.Status.Replicas = replicas
+	return respRc, f.create("replicationcontrollers", respRc, &respRc.ObjectMeta, false)
+}
+
+// Lock must be held
+func (f *FakeClient) createPod(pod *v1.Pod) error {
+	pod.SetDefaults()
+	pod.Status.Phase = v1.PodRunning
+	if phase, found := f.podPhases[pod.Name]; found {
+		pod.Status.Phase = phase
+	}
+	return f.create("pods", pod, &pod.ObjectMeta, false)
+}
+
+func (f *FakeClient) CheckServiceExists(serviceName string) (bool, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if err := f.record("get", "services", serviceName); err != nil {
+		return false, err
+	}
+	_, found := f.objects[f.key("services", serviceName)]
+	return found, nil
+}
+
+// Creates the service, allocating a cluster ip, node ports and a load balancer address according to its type
+func (f *FakeClient) CreateService(svc *v1.Service, force bool) (*v1.Service, error) {
+	respSvc := &v1.Service{}
+	if err := f.validate("service", svc.Name, validation.ValidateService(svc)); err != nil {
+		return respSvc, err
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if err := f.record("create", "services", svc.Name); err != nil {
+		return respSvc, err
+	}
+
+	copyFakeObject(svc, respSvc)
+	respSvc.SetDefaults()
+	allocation := len(f.objects) + 1
+	if respSvc.Spec.ClusterIP == "" {
+		respSvc.Spec.ClusterIP = fmt.Sprintf("10.0.0.%d", allocation%250+1)
+	}
+	if respSvc.Spec.Type == v1.ServiceTypeNodePort || respSvc.Spec.Type == v1.ServiceTypeLoadBalancer {
+		for i := range respSvc.Spec.Ports {
+			if respSvc.Spec.Ports[i].NodePort == 0 {
+				respSvc.Spec.Ports[i].NodePort = 30000 + allocation*10 + i
+			}
+		}
+	}
+	if respSvc.Spec.Type == v1.ServiceTypeLoadBalancer {
+		respSvc.Status.LoadBalancer.Ingress = []v1.LoadBalancerIngress{{IP: fmt.Sprintf("10.0.1.%d", allocation%250+1)}}
+	}
+	return respSvc, f.create("services", respSvc, &respSvc.ObjectMeta, force)
+}
+
+func (f *FakeClient) CreatePersistentVolume(pv *v1.PersistentVolume, force bool) (*v1.PersistentVolume, error) {
+	respPv := &v1.PersistentVolume{}
+	if err := f.validate("persistent volume", pv.Name, validation.ValidatePersistentVolume(pv)); err != nil {
+		return respPv, err
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if err := f.record("create", "persistentvolumes", pv.Name); err != nil {
+		return respPv, err
+	}
+	copyFakeObject(pv, respPv)
+	if respPv.Status.Phase == "" {
+		respPv.Status.Phase = v1.VolumeAvailable
+	}
+	respPv.SetDefaults()
+	return respPv, f.create("persistentvolumes", respPv, &respPv.ObjectMeta, force)
+}
+
+// Creates the claim bound right away
+func (f *FakeClient) CreatePersistentVolumeClaim(pvc *v1.PersistentVolumeClaim, force bool) (*v1.PersistentVolumeClaim, error) {
+	respPvc := &v1.PersistentVolumeClaim{}
+	if err := f.validate("persistent volume claim", pvc.Name, validation.ValidatePersistentVolumeClaim(pvc)); err != nil {
+		return respPvc, err
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if err := f.record("create", "persistentvolumeclaims", pvc.Name); err != nil {
+		return respPvc, err
+	}
+	copyFakeObject(pvc, respPvc)
+	if respPvc.Status.Phase == "" {
+		respPvc.Status.Phase = v1.ClaimBound
+	}
+	return respPvc, f.create("persistentvolumeclaims", respPvc, &respPvc.ObjectMeta, force)
+}
+
+func (f *FakeClient) ListPodsInfo(labelFilters map[string]string) ([]*v1.Pod, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if err := f.record("list", "pods", ""); err != nil {
+		return nil, err
+	}
+	podList := make([]*v1.Pod, 0)
+	for _, data := range f.list("pods") {
+		pod := &v1.Pod{}
+		decodeFakeObject(data, pod)
+		if doesObjectHaveAllLabels(&pod.ObjectMeta, labelFilters) {
+			podList = append(podList, pod)
+		}
+	}
+	return podList, nil
+}
+
+func (f *FakeClient) ListEntityEvents(entityUid types.UID) ([]*v1.Event, error) {
+	return f.ListEvents(EventQuery{InvolvedObjectUID: entityUid})
+}
+
+// Lists the events matching the query, events are only kept in the namespace of the client
+func (f *FakeClient) ListEvents(query EventQuery) ([]*v1.Event, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if err := f.record("list", "events", ""); err != nil {
+		return nil, err
+	}
+	eventList := make([]*v1.Event, 0)
+	if query.Namespace != "" && query.Namespace != f.Namespace {
+		return eventList, nil
+	}
+	for _, data := range f.list("events") {
+		event := &v1.Event{}
+		decodeFakeObject(data, event)
+		if query.matches(event) {
+			eventList = append(eventList, event)
+		}
+	}
+	sortEvents(eventList)
+	return eventList, nil
+}
+
+func (f *FakeClient) CreateEvent(event *v1.Event) (*v1.Event, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	respEvent := &v1.Event{}
+	if err := f.record("create", "events", event.Name); err != nil {
+		return respEvent, err
+	}
+	copyFakeObject(event, respEvent)
+	return respEvent, f.create("events", respEvent, &respEvent.ObjectMeta, false)
+}
+
+func (f *FakeClient) UpdateEvent(event *v1.Event) (*v1.Event, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	respEvent := &v1.Event{}
+	if err := f.record("update", "events", event.Name); err != nil {
+		return respEvent, err
+	}
+	if _, found := f.objects[f.key("events", event.Name)]; !found {
+		return respEvent, fakeStatusError("PUT", "events", event.Name, http.StatusNotFound)
+	}
+	copyFakeObject(event, respEvent)
+	respEvent.Namespace = f.Namespace
+	f.store("events", respEvent, &respEvent.ObjectMeta)
+	return respEvent, nil
+}
+
+func (f *FakeClient) ListServiceInfo(labelFilters map[string]string) ([]*v1.Service, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if err := f.record("list", "services", ""); err != nil {
+		return nil, err
+	}
+	svcList := make([]*v1.Service, 0)
+	for _, data := range f.list("services") {
+		svc := &v1.Service{}
+		decodeFakeObject(data, svc)
+		if doesObjectHaveAllLabels(&svc.ObjectMeta, labelFilters) {
+			svcList = append(svcList, svc)
+		}
+	}
+	return svcList, nil
+}
+
+func (f *FakeClient) ListNamespaceInfo(labelFilters map[string]string) ([]*v1.Namespace, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if err := f.record("list", "namespaces", ""); err != nil {
+		return nil, err
+	}
+	nsList := make([]*v1.Namespace, 0)
+	for _, data := range f.list("namespaces") {
+		ns := &v1.Namespace{}
+		decodeFakeObject(data, ns)
+		if doesObjectHaveAllLabels(&ns.ObjectMeta, labelFilters) {
+			nsList = append(nsList, ns)
+		}
+	}
+	return nsList, nil
+}
+
+func (f *FakeClient) ListNodes(labelFilters map[string]string) ([]*v1.Node, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if err := f.record("list", "nodes", ""); err != nil {
+		return nil, err
+	}
+	nodeList := make([]*v1.Node, 0)
+	for _, data := range f.list("nodes") {
+		node := &v1.Node{}
+		decodeFakeObject(data, node)
+		if doesObjectHaveAllLabels(&node.ObjectMeta, labelFilters) {
+			nodeList = append(nodeList, node)
+		}
+	}
+	return nodeList, nil
+}
+
+func (f *FakeClient) ListResourceQuotas(labelFilters map[string]string) ([]*v1.ResourceQuota, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if err := f.record("list", "resourcequotas", ""); err != nil {
+		return nil, err
+	}
+	quotaList := make([]*v1.ResourceQuota, 0)
+	for _, data := range f.list("resourcequotas") {
+		quota := &v1.ResourceQuota{}
+		decodeFakeObject(data, quota)
+		if doesObjectHaveAllLabels(&quota.ObjectMeta, labelFilters) {
+			quotaList = append(quotaList, quota)
+		}
+	}
+	return quotaList, nil
+}
+
+// Compares the desired object with the stored one, recording the get action
+func (f *FakeClient) Diff(desired interface{}) (*ObjectDiff, error) {
+	desiredConfig, err := desiredConfiguration(desired)
+	if err != nil {
+		return nil, err
+	}
+	resource := manifestKindOf(desiredConfig.GetAPIVersion(), desiredConfig.GetKind()).resource.Resource
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if err = f.record("get", resource, desiredConfig.GetName()); err != nil {
+		return nil, err
+	}
+	data, found := f.objects[f.key(resource, desiredConfig.GetName())]
+	if !found {
+		return &ObjectDiff{Kind: desiredConfig.GetKind(), Name: desiredConfig.GetName(), Fields: []FieldDiff{}}, nil
+	}
+	live := Unstructured{}
+	if err = decodeUnstructured(bytes.NewReader(data), &live); err != nil {
+		return nil, err
+	}
+	return DiffObjects(desired, live)
+}
+
+// Clones the objects the fake keeps in the source namespace like the client does, cloned objects replace those
+// cloned before
+func (f *FakeClient) CloneNamespace(srcNamespace string, dstNamespace string, options *CloneOptions) ([]*ApplyResult, error) {
+	if options == nil {
+		options = &CloneOptions{}
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if err := f.record("clone", "namespaces", srcNamespace); err != nil {
+		return nil, err
+	}
+	if srcNamespace == dstNamespace {
+		return nil, fmt.Errorf("Failed cloning namespace %s onto itself", srcNamespace)
+	}
+	kinds, err := options.clonedKinds()
+	if err != nil {
+		return nil, fmt.Errorf("Failed cloning namespace %s - %s", srcNamespace, err.Error())
+	}
+	src, found := f.objects[fakeObjectKey{"namespaces", "", srcNamespace}]
+	if !found {
+		return nil, fakeStatusError("GET", "namespaces", srcNamespace, http.StatusNotFound)
+	}
+	srcObject := Unstructured{}
+	if err = decodeUnstructured(bytes.NewReader(src), &srcObject); err != nil {
+		return nil, err
+	}
+	results := []*ApplyResult{f.storeClone("namespaces", "", cloneNamespaceObject(srcObject, dstNamespace, options))}
+
+	for _, kind := range kinds {
+		resource := pluralResourceName(kind)
+		var keys []fakeObjectKey
+		for key := range f.objects {
+			if key.resource == resource && key.namespace == srcNamespace {
+				keys = append(keys, key)
+			}
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i].name < keys[j].name })
+		for _, key := range keys {
+			obj := Unstructured{}
+			if err = decodeUnstructured(bytes.NewReader(f.objects[key]), &obj); err != nil {
+				return results, err
+			}
+			if !doesObjectHaveAllLabels(&v1.ObjectMeta{Labels: obj.GetLabels()}, options.LabelFilters) {
+				continue
+			}
+			if clone := cloneObject(obj, kind, srcNamespace, dstNamespace, options); clone != nil {
+				results = append(results, f.storeClone(resource, dstNamespace, clone))
+			}
+		}
+	}
+	return results, nil
+}
+
+// Stores a cloned object, filling the fields set by the api server on it. Lock must be held
+func (f *FakeClient) storeClone(resource string, namespace string, obj Unstructured) *ApplyResult {
+	key := fakeObjectKey{resource, namespace, obj.GetName()}
+	operation := ApplyConfigured
+	if _, found := f.objects[key]; !found {
+		operation = ApplyCreated
+		SetNestedField(obj, fmt.Sprintf("fake-%s-%s", resource, obj.GetName()), "metadata", "uid")
+		SetNestedField(obj, unversioned.Now().UTC().Format(time.RFC3339), "metadata", "creationTimestamp")
+	}
+	f.resourceVersion++
+	SetNestedField(obj, strconv.Itoa(f.resourceVersion), "metadata", "resourceVersion")
+	data, _ := json.Marshal(obj)
+	f.objects[key] = data
+	return &ApplyResult{Object: obj, Operation: operation}
+}
+
+// Records the impersonate action on the user and returns the fake itself, calls made on behalf of the user
+// are recorded after it
+func (f *FakeClient) Impersonate(userName string, groups []string) ClientInterface {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.record("impersonate", "users", userName)
+	return f
+}
+
+// Reads a copy of an object, recording the get action
+func (f *FakeClient) getEntityInfo(resource string, name string, objPtr interface{}) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if err := f.record("get", resource, name); err != nil {
+		return err
+	}
+	return f.get(resource, name, objPtr)
+}
+
+func (f *FakeClient) GetServiceInfo(serviceName string) (*v1.Service, error) {
+	svc := &v1.Service{}
+	err := f.getEntityInfo("services", serviceName, svc)
+	return svc, err
+}
+
+func (f *FakeClient) GetPersistentVolumeInfo(persistentVolumeName string) (*v1.PersistentVolume, error) {
+	pv := &v1.PersistentVolume{}
+	err := f.getEntityInfo("persistentvolumes", persistentVolumeName, pv)
+	return pv, err
+}
+
+func (f *FakeClient) GetPersistentVolumeClaimInfo(pvcName string) (*v1.PersistentVolumeClaim, error) {
+	pvc := &v1.PersistentVolumeClaim{}
+	err := f.getEntityInfo("persistentvolumeclaims", pvcName, pvc)
+	return pvc, err
+}
+
+func (f *FakeClient) GetReplicationControllerInfo(rcName string) (*v1.ReplicationController, error) {
+	rc := &v1.ReplicationController{}
+	err := f.getEntityInfo("replicationcontrollers", rcName, rc)
+	return rc, err
+}
+
+func (f *FakeClient) GetPodInfo(podName string) (*v1.Pod, error) {
+	pod := &v1.Pod{}
+	err := f.getEntityInfo("pods", podName, pod)
+	return pod, err
+}
+
+func (f *FakeClient) GetNode(nodeName string) (*v1.Node, error) {
+	node := &v1.Node{}
+	err := f.getEntityInfo("nodes", nodeName, node)
+	return node, err
+}
+
+func (f *FakeClient) GetPodLogs(podName string) ([]byte, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if err := f.record("logs", "pods", podName); err != nil {
+		return nil, err
+	}
+	if _, found := f.objects[f.key("pods", podName)]; !found {
+		return nil, fakeStatusError("GET", "pods", podName, http.StatusNotFound)
+	}
+	return []byte(f.podLogs[podName]), nil
+}
+
+// Keeps an archive of the local file or directory as the remote path of the pod, so copying the same remote path
+// from the pod returns it
+func (f *FakeClient) CopyToPod(podName string, localPath string, remotePath string, options *CopyOptions) error {
+	if options == nil {
+		options = &CopyOptions{}
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if err := f.record("copy-to", "pods", podName); err != nil {
+		return err
+	}
+	if _, found := f.objects[f.key("pods", podName)]; !found {
+		return fakeStatusError("GET", "pods", podName, http.StatusNotFound)
+	}
+
+	remotePath = path.Clean(remotePath)
+	archive := &bytes.Buffer{}
+	if err := writeCopyArchive(archive, localPath, path.Base(remotePath), &copyProgressCounter{report: options.Progress}); err != nil {
+		return fmt.Errorf("Failed copying %s to %s in pod %s - %s", localPath, remotePath, podName, err.Error())
+	}
+	if f.podFiles[podName] == nil {
+		f.podFiles[podName] = make(map[string][]byte)
+	}
+	f.podFiles[podName][remotePath] = archive.Bytes()
+	return nil
+}
+
+// Extracts the archive copied to the remote path of the pod by CopyToPod, other paths are missing
+func (f *FakeClient) CopyFromPod(podName string, remotePath string, localPath string, options *CopyOptions) error {
+	if options == nil {
+		options = &CopyOptions{}
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if err := f.record("copy-from", "pods", podName); err != nil {
+		return err
+	}
+	if _, found := f.objects[f.key("pods", podName)]; !found {
+		return fakeStatusError("GET", "pods", podName, http.StatusNotFound)
+	}
+
+	remotePath = path.Clean(remotePath)
+	archive, found := f.podFiles[podName][remotePath]
+	if !found {
+		return &ExecError{
+			PodName:  podName,
+			Command:  []string{"tar", "-cf", "-", remotePath},
+			ExitCode: 2,
+			Message:  remotePath + ": No such file or directory",
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(localPath), 0755); err != nil {
+		return err
+	}
+	return extractCopyArchive(bytes.NewReader(archive), localPath, path.Base(remotePath), &copyProgressCounter{report: options.Progress})
+}
+
+// Sends the log lines of the pod to the consumer channel, as if the pod logged them all and exited
+func (f *FakeClient) FollowPodLogs(podName string, consumerChannel chan string) (CloseHandle, error) {
+	logs, err := f.GetPodLogs(podName)
+	if err != nil {
+		return nil, fmt.Errorf("Failed following k8s logs for pod %s - %s", podName, err.Error())
+	}
+	closeChannel := make(chan bool, 1)
+	go func() {
+		reader := bufio.NewReader(strings.NewReader(string(logs)))
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			select {
+			case consumerChannel <- line:
+			case <-closeChannel:
+				return
+			}
+		}
+	}()
+	return func() {
+		closeChannel <- true
+	}, nil
+}
+
+func (f *FakeClient) DeletePod(podName string) (*v1.Pod, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if err := f.record("delete", "pods", podName); err != nil {
+		return nil, err
+	}
+	pod := &v1.Pod{}
+	if err := f.get("pods", podName, pod); err != nil {
+		return nil, fmt.Errorf("Failed deleting k8s pods/%s for %s - %s", podName, podName, err.Error())
+	}
+	return pod, f.delete("pods", podName)
+}
+
+func (f *FakeClient) CheckNamespaceExist(nsName string) (bool, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if err := f.record("get", "namespaces", nsName); err != nil {
+		return false, err
+	}
+	_, found := f.objects[f.key("namespaces", nsName)]
+	return found, nil
+}
+
+// Deletes the namespace along with all the objects in it, the namespace is gone immediately
+func (f *FakeClient) DeleteNamespaceAndWaitForTermination(nsName string, maxRetries int, sleepDuration time.Duration) error {
+	exist, err := f.CheckNamespaceExist(nsName)
+	if err != nil || !exist {
+		return err
+	}
+	return f.DeleteNamespace(nsName)
+}
+
+func (f *FakeClient) deleteEntity(resource string, name string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if err := f.record("delete", resource, name); err != nil {
+		return err
+	}
+	if err := f.delete(resource, name); err != nil {
+		return fmt.Errorf("Failed deleting %s/%s received status %s", resource, name, err.(*StatusError).Status)
+	}
+	return nil
+}
+
+func (f *FakeClient) DeleteNamespace(nsName string) error {
+	return f.deleteEntity("namespaces", nsName)
+}
+
+func (f *FakeClient) DeleteReplicationController(rcName string) error {
+	return f.DeleteReplicationControllerWithOptions(rcName, nil)
+}
+
+// Deletes the replication controller along with its pods, unless the propagation policy is Orphan
+func (f *FakeClient) DeleteReplicationControllerWithOptions(rcName string, options *v1.DeleteOptions) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if err := f.record("delete", "replicationcontrollers", rcName); err != nil {
+		return err
+	}
+	rc := &v1.ReplicationController{}
+	if err := f.get("replicationcontrollers", rcName, rc); err != nil {
+		return fmt.Errorf("Failed deleting replicationcontrollers/%s received status %s", rcName, err.(*StatusError).Status)
+	}
+	if podSelector := replicationControllerPodSelector(rc); !isOrphaning(options) && len(podSelector) > 0 {
+		f.deleteMatching("pods", podSelector)
+	}
+	return f.delete("replicationcontrollers", rcName)
+}
+
+// Creates or deletes the pods of the replication controller, pods are named <rc name>-<replica index>
+func (f *FakeClient) ScaleReplicationController(rcName string, replicas int) (*v1.ReplicationController, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	rc := &v1.ReplicationController{}
+	if err := f.record("update", "replicationcontrollers", rcName); err != nil {
+		return rc, err
+	}
+	if err := f.get("replicationcontrollers", rcName, rc); err != nil {
+		return rc, err
+	}
+	for i := 0; i < replicas && rc.Spec.Template != nil; i++ {
+		pod := &v1.Pod{ObjectMeta: rc.Spec.Template.ObjectMeta, Spec: rc.Spec.Template.Spec}
+		pod.Name = fmt.Sprintf("%s-%d", rc.Name, i)
+		if _, found := f.objects[f.key("pods", pod.Name)]; !found {
+			f.createPod(pod)
+		}
+	}
+	for i := replicas; i < rc.Status.Replicas; i++ {
+		f.delete("pods", fmt.Sprintf("%s-%d", rc.Name, i))
+	}
+	rc.Spec.Replicas = &replicas
+	rc.Status.Replicas = replicas
+	f.store("replicationcontrollers", rc, &rc.ObjectMeta)
+	return rc, nil
+}
+
+func (f *FakeClient) DeleteCollection(resource string, labelFilters map[string]string, options *v1.DeleteOptions) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if err := f.record("delete", resource, ""); err != nil {
+		return err
+	}
+	f.deleteMatching(resource, labelFilters)
+	return nil
+}
+
+// Deletes the objects of the resource in the namespace of the client having all the labels. Lock must be held
+func (f *FakeClient) deleteMatching(resource string, labelFilters map[string]string) {
+	for _, data := range f.list(resource) {
+		obj := &struct {
+			Metadata v1.ObjectMeta `json:"metadata"`
+		}{}
+		decodeFakeObject(data, obj)
+		if doesObjectHaveAllLabels(&obj.Metadata, labelFilters) {
+			f.delete(resource, obj.Metadata.Name)
+		}
+	}
+}
+
+func (f *FakeClient) DeleteService(serviceName string) error {
+	return f.deleteEntity("services", serviceName)
+}
+
+func (f *FakeClient) DeletePersistentVolumeClaim(pvcName string) error {
+	return f.deleteEntity("persistentvolumeclaims", pvcName)
+}
+
+func (f *FakeClient) RunOneOffTask(name string, containerName string, additionalVars []v1.EnvVar) error {
+	return runOneOffTask(f, name, containerName, additionalVars)
+}
+
+// Runs the task pod, the task succeeds unless its pod phase was scripted otherwise using SetPodPhase.
+// A task whose pod was scripted to stay pending or running times out right away
+func (f *FakeClient) RunTask(name string, image string, additionalVars []v1.EnvVar, options TaskOptions) (*TaskResult, error) {
+	options = options.withDefaults(name)
+	f.lock.Lock()
+	_, scripted := f.podPhases[name]
+	if !scripted {
+		f.podPhases[name] = v1.PodSucceeded
+	}
+	f.lock.Unlock()
+
+	createdPod, err := f.CreatePod(newTaskPod(name, image, additionalVars, options), false)
+	f.lock.Lock()
+	if !scripted {
+		delete(f.podPhases, name)
+	}
+	logs := f.podLogs[name]
+	f.lock.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("Failed creating task pod %s - %s", name, err.Error())
+	}
+
+	result := &TaskResult{PodName: createdPod.Name, Phase: createdPod.Status.Phase}
+	output := &taskOutput{consumer: options.Output}
+	for _, line := range strings.SplitAfter(logs, "\n") {
+		if line != "" {
+			output.write(line)
+		}
+	}
+	result.Output = output.String()
+
+	switch result.Phase {
+	case v1.PodSucceeded:
+		result.Reason = "Completed"
+	case v1.PodFailed:
+		result.ExitCode = 1
+		result.Reason = "Error"
+		err = result.failure()
+	default:
+		err = &WaitTimeoutError{Description: "task pod " + name + " to finish", Elapsed: options.Timeout, Attempts: 1}
+	}
+	if err == nil || !options.KeepPodOnFailure {
+		f.DeletePod(createdPod.Name)
+	}
+	return result, err
+}
+
+// Creates the pod, running unless its phase was scripted using SetPodPhase
+func (f *FakeClient) CreatePod(pod *v1.Pod, force bool) (*v1.Pod, error) {
+	respPod := &v1.Pod{}
+	if err := f.validate("pod", pod.Name, validation.ValidatePod(pod)); err != nil {
+		return respPod, err
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if err := f.record("create", "pods", pod.Name); err != nil {
+		return respPod, err
+	}
+	copyFakeObject(pod, respPod)
+	if _, found := f.objects[f.key("pods", pod.Name)]; found {
+		return respPod, f.create("pods", respPod, &respPod.ObjectMeta, force)
+	}
+	return respPod, f.createPod(respPod)
+}
+
+func (f *FakeClient) TestVolume(volumeName string) (bool, *v1.PersistentVolume, error) {
+	pv, err := f.GetPersistentVolumeInfo(volumeName)
+	if err != nil {
+		return false, nil, fmt.Errorf("Failed getting k8s pv for %s - %s", volumeName, err.Error())
+	}
+	f.lock.Lock()
+	delayed := f.delayedReadiness("persistentvolumes", volumeName)
+	f.lock.Unlock()
+	if delayed {
+		return false, pv, nil
+	}
+	ready, err := isVolumeReady(pv)
+	return ready, pv, err
+}
+
+func (f *FakeClient) TestService(serviceName string) (bool, *v1.Service, error) {
+	svc, err := f.GetServiceInfo(serviceName)
+	if err != nil {
+		return false, nil, fmt.Errorf("Failed getting k8s service for %s - %s", serviceName, err.Error())
+	}
+	f.lock.Lock()
+	delayed := f.delayedReadiness("services", serviceName)
+	f.lock.Unlock()
+	if delayed {
+		return false, svc, nil
+	}
+	ready, err := isServiceReady(svc)
+	return ready, svc, err
+}
+
+// Checks the service readiness up to maxRetries times without sleeping in between
+func (f *FakeClient) WaitForServiceToStart(serviceName string, maxRetries int, sleepDuration time.Duration) (*v1.Service, error) {
+	var svc *v1.Service
+	for retries := maxRetries; retries > 0; retries-- {
+		ready, readSvc, err := f.TestService(serviceName)
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting k8s service for %s - %s", serviceName, err.Error())
+		}
+		svc = readSvc
+		if ready {
+			return svc, nil
+		}
+	}
+	return svc, fmt.Errorf("Service %s failed to start after %d retries", serviceName, maxRetries)
+}
+
+// Creates the replication controller and fails in case its first pod is not running
+func (f *FakeClient) DeployReplicationController(serviceName string, rc *v1.ReplicationController, force bool) (*v1.ReplicationController, error) {
+	rc, err := f.CreateReplicationController(rc, force)
+	if err != nil {
+		return nil, fmt.Errorf("Failed creating k8s replication controller for %s - %s", serviceName, err.Error())
+	}
+	pods, err := f.ListPodsInfo(rc.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("Failed searching for pods scheduled for rc %s - %s", rc.Name, err.Error())
+	}
+	if len(pods) == 0 {
+		return nil, fmt.Errorf("Failed finding pod scheduled for replication controller %s", rc.Name)
+	}
+	if pods[0].Status.Phase != v1.PodRunning {
+		return nil, fmt.Errorf("Pod %s did not start and found in phase %s", pods[0].Name, pods[0].Status.Phase)
+	}
+	return rc, nil
+}
+
+func (f *FakeClient) FindClusterAddress() (string, error) {
+	nodes, err := f.ListNodes(nil)
+	if err != nil {
+		return "", err
+	}
+	return findClusterAddress(nodes)
+}
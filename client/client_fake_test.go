@@ -0,0 +1,162 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"errors"
+	"ocopea/kubernetes/client/types"
+	"ocopea/kubernetes/client/v1"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func fakeReplicationController(name string) *v1.ReplicationController {
+	replicas := 2
+	return &v1.ReplicationController{
+		ObjectMeta: v1.ObjectMeta{Name: name},
+		Spec: v1.ReplicationControllerSpec{
+			Replicas: &replicas,
+			Selector: map[string]string{"app": name},
+			Template: &v1.PodTemplateSpec{
+				ObjectMeta: v1.ObjectMeta{Labels: map[string]string{"app": name}},
+				Spec:       v1.PodSpec{Containers: []v1.Container{{Name: name, Image: "ocopea/" + name}}},
+			},
+		},
+	}
+}
+
+func TestFakeClientObjects(t *testing.T) {
+	f := NewFakeClient("ocopea", &v1.Namespace{ObjectMeta: v1.ObjectMeta{Name: "ocopea"}})
+
+	rc, err := f.DeployReplicationController("orcs", fakeReplicationController("orcs"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rc.Status.Replicas != 2 || rc.Namespace != "ocopea" || rc.ResourceVersion == "" {
+		t.Errorf("unexpected deployed rc %+v", rc.ObjectMeta)
+	}
+	if _, err = f.CreateReplicationController(fakeReplicationController("orcs"), false); !IsConflict(err) {
+		t.Errorf("expected conflict creating an existing rc, got %v", err)
+	}
+	if _, err = f.CreateReplicationController(fakeReplicationController("orcs"), true); err != nil {
+		t.Errorf("expected force create of an existing rc to succeed, got %v", err)
+	}
+
+	pods, err := f.ListPodsInfo(map[string]string{"app": "orcs"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pods) != 2 || pods[0].Name != "orcs-0" || pods[1].Name != "orcs-1" || pods[0].Status.Phase != v1.PodRunning {
+		t.Fatalf("expected 2 running pods scheduled by the rc, got %v", pods)
+	}
+
+	svc := &v1.Service{
+		ObjectMeta: v1.ObjectMeta{Name: "orcs"},
+		Spec: v1.ServiceSpec{
+			Type:  v1.ServiceTypeNodePort,
+			Ports: []v1.ServicePort{{Port: 80, TargetPort: types.NewIntOrStringFromInt(8080)}},
+		},
+	}
+	if _, err = f.CreateService(svc, false); err != nil {
+		t.Fatal(err)
+	}
+	if svc.Spec.Ports[0].NodePort != 0 {
+		t.Errorf("the created service should not be modified")
+	}
+	exists, err := f.CheckServiceExists("orcs")
+	if err != nil || !exists {
+		t.Errorf("expected service to exist, got %v", err)
+	}
+
+	if err = f.DeleteService("orcs"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = f.GetServiceInfo("orcs"); !IsNotFound(err) {
+		t.Errorf("expected deleted service not to be found, got %v", err)
+	}
+	if err = f.DeleteService("orcs"); err == nil {
+		t.Errorf("expected deleting a missing service to fail")
+	}
+
+	if err = f.DeleteNamespaceAndWaitForTermination("ocopea", 1, time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if pods, _ = f.ListPodsInfo(nil); len(pods) != 0 {
+		t.Errorf("expected pods to be deleted with their namespace, got %v", pods)
+	}
+}
+
+func TestFakeClientActionsAndErrors(t *testing.T) {
+	f := NewFakeClient("ocopea")
+	f.InjectError("create", "services", errors.New("boom"))
+
+	if _, err := f.CreateService(&v1.Service{ObjectMeta: v1.ObjectMeta{Name: "orcs"}}, false); err == nil || err.Error() != "boom" {
+		t.Errorf("expected injected error, got %v", err)
+	}
+	f.ClearErrors()
+	if _, err := f.CreateService(&v1.Service{ObjectMeta: v1.ObjectMeta{Name: "orcs"}}, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.CreateService(&v1.Service{ObjectMeta: v1.ObjectMeta{Name: "Invalid_Name"}}, false); err == nil {
+		t.Errorf("expected invalid service to fail validation")
+	}
+	f.CheckServiceExists("orcs")
+
+	expectedActions := []FakeAction{
+		{Verb: "create", Resource: "services", Name: "orcs"},
+		{Verb: "create", Resource: "services", Name: "orcs"},
+		{Verb: "get", Resource: "services", Name: "orcs"},
+	}
+	if !reflect.DeepEqual(f.Actions(), expectedActions) {
+		t.Errorf("unexpected actions %v", f.Actions())
+	}
+}
+
+func TestFakeClientScripting(t *testing.T) {
+	f := NewFakeClient("ocopea")
+
+	svc := &v1.Service{
+		ObjectMeta: v1.ObjectMeta{Name: "orcs"},
+		Spec:       v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer, Ports: []v1.ServicePort{{Port: 80}}},
+	}
+	if _, err := f.CreateService(svc, false); err != nil {
+		t.Fatal(err)
+	}
+	f.DelayReadiness("services", "orcs", 2)
+	if _, err := f.WaitForServiceToStart("orcs", 2, time.Second); err == nil {
+		t.Errorf("expected service not to be ready after 2 checks")
+	}
+	started, err := f.WaitForServiceToStart("orcs", 1, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(started.Status.LoadBalancer.Ingress) != 1 {
+		t.Errorf("expected load balancer ingress, got %+v", started.Status)
+	}
+
+	f.SetPodPhase("broken-0", v1.PodPending)
+	if _, err = f.DeployReplicationController("broken", fakeReplicationController("broken"), false); err == nil {
+		t.Errorf("expected deploying an rc with a pending pod to fail")
+	}
+
+	if err = f.RunOneOffTask("task", "ocopea/bootstrap", nil); err != nil {
+		t.Fatal(err)
+	}
+	f.SetPodPhase("failing-task", v1.PodFailed)
+	if err = f.RunOneOffTask("failing-task", "ocopea/bootstrap", nil); err == nil {
+		t.Errorf("expected failing task to fail")
+	}
+	if exists, _ := f.CheckNamespaceExist("ocopea"); exists {
+		t.Errorf("namespaces should only exist once created")
+	}
+
+	if err = f.Add(&v1.Node{
+		ObjectMeta: v1.ObjectMeta{Name: "node1"},
+		Status:     v1.NodeStatus{Addresses: []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "192.168.99.100"}}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if address, err := f.FindClusterAddress(); err != nil || address != "192.168.99.100" {
+		t.Errorf("unexpected cluster address %s - %v", address, err)
+	}
+}
@@ -24,26 +24,45 @@ type ClientInterface interface {
 	CheckServiceExists(serviceName string) (bool, error)
 	CreateService(svc *v1.Service, force bool) (*v1.Service, error)
 	CreatePersistentVolume(pv *v1.PersistentVolume, force bool) (*v1.PersistentVolume, error)
+	CreatePersistentVolumeClaim(pvc *v1.PersistentVolumeClaim, force bool) (*v1.PersistentVolumeClaim, error)
 	ListPodsInfo(labelFilters map[string]string) ([]*v1.Pod, error)
 	ListEntityEvents(entityUid types.UID) ([]*v1.Event, error)
+	ListEvents(query EventQuery) ([]*v1.Event, error)
+	CreateEvent(event *v1.Event) (*v1.Event, error)
+	UpdateEvent(event *v1.Event) (*v1.Event, error)
 	ListServiceInfo(labelFilters map[string]string) ([]*v1.Service, error)
 	ListNamespaceInfo(labelFilters map[string]string) ([]*v1.Namespace, error)
 	GetServiceInfo(serviceName string) (*v1.Service, error)
 	GetPersistentVolumeInfo(persistentVolumeName string) (*v1.PersistentVolume, error)
+	GetPersistentVolumeClaimInfo(pvcName string) (*v1.PersistentVolumeClaim, error)
 	GetReplicationControllerInfo(rcName string) (*v1.ReplicationController, error)
 	GetPodInfo(podName string) (*v1.Pod, error)
 	GetPodLogs(podName string) ([]byte, error)
 	FollowPodLogs(podName string, consumerChannel chan string) (CloseHandle, error)
+	CopyToPod(podName string, localPath string, remotePath string, options *CopyOptions) error
+	CopyFromPod(podName string, remotePath string, localPath string, options *CopyOptions) error
 	DeletePod(podName string) (*v1.Pod, error)
 	CheckNamespaceExist(nsName string) (bool, error)
 	DeleteNamespaceAndWaitForTermination(nsName string, maxRetries int, sleepDuration time.Duration) error
 	DeleteNamespace(nsName string) error
 	DeleteReplicationController(rcName string) error
+	DeleteReplicationControllerWithOptions(rcName string, options *v1.DeleteOptions) error
+	ScaleReplicationController(rcName string, replicas int) (*v1.ReplicationController, error)
+	DeleteCollection(resource string, labelFilters map[string]string, options *v1.DeleteOptions) error
 	DeleteService(serviceName string) error
+	DeletePersistentVolumeClaim(pvcName string) error
 	RunOneOffTask(name string, containerName string, additionalVars []v1.EnvVar) error
+	RunTask(name string, image string, additionalVars []v1.EnvVar, options TaskOptions) (*TaskResult, error)
 	CreatePod(pod *v1.Pod, force bool) (*v1.Pod, error)
 	TestVolume(volumeName string) (bool, *v1.PersistentVolume, error)
 	TestService(serviceName string) (bool, *v1.Service, error)
 	WaitForServiceToStart(serviceName string, maxRetries int, sleepDuration time.Duration) (*v1.Service, error)
 	DeployReplicationController(serviceName string, rc *v1.ReplicationController, force bool) (*v1.ReplicationController, error)
+	ListNodes(labelFilters map[string]string) ([]*v1.Node, error)
+	GetNode(nodeName string) (*v1.Node, error)
+	ListResourceQuotas(labelFilters map[string]string) ([]*v1.ResourceQuota, error)
+	Diff(desired interface{}) (*ObjectDiff, error)
+	CloneNamespace(srcNamespace string, dstNamespace string, options *CloneOptions) ([]*ApplyResult, error)
+	Impersonate(userName string, groups []string) ClientInterface
+	FindClusterAddress() (string, error)
 }
@@ -16,33 +16,52 @@ import (
 type ClientMock struct {
 	delegate *ClientInterface
 
-	MockCreateNamespace                      func(ns *v1.Namespace, force bool) (*v1.Namespace, error)
-	MockCreateReplicationController          func(rc *v1.ReplicationController, force bool) (*v1.ReplicationController, error)
-	MockCheckServiceExists                   func(serviceName string) (bool, error)
-	MockCreateService                        func(svc *v1.Service, force bool) (*v1.Service, error)
-	MockCreatePersistentVolume               func(pv *v1.PersistentVolume, force bool) (*v1.PersistentVolume, error)
-	MockListPodsInfo                         func(labelFilters map[string]string) ([]*v1.Pod, error)
-	MockListEntityEvents                     func(entityUid types.UID) ([]*v1.Event, error)
-	MockListServiceInfo                      func(labelFilters map[string]string) ([]*v1.Service, error)
-	MockListNamespaceInfo                    func(labelFilters map[string]string) ([]*v1.Namespace, error)
-	MockGetServiceInfo                       func(serviceName string) (*v1.Service, error)
-	MockGetPersistentVolumeInfo              func(persistentVolumeName string) (*v1.PersistentVolume, error)
-	MockGetReplicationControllerInfo         func(rcName string) (*v1.ReplicationController, error)
-	MockGetPodInfo                           func(podName string) (*v1.Pod, error)
-	MockGetPodLogs                           func(podName string) ([]byte, error)
-	MockFollowPodLogs                        func(podName string, consumerChannel chan string) (CloseHandle, error)
-	MockDeletePod                            func(podName string) (*v1.Pod, error)
-	MockCheckNamespaceExist                  func(nsName string) (bool, error)
-	MockDeleteNamespaceAndWaitForTermination func(nsName string, maxRetries int, sleepDuration time.Duration) error
-	MockDeleteNamespace                      func(nsName string) error
-	MockDeleteReplicationController          func(rcName string) error
-	MockDeleteService                        func(serviceName string) error
-	MockRunOneOffTask                        func(name string, containerName string, additionalVars []v1.EnvVar) error
-	MockCreatePod                            func(pod *v1.Pod, force bool) (*v1.Pod, error)
-	MockTestVolume                           func(volumeName string) (bool, *v1.PersistentVolume, error)
-	MockTestService                          func(serviceName string) (bool, *v1.Service, error)
-	MockWaitForServiceToStart                func(serviceName string, maxRetries int, sleepDuration time.Duration) (*v1.Service, error)
-	MockDeployReplicationController          func(serviceName string, rc *v1.ReplicationController, force bool) (*v1.ReplicationController, error)
+	MockCreateNamespace                        func(ns *v1.Namespace, force bool) (*v1.Namespace, error)
+	MockCreateReplicationController            func(rc *v1.ReplicationController, force bool) (*v1.ReplicationController, error)
+	MockCheckServiceExists                     func(serviceName string) (bool, error)
+	MockCreateService                          func(svc *v1.Service, force bool) (*v1.Service, error)
+	MockCreatePersistentVolume                 func(pv *v1.PersistentVolume, force bool) (*v1.PersistentVolume, error)
+	MockCreatePersistentVolumeClaim            func(pvc *v1.PersistentVolumeClaim, force bool) (*v1.PersistentVolumeClaim, error)
+	MockListPodsInfo                           func(labelFilters map[string]string) ([]*v1.Pod, error)
+	MockListEntityEvents                       func(entityUid types.UID) ([]*v1.Event, error)
+	MockListEvents                             func(query EventQuery) ([]*v1.Event, error)
+	MockCreateEvent                            func(event *v1.Event) (*v1.Event, error)
+	MockUpdateEvent                            func(event *v1.Event) (*v1.Event, error)
+	MockListServiceInfo                        func(labelFilters map[string]string) ([]*v1.Service, error)
+	MockListNamespaceInfo                      func(labelFilters map[string]string) ([]*v1.Namespace, error)
+	MockGetServiceInfo                         func(serviceName string) (*v1.Service, error)
+	MockGetPersistentVolumeInfo                func(persistentVolumeName string) (*v1.PersistentVolume, error)
+	MockGetPersistentVolumeClaimInfo           func(pvcName string) (*v1.PersistentVolumeClaim, error)
+	MockGetReplicationControllerInfo           func(rcName string) (*v1.ReplicationController, error)
+	MockGetPodInfo                             func(podName string) (*v1.Pod, error)
+	MockGetPodLogs                             func(podName string) ([]byte, error)
+	MockFollowPodLogs                          func(podName string, consumerChannel chan string) (CloseHandle, error)
+	MockDeletePod                              func(podName string) (*v1.Pod, error)
+	MockCheckNamespaceExist                    func(nsName string) (bool, error)
+	MockDeleteNamespaceAndWaitForTermination   func(nsName string, maxRetries int, sleepDuration time.Duration) error
+	MockDeleteNamespace                        func(nsName string) error
+	MockDeleteReplicationController            func(rcName string) error
+	MockDeleteReplicationControllerWithOptions func(rcName string, options *v1.DeleteOptions) error
+	MockScaleReplicationController             func(rcName string, replicas int) (*v1.ReplicationController, error)
+	MockDeleteCollection                       func(resource string, labelFilters map[string]string, options *v1.DeleteOptions) error
+	MockDeletePersistentVolumeClaim            func(pvcName string) error
+	MockDeleteService                          func(serviceName string) error
+	MockRunOneOffTask                          func(name string, containerName string, additionalVars []v1.EnvVar) error
+	MockRunTask                                func(name string, image string, additionalVars []v1.EnvVar, options TaskOptions) (*TaskResult, error)
+	MockCreatePod                              func(pod *v1.Pod, force bool) (*v1.Pod, error)
+	MockTestVolume                             func(volumeName string) (bool, *v1.PersistentVolume, error)
+	MockTestService                            func(serviceName string) (bool, *v1.Service, error)
+	MockWaitForServiceToStart                  func(serviceName string, maxRetries int, sleepDuration time.Duration) (*v1.Service, error)
+	MockDeployReplicationController            func(serviceName string, rc *v1.ReplicationController, force bool) (*v1.ReplicationController, error)
+	MockListNodes                              func(labelFilters map[string]string) ([]*v1.Node, error)
+	MockGetNode                                func(nodeName string) (*v1.Node, error)
+	MockListResourceQuotas                     func(labelFilters map[string]string) ([]*v1.ResourceQuota, error)
+	MockDiff                                   func(desired interface{}) (*ObjectDiff, error)
+	MockCloneNamespace                         func(srcNamespace string, dstNamespace string, options *CloneOptions) ([]*ApplyResult, error)
+	MockImpersonate                            func(userName string, groups []string) ClientInterface
+	MockCopyToPod                              func(podName string, localPath string, remotePath string, options *CopyOptions) error
+	MockCopyFromPod                            func(podName string, remotePath string, localPath string, options *CopyOptions) error
+	MockFindClusterAddress                     func() (string, error)
 }
 
 func (mc *ClientMock) CreateNamespace(ns *v1.Namespace, force bool) (*v1.Namespace, error) {
@@ -69,6 +88,15 @@ func (mc *ClientMock) ListPodsInfo(labelFilters map[string]string) ([]*v1.Pod, e
 func (mc *ClientMock) ListEntityEvents(entityUid types.UID) ([]*v1.Event, error) {
 	return mc.MockListEntityEvents(entityUid)
 }
+func (mc *ClientMock) ListEvents(query EventQuery) ([]*v1.Event, error) {
+	return mc.MockListEvents(query)
+}
+func (mc *ClientMock) CreateEvent(event *v1.Event) (*v1.Event, error) {
+	return mc.MockCreateEvent(event)
+}
+func (mc *ClientMock) UpdateEvent(event *v1.Event) (*v1.Event, error) {
+	return mc.MockUpdateEvent(event)
+}
 func (mc *ClientMock) ListServiceInfo(labelFilters map[string]string) ([]*v1.Service, error) {
 	return mc.MockListServiceInfo(labelFilters)
 }
@@ -78,6 +106,12 @@ func (mc *ClientMock) ListNamespaceInfo(labelFilters map[string]string) ([]*v1.N
 func (mc *ClientMock) GetServiceInfo(serviceName string) (*v1.Service, error) {
 	return mc.MockGetServiceInfo(serviceName)
 }
+func (mc *ClientMock) CreatePersistentVolumeClaim(pvc *v1.PersistentVolumeClaim, force bool) (*v1.PersistentVolumeClaim, error) {
+	return mc.MockCreatePersistentVolumeClaim(pvc, force)
+}
+func (mc *ClientMock) GetPersistentVolumeClaimInfo(pvcName string) (*v1.PersistentVolumeClaim, error) {
+	return mc.MockGetPersistentVolumeClaimInfo(pvcName)
+}
 func (mc *ClientMock) GetPersistentVolumeInfo(persistentVolumeName string) (*v1.PersistentVolume, error) {
 	return mc.MockGetPersistentVolumeInfo(persistentVolumeName)
 }
@@ -108,12 +142,27 @@ func (mc *ClientMock) DeleteNamespace(nsName string) error {
 func (mc *ClientMock) DeleteReplicationController(rcName string) error {
 	return mc.MockDeleteReplicationController(rcName)
 }
+func (mc *ClientMock) DeleteReplicationControllerWithOptions(rcName string, options *v1.DeleteOptions) error {
+	return mc.MockDeleteReplicationControllerWithOptions(rcName, options)
+}
+func (mc *ClientMock) ScaleReplicationController(rcName string, replicas int) (*v1.ReplicationController, error) {
+	return mc.MockScaleReplicationController(rcName, replicas)
+}
+func (mc *ClientMock) DeleteCollection(resource string, labelFilters map[string]string, options *v1.DeleteOptions) error {
+	return mc.MockDeleteCollection(resource, labelFilters, options)
+}
+func (mc *ClientMock) DeletePersistentVolumeClaim(pvcName string) error {
+	return mc.MockDeletePersistentVolumeClaim(pvcName)
+}
 func (mc *ClientMock) DeleteService(serviceName string) error {
 	return mc.MockDeleteService(serviceName)
 }
 func (mc *ClientMock) RunOneOffTask(name string, containerName string, additionalVars []v1.EnvVar) error {
 	return mc.MockRunOneOffTask(name, containerName, additionalVars)
 }
+func (mc *ClientMock) RunTask(name string, image string, additionalVars []v1.EnvVar, options TaskOptions) (*TaskResult, error) {
+	return mc.MockRunTask(name, image, additionalVars, options)
+}
 func (mc *ClientMock) CreatePod(pod *v1.Pod, force bool) (*v1.Pod, error) {
 	return mc.MockCreatePod(pod, force)
 }
@@ -129,3 +178,30 @@ func (mc *ClientMock) WaitForServiceToStart(serviceName string, maxRetries int,
 func (mc *ClientMock) DeployReplicationController(serviceName string, rc *v1.ReplicationController, force bool) (*v1.ReplicationController, error) {
 	return mc.MockDeployReplicationController(serviceName, rc, force)
 }
+func (mc *ClientMock) ListNodes(labelFilters map[string]string) ([]*v1.Node, error) {
+	return mc.MockListNodes(labelFilters)
+}
+func (mc *ClientMock) GetNode(nodeName string) (*v1.Node, error) {
+	return mc.MockGetNode(nodeName)
+}
+func (mc *ClientMock) ListResourceQuotas(labelFilters map[string]string) ([]*v1.ResourceQuota, error) {
+	return mc.MockListResourceQuotas(labelFilters)
+}
+func (mc *ClientMock) Diff(desired interface{}) (*ObjectDiff, error) {
+	return mc.MockDiff(desired)
+}
+func (mc *ClientMock) CloneNamespace(srcNamespace string, dstNamespace string, options *CloneOptions) ([]*ApplyResult, error) {
+	return mc.MockCloneNamespace(srcNamespace, dstNamespace, options)
+}
+func (mc *ClientMock) Impersonate(userName string, groups []string) ClientInterface {
+	return mc.MockImpersonate(userName, groups)
+}
+func (mc *ClientMock) FindClusterAddress() (string, error) {
+	return mc.MockFindClusterAddress()
+}
+func (mc *ClientMock) CopyToPod(podName string, localPath string, remotePath string, options *CopyOptions) error {
+	return mc.MockCopyToPod(podName, localPath, remotePath, options)
+}
+func (mc *ClientMock) CopyFromPod(podName string, remotePath string, localPath string, options *CopyOptions) error {
+	return mc.MockCopyFromPod(podName, remotePath, localPath, options)
+}
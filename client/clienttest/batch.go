@@ -0,0 +1,162 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package clienttest
+
+import (
+	"fmt"
+	"ocopea/kubernetes/client/v1"
+	"strconv"
+)
+
+// Jobs create a pod at a time until one succeeds or the backoff limit is exceeded.
+// Job pods with no script succeed the first time they are read, reading a job advances its active pods
+
+var jobsKey = resourceKey{"/apis/batch/v1", "jobs"}
+var cronJobsKey = resourceKey{"/apis/batch/v1", "cronjobs"}
+
+const jobNameLabel = "job-name"
+const defaultBackoffLimit = 6
+
+var defaultJobPodPhases = []v1.PodPhase{v1.PodRunning, v1.PodSucceeded}
+
+// Creates a job from the job template of the cron job as if it was scheduled now, returns the job name
+func (s *Server) TriggerCronJob(namespace string, cronJobName string) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	cronJobKey := objectKey{cronJobsKey, namespace, cronJobName}
+	cronJob, found := s.objects[cronJobKey]
+	if !found {
+		return "", fmt.Errorf("cron job %s/%s not found", namespace, cronJobName)
+	}
+
+	spec, _ := cronJob["spec"].(map[string]interface{})
+	jobTemplate, _ := spec["jobTemplate"].(map[string]interface{})
+	job := deepCopy(jobTemplate).(map[string]interface{})
+	job["apiVersion"] = "batch/v1"
+	job["kind"] = "Job"
+	metadata := metadataOf(job)
+	metadata["generateName"] = cronJobName + "-"
+	metadata["ownerReferences"] = []interface{}{map[string]interface{}{
+		"apiVersion": "batch/v1",
+		"kind":       "CronJob",
+		"name":       cronJobName,
+		"uid":        stringField(cronJob, "metadata", "uid"),
+		"controller": true,
+	}}
+	created, status := s.create(objectKey{resourceKey: jobsKey, namespace: namespace}, job)
+	if status != nil {
+		return "", fmt.Errorf("failed creating job for cron job %s/%s - %s", namespace, cronJobName, status.Message)
+	}
+
+	jobName := stringField(created, "metadata", "name")
+	cronJob["status"] = map[string]interface{}{
+		"lastScheduleTime": now(),
+		"active":           []interface{}{map[string]interface{}{"kind": "Job", "namespace": namespace, "name": jobName}},
+	}
+	s.store(cronJobKey, cronJob, "MODIFIED")
+	return jobName, nil
+}
+
+// Moves the active pods of the job to their next scripted phase, returns the job. Lock must be held
+func (s *Server) advanceJob(key objectKey) map[string]interface{} {
+	for _, pod := range s.jobPods(key) {
+		podKey := objectKey{resourceKey{"/api/v1", "pods"}, key.namespace, stringField(pod, "metadata", "name")}
+		if state := s.podStates[podKey]; state != nil && isActive(state.phase()) {
+			s.advancePod(podKey)
+		}
+	}
+	s.reconcileJob(key, s.objects[key])
+	return s.objects[key]
+}
+
+// Lock must be held
+func (s *Server) jobPods(key objectKey) []map[string]interface{} {
+	sel := selector{requirement{key: jobNameLabel, operator: "=", values: []string{key.name}}}
+	return s.list(resourceKey{"/api/v1", "pods"}, key.namespace, sel, nil)
+}
+
+func isActive(phase v1.PodPhase) bool {
+	return phase == v1.PodPending || phase == v1.PodRunning
+}
+
+// Counts the pods of the job by phase, creating another pod while the job did not finish. Lock must be held
+func (s *Server) reconcileJob(key objectKey, job map[string]interface{}) {
+	status, _ := job["status"].(map[string]interface{})
+	if status == nil {
+		status = map[string]interface{}{"startTime": now()}
+	}
+	if _, finished := status["conditions"]; finished {
+		return
+	}
+
+	completions := 1
+	if value := stringField(job, "spec", "completions"); value != "" {
+		completions, _ = strconv.Atoi(value)
+	}
+	backoffLimit := defaultBackoffLimit
+	if value := stringField(job, "spec", "backoffLimit"); value != "" {
+		backoffLimit, _ = strconv.Atoi(value)
+	}
+
+	active, succeeded, failed := 0, 0, 0
+	for _, pod := range s.jobPods(key) {
+		switch v1.PodPhase(stringField(pod, "status", "phase")) {
+		case v1.PodSucceeded:
+			succeeded++
+		case v1.PodFailed:
+			failed++
+		default:
+			active++
+		}
+	}
+
+	if succeeded >= completions {
+		status["conditions"] = []interface{}{jobCondition("Complete", "", "")}
+		status["completionTime"] = now()
+	} else if failed > backoffLimit {
+		status["conditions"] = []interface{}{
+			jobCondition("Failed", "BackoffLimitExceeded", "Job has reached the specified backoff limit")}
+	} else if active == 0 {
+		s.createJobPod(key, job)
+		active++
+	}
+	status["active"] = active
+	status["succeeded"] = succeeded
+	status["failed"] = failed
+	if _, finished := status["conditions"]; finished {
+		status["active"] = 0
+	}
+	job["status"] = status
+	s.store(key, job, "MODIFIED")
+}
+
+// Lock must be held
+func (s *Server) createJobPod(key objectKey, job map[string]interface{}) {
+	spec, _ := job["spec"].(map[string]interface{})
+	template, _ := spec["template"].(map[string]interface{})
+	if template == nil {
+		return
+	}
+	pod := deepCopy(template).(map[string]interface{})
+	metadata := metadataOf(pod)
+	delete(metadata, "name")
+	metadata["generateName"] = key.name + "-"
+	labels, _ := metadata["labels"].(map[string]interface{})
+	if labels == nil {
+		labels = make(map[string]interface{})
+		metadata["labels"] = labels
+	}
+	labels[jobNameLabel] = key.name
+	labels["controller-uid"] = stringField(job, "metadata", "uid")
+	s.create(objectKey{resourceKey: resourceKey{"/api/v1", "pods"}, namespace: key.namespace}, pod)
+}
+
+func jobCondition(conditionType string, reason string, message string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":               conditionType,
+		"status":             "True",
+		"lastProbeTime":      now(),
+		"lastTransitionTime": now(),
+		"reason":             reason,
+		"message":            message,
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package clienttest
+
+import (
+	"encoding/json"
+	"net/http"
+	"ocopea/kubernetes/client/protobuf"
+	"ocopea/kubernetes/client/v1"
+	"strings"
+)
+
+// The v1 types of the kinds served in the protobuf wire format when requested. The real api server encodes every
+// built in kind, the client only asks for pods and events
+var protobufTypes = map[string]func() protobuf.Marshaler{
+	"Pod":       func() protobuf.Marshaler { return &v1.Pod{} },
+	"PodList":   func() protobuf.Marshaler { return &v1.PodList{} },
+	"Event":     func() protobuf.Marshaler { return &v1.Event{} },
+	"EventList": func() protobuf.Marshaler { return &v1.EventList{} },
+}
+
+// Returns true in case the request accepts protobuf and objects of the resource can be encoded as protobuf
+func acceptsProtobuf(r *http.Request, p requestPath) bool {
+	return p.apiPath == "/api/v1" && protobufTypes[coreKinds[p.resource]] != nil &&
+		strings.Contains(r.Header.Get("Accept"), protobuf.ContentType)
+}
+
+// Encodes the decoded json of a v1 object of the kind in the protobuf wire format
+func marshalProtobuf(kind string, obj interface{}) ([]byte, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	typed := protobufTypes[kind]()
+	if err = json.Unmarshal(data, typed); err != nil {
+		return nil, err
+	}
+	return protobuf.Marshal(protobuf.TypeMeta{APIVersion: "v1", Kind: kind}, typed), nil
+}
+
+func writeProtobuf(w http.ResponseWriter, kind string, obj interface{}) {
+	data, err := marshalProtobuf(kind, obj)
+	if err != nil {
+		writeStatus(w, badRequest(err.Error()))
+		return
+	}
+	w.Header().Set("Content-Type", protobuf.ContentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
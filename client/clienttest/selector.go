@@ -0,0 +1,143 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package clienttest
+
+import (
+	"fmt"
+	"strings"
+)
+
+type requirement struct {
+	key      string
+	operator string
+	values   []string
+}
+
+// A label or field selector, e.g. "app=orcs,tier!=db,release in (stable,beta),!canary"
+type selector []requirement
+
+func parseSelector(s string) (selector, error) {
+	var sel selector
+	for _, term := range splitTerms(s) {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		var r requirement
+		switch {
+		case strings.HasPrefix(term, "!"):
+			r = requirement{key: strings.TrimSpace(term[1:]), operator: "!"}
+		case strings.Contains(term, "!="):
+			parts := strings.SplitN(term, "!=", 2)
+			r = requirement{key: parts[0], operator: "!=", values: []string{parts[1]}}
+		case strings.Contains(term, "=="):
+			parts := strings.SplitN(term, "==", 2)
+			r = requirement{key: parts[0], operator: "=", values: []string{parts[1]}}
+		case strings.Contains(term, "="):
+			parts := strings.SplitN(term, "=", 2)
+			r = requirement{key: parts[0], operator: "=", values: []string{parts[1]}}
+		case strings.Contains(term, " notin "):
+			parts := strings.SplitN(term, " notin ", 2)
+			values, err := parseSet(parts[1])
+			if err != nil {
+				return nil, err
+			}
+			r = requirement{key: parts[0], operator: "notin", values: values}
+		case strings.Contains(term, " in "):
+			parts := strings.SplitN(term, " in ", 2)
+			values, err := parseSet(parts[1])
+			if err != nil {
+				return nil, err
+			}
+			r = requirement{key: parts[0], operator: "in", values: values}
+		default:
+			r = requirement{key: term, operator: "exists"}
+		}
+		r.key = strings.TrimSpace(r.key)
+		for i := range r.values {
+			r.values[i] = strings.TrimSpace(r.values[i])
+		}
+		if r.key == "" {
+			return nil, fmt.Errorf("invalid selector %s", s)
+		}
+		sel = append(sel, r)
+	}
+	return sel, nil
+}
+
+// Splits a selector on the commas that are not part of a set
+func splitTerms(s string) []string {
+	var terms []string
+	depth := 0
+	start := 0
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				terms = append(terms, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(terms, s[start:])
+}
+
+func parseSet(s string) ([]string, error) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "(") || !strings.HasSuffix(s, ")") {
+		return nil, fmt.Errorf("invalid set %s", s)
+	}
+	return strings.Split(s[1:len(s)-1], ","), nil
+}
+
+func (r requirement) matches(value string, exists bool) bool {
+	switch r.operator {
+	case "exists":
+		return exists
+	case "!":
+		return !exists
+	case "=":
+		return exists && value == r.values[0]
+	case "!=":
+		return !exists || value != r.values[0]
+	case "in":
+		return exists && contains(r.values, value)
+	case "notin":
+		return !exists || !contains(r.values, value)
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (sel selector) matchesLabels(obj map[string]interface{}) bool {
+	labels, _ := metadataOf(obj)["labels"].(map[string]interface{})
+	for _, r := range sel {
+		value, exists := labels[r.key].(string)
+		if !r.matches(value, exists) {
+			return false
+		}
+	}
+	return true
+}
+
+// Fields are matched by their json path, e.g. involvedObject.uid or status.phase
+func (sel selector) matchesFields(obj map[string]interface{}) bool {
+	for _, r := range sel {
+		value := stringField(obj, strings.Split(r.key, ".")...)
+		if !r.matches(value, true) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,877 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+// Package clienttest provides an in-memory fake of the kubernetes api server for tests
+// The server implements the subset of the api the client uses (namespaces, pods, services, replication
+// controllers, persistent volumes, events, logs, watch and proxy) on top of an httptest server, so the client,
+// the deployer and k8spsb can be tested end-to-end without a running cluster.
+// Objects are kept as decoded json, so any kind served under /api/v1 or /apis/{group}/{version} is accepted
+package clienttest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"ocopea/kubernetes/client/protobuf"
+	"ocopea/kubernetes/client/unversioned"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Identifies a kind of resource by the root path it is served from and its plural name, e.g. {"/api/v1", "pods"}
+type resourceKey struct {
+	apiPath  string
+	resource string
+}
+
+type objectKey struct {
+	resourceKey
+	namespace string
+	name      string
+}
+
+type storedEvent struct {
+	resourceVersion int64
+	key             objectKey
+	eventType       string
+	object          map[string]interface{}
+}
+
+type watcher struct {
+	resourceKey
+	namespace     string
+	labelSelector selector
+	fieldSelector selector
+	eventsChannel chan *storedEvent
+	closed        bool
+}
+
+// Server is an in-memory kubernetes api server.
+// Every write is assigned a new resourceVersion, updates with a stale resourceVersion are rejected with a conflict
+type Server struct {
+	*httptest.Server
+
+	lock            sync.Mutex
+	resourceVersion int64
+	objects         map[objectKey]map[string]interface{}
+	history         []*storedEvent
+	watchers        map[*watcher]bool
+	requests        []string
+	stopChannel     chan struct{}
+
+	// handlers serving the proxy subresource of services and pods by object key, see Proxy
+	proxies map[objectKey]http.Handler
+
+	// pod simulation state, see simulation.go
+	podScripts  map[string]PodScript
+	podStates   map[objectKey]*podState
+	allocations int
+}
+
+// Starts a new fake api server, the "default" namespace already exists
+func NewServer() *Server {
+	s := &Server{
+		objects:     make(map[objectKey]map[string]interface{}),
+		watchers:    make(map[*watcher]bool),
+		stopChannel: make(chan struct{}),
+		proxies:     make(map[objectKey]http.Handler),
+		podScripts:  make(map[string]PodScript),
+		podStates:   make(map[objectKey]*podState),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	if err := s.Add("namespaces", map[string]interface{}{"metadata": map[string]interface{}{"name": "default"}}); err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Stops the server, open watches are terminated
+func (s *Server) Close() {
+	s.lock.Lock()
+	select {
+	case <-s.stopChannel:
+	default:
+		close(s.stopChannel)
+	}
+	s.lock.Unlock()
+	s.Server.Close()
+}
+
+// Returns the requests received by the server so far, formatted as "METHOD path"
+func (s *Server) Requests() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]string{}, s.requests...)
+}
+
+// Adds an object of a core resource (e.g. "nodes") as if it was created through the api.
+// The object may be a v1 struct or a decoded json map, the namespace is taken from its metadata
+func (s *Server) Add(resource string, obj interface{}) error {
+	m, err := toMap(obj)
+	if err != nil {
+		return err
+	}
+	key := objectKey{
+		resourceKey: resourceKey{apiPath: "/api/v1", resource: resource},
+		namespace:   stringField(m, "metadata", "namespace"),
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	_, status := s.create(key, m)
+	if status != nil {
+		return fmt.Errorf("Failed adding %s - %s", resource, status.Message)
+	}
+	return nil
+}
+
+// Reads the current state of an object of a core resource into objPtr, returns false if it does not exist
+func (s *Server) Get(resource string, namespace string, name string, objPtr interface{}) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	obj, found := s.objects[objectKey{resourceKey{"/api/v1", resource}, namespace, name}]
+	if !found {
+		return false
+	}
+	data, _ := json.Marshal(obj)
+	return json.Unmarshal(data, objPtr) == nil
+}
+
+// A parsed request path, e.g. /api/v1/namespaces/ns1/pods/pod1/log
+type requestPath struct {
+	objectKey
+	subresource string
+	root        bool
+}
+
+func parsePath(path string) requestPath {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	var rest []string
+	p := requestPath{}
+	switch {
+	case len(segments) >= 2 && segments[0] == "api":
+		p.apiPath = "/api/" + segments[1]
+		rest = segments[2:]
+	case len(segments) >= 3 && segments[0] == "apis":
+		p.apiPath = "/apis/" + segments[1] + "/" + segments[2]
+		rest = segments[3:]
+	}
+	if len(rest) == 0 {
+		p.root = true
+		return p
+	}
+
+	// namespaces/{namespace}/{resource}/... unless it is a namespace subresource
+	if rest[0] == "namespaces" && len(rest) >= 3 && rest[2] != "status" && rest[2] != "finalize" {
+		p.namespace = rest[1]
+		rest = rest[2:]
+	}
+	p.resource = rest[0]
+	if len(rest) > 1 {
+		p.name = rest[1]
+	}
+	if len(rest) > 2 {
+		p.subresource = rest[2]
+	}
+	return p
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+	s.lock.Unlock()
+
+	p := parsePath(r.URL.Path)
+	if p.root {
+		if p.apiPath == "" && r.URL.Path != "/api" && r.URL.Path != "/apis" {
+			writeStatus(w, notFound("", r.URL.Path))
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"kind": "APIVersions", "versions": []string{"v1"}})
+		return
+	}
+
+	query := r.URL.Query()
+	switch {
+	case p.subresource == "proxy":
+		s.serveProxy(w, r, p)
+	case r.Method == "GET" && p.subresource == "log":
+		s.serveLogs(w, p)
+	case r.Method == "GET" && p.name == "" && query.Get("watch") == "true":
+		s.serveWatch(w, r, p)
+	case r.Method == "GET" && p.name == "":
+		s.serveList(w, r, p, query.Get("labelSelector"), query.Get("fieldSelector"))
+	case r.Method == "GET":
+		s.serveGet(w, p)
+	case r.Method == "POST" && p.name == "":
+		s.serveCreate(w, r, p)
+	case r.Method == "PUT" && p.name != "":
+		s.serveUpdate(w, r, p)
+	case r.Method == "PATCH" && p.name != "":
+		s.servePatch(w, r, p)
+	case r.Method == "DELETE" && p.name == "":
+		s.serveDeleteCollection(w, p, query.Get("labelSelector"), query.Get("fieldSelector"))
+	case r.Method == "DELETE":
+		s.serveDelete(w, p)
+	default:
+		writeStatus(w, &unversioned.Status{
+			Status:  unversioned.StatusFailure,
+			Message: fmt.Sprintf("%s is not supported on %s", r.Method, r.URL.Path),
+			Reason:  unversioned.StatusReasonMethodNotAllowed,
+			Code:    http.StatusMethodNotAllowed,
+		})
+	}
+}
+
+func (s *Server) serveGet(w http.ResponseWriter, p requestPath) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	obj, found := s.objects[p.objectKey]
+	if !found {
+		writeStatus(w, notFound(p.resource, p.name))
+		return
+	}
+	if p.resource == "pods" && p.apiPath == "/api/v1" {
+		obj = s.advancePod(p.objectKey)
+	} else if p.resourceKey == jobsKey {
+		obj = s.advanceJob(p.objectKey)
+	}
+	writeJSON(w, http.StatusOK, obj)
+}
+
+// Lists the objects as json, or in the protobuf wire format when accepted by the request, see acceptsProtobuf
+func (s *Server) serveList(w http.ResponseWriter, r *http.Request, p requestPath, labelSelector string, fieldSelector string) {
+	labels, err := parseSelector(labelSelector)
+	if err != nil {
+		writeStatus(w, badRequest(err.Error()))
+		return
+	}
+	fields, err := parseSelector(fieldSelector)
+	if err != nil {
+		writeStatus(w, badRequest(err.Error()))
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	items := s.list(p.resourceKey, p.namespace, labels, fields)
+	list := map[string]interface{}{
+		"kind":       listKind(p.resource, items),
+		"apiVersion": strings.TrimPrefix(strings.TrimPrefix(p.apiPath, "/apis/"), "/api/"),
+		"metadata":   map[string]interface{}{"resourceVersion": strconv.FormatInt(s.resourceVersion, 10)},
+		"items":      items,
+	}
+	if acceptsProtobuf(r, p) {
+		writeProtobuf(w, list["kind"].(string), list)
+		return
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+// Returns the objects of the resource matching the selectors sorted by namespace and name.
+// An empty namespace lists the objects of all namespaces
+func (s *Server) list(rk resourceKey, namespace string, labels selector, fields selector) []map[string]interface{} {
+	keys := make([]objectKey, 0)
+	for key, obj := range s.objects {
+		if key.resourceKey == rk && (namespace == "" || key.namespace == namespace) &&
+			labels.matchesLabels(obj) && fields.matchesFields(obj) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].namespace != keys[j].namespace {
+			return keys[i].namespace < keys[j].namespace
+		}
+		return keys[i].name < keys[j].name
+	})
+	items := make([]map[string]interface{}, 0, len(keys))
+	for _, key := range keys {
+		items = append(items, s.objects[key])
+	}
+	return items
+}
+
+func (s *Server) serveCreate(w http.ResponseWriter, r *http.Request, p requestPath) {
+	obj, err := decodeBody(r.Body)
+	if err != nil {
+		writeStatus(w, badRequest(err.Error()))
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	created, status := s.create(p.objectKey, obj)
+	if status != nil {
+		writeStatus(w, status)
+		return
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// Stores a new object, key holds the resource and namespace the object is created in. Lock must be held
+func (s *Server) create(key objectKey, obj map[string]interface{}) (map[string]interface{}, *unversioned.Status) {
+	metadata := metadataOf(obj)
+	if ns, _ := metadata["namespace"].(string); ns != "" && key.namespace != "" && ns != key.namespace {
+		return nil, badRequest(fmt.Sprintf("the namespace of the object (%s) does not match the namespace of the request (%s)", ns, key.namespace))
+	}
+	if key.namespace != "" {
+		metadata["namespace"] = key.namespace
+		if _, found := s.objects[objectKey{resourceKey{"/api/v1", "namespaces"}, "", key.namespace}]; !found {
+			return nil, notFound("namespaces", key.namespace)
+		}
+	}
+
+	name, _ := metadata["name"].(string)
+	if name == "" {
+		generateName, _ := metadata["generateName"].(string)
+		if generateName == "" {
+			return nil, invalid(key.resource, "", "name or generateName is required")
+		}
+		name = generateName + s.nextSuffix()
+		metadata["name"] = name
+	}
+	key.name = name
+	if _, found := s.objects[key]; found {
+		return nil, &unversioned.Status{
+			Status:  unversioned.StatusFailure,
+			Message: fmt.Sprintf("%s \"%s\" already exists", key.resource, name),
+			Reason:  unversioned.StatusReasonAlreadyExists,
+			Details: &unversioned.StatusDetails{Name: name, Kind: key.resource},
+			Code:    http.StatusConflict,
+		}
+	}
+
+	s.allocations++
+	metadata["uid"] = fmt.Sprintf("00000000-0000-0000-0000-%012d", s.allocations)
+	metadata["creationTimestamp"] = now()
+	metadata["selfLink"] = selfLink(key)
+	s.prepareCreate(key, obj)
+	s.store(key, obj, "ADDED")
+	s.afterWrite(key, obj, true)
+	return obj, nil
+}
+
+func (s *Server) serveUpdate(w http.ResponseWriter, r *http.Request, p requestPath) {
+	obj, err := decodeBody(r.Body)
+	if err != nil {
+		writeStatus(w, badRequest(err.Error()))
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	updated, status := s.update(p.objectKey, obj)
+	if status != nil {
+		writeStatus(w, status)
+		return
+	}
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// Replaces a stored object, a resourceVersion other than the current one is a conflict. Lock must be held
+func (s *Server) update(key objectKey, obj map[string]interface{}) (map[string]interface{}, *unversioned.Status) {
+	existing, found := s.objects[key]
+	if !found {
+		return nil, notFound(key.resource, key.name)
+	}
+	metadata := metadataOf(obj)
+	existingMetadata := metadataOf(existing)
+	if name, _ := metadata["name"].(string); name != "" && name != key.name {
+		return nil, badRequest(fmt.Sprintf("the name of the object (%s) does not match the name of the request (%s)", name, key.name))
+	}
+	if rv, _ := metadata["resourceVersion"].(string); rv != "" && rv != existingMetadata["resourceVersion"] {
+		return nil, &unversioned.Status{
+			Status: unversioned.StatusFailure,
+			Message: fmt.Sprintf(
+				"Operation cannot be fulfilled on %s \"%s\": the object has been modified; please apply your changes to the latest version and try again",
+				key.resource,
+				key.name),
+			Reason:  unversioned.StatusReasonConflict,
+			Details: &unversioned.StatusDetails{Name: key.name, Kind: key.resource},
+			Code:    http.StatusConflict,
+		}
+	}
+
+	// Fields owned by the server are kept as is
+	for _, field := range []string{"name", "namespace", "uid", "creationTimestamp", "selfLink"} {
+		if value, found := existingMetadata[field]; found {
+			metadata[field] = value
+		}
+	}
+	s.store(key, obj, "MODIFIED")
+	s.afterWrite(key, obj, false)
+	return obj, nil
+}
+
+func (s *Server) servePatch(w http.ResponseWriter, r *http.Request, p requestPath) {
+	contentType := r.Header.Get("Content-Type")
+	if contentType != "application/merge-patch+json" && contentType != "application/strategic-merge-patch+json" {
+		writeStatus(w, &unversioned.Status{
+			Status:  unversioned.StatusFailure,
+			Message: fmt.Sprintf("patch content type %s is not supported", contentType),
+			Code:    http.StatusUnsupportedMediaType,
+		})
+		return
+	}
+	patch, err := decodeBody(r.Body)
+	if err != nil {
+		writeStatus(w, badRequest(err.Error()))
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	existing, found := s.objects[p.objectKey]
+	if !found {
+		writeStatus(w, notFound(p.resource, p.name))
+		return
+	}
+
+	// Strategic merge is treated as a plain json merge patch, lists are replaced rather than merged by key
+	patched := mergePatch(deepCopy(existing), patch).(map[string]interface{})
+	updated, status := s.update(p.objectKey, patched)
+	if status != nil {
+		writeStatus(w, status)
+		return
+	}
+	writeJSON(w, http.StatusOK, updated)
+}
+
+func (s *Server) serveDelete(w http.ResponseWriter, p requestPath) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	obj, found := s.objects[p.objectKey]
+	if !found {
+		writeStatus(w, notFound(p.resource, p.name))
+		return
+	}
+	s.delete(p.objectKey)
+	if p.objectKey.resourceKey == podsKey {
+		s.replaceDeletedPod(obj)
+	}
+	writeJSON(w, http.StatusOK, obj)
+}
+
+func (s *Server) serveDeleteCollection(w http.ResponseWriter, p requestPath, labelSelector string, fieldSelector string) {
+	labels, err := parseSelector(labelSelector)
+	if err != nil {
+		writeStatus(w, badRequest(err.Error()))
+		return
+	}
+	fields, err := parseSelector(fieldSelector)
+	if err != nil {
+		writeStatus(w, badRequest(err.Error()))
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	items := s.list(p.resourceKey, p.namespace, labels, fields)
+	for _, item := range items {
+		s.delete(objectKey{p.resourceKey, stringField(item, "metadata", "namespace"), stringField(item, "metadata", "name")})
+	}
+	if p.resourceKey == podsKey {
+		for _, item := range items {
+			s.replaceDeletedPod(item)
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"kind": listKind(p.resource, items), "items": items})
+}
+
+// Removes a stored object, deleting a namespace removes all the objects in it. Lock must be held
+func (s *Server) delete(key objectKey) {
+	obj := s.objects[key]
+	delete(s.objects, key)
+	delete(s.podStates, key)
+	s.store(key, obj, "DELETED")
+
+	if key.resource == "namespaces" && key.namespace == "" {
+		for k := range s.objects {
+			if k.namespace == key.name {
+				s.delete(k)
+			}
+		}
+	}
+}
+
+// Records a write, assigning the object a new resourceVersion and notifying matching watchers. Lock must be held
+func (s *Server) store(key objectKey, obj map[string]interface{}, eventType string) {
+	s.resourceVersion++
+	metadataOf(obj)["resourceVersion"] = strconv.FormatInt(s.resourceVersion, 10)
+	if eventType != "DELETED" {
+		s.objects[key] = obj
+	}
+
+	event := &storedEvent{resourceVersion: s.resourceVersion, key: key, eventType: eventType, object: deepCopy(obj).(map[string]interface{})}
+	s.history = append(s.history, event)
+	for w := range s.watchers {
+		if w.matches(event) {
+			select {
+			case w.eventsChannel <- event:
+			default:
+				// A watcher that does not keep up is dropped, just like the real server closes slow watches
+				s.closeWatcher(w)
+			}
+		}
+	}
+}
+
+func (w *watcher) matches(event *storedEvent) bool {
+	return event.key.resourceKey == w.resourceKey &&
+		(w.namespace == "" || event.key.namespace == w.namespace) &&
+		w.labelSelector.matchesLabels(event.object) &&
+		w.fieldSelector.matchesFields(event.object)
+}
+
+// Lock must be held
+func (s *Server) closeWatcher(w *watcher) {
+	if !w.closed {
+		w.closed = true
+		close(w.eventsChannel)
+		delete(s.watchers, w)
+	}
+}
+
+// Streams watch events as json objects, or as protobuf frames when accepted by the request. Without a resourceVersion
+// the current objects are sent as ADDED events first, otherwise all events that happened after the given
+// resourceVersion are replayed
+func (s *Server) serveWatch(w http.ResponseWriter, r *http.Request, p requestPath) {
+	query := r.URL.Query()
+	labels, err := parseSelector(query.Get("labelSelector"))
+	if err != nil {
+		writeStatus(w, badRequest(err.Error()))
+		return
+	}
+	fields, err := parseSelector(query.Get("fieldSelector"))
+	if err != nil {
+		writeStatus(w, badRequest(err.Error()))
+		return
+	}
+	var fromResourceVersion int64
+	if rv := query.Get("resourceVersion"); rv != "" {
+		fromResourceVersion, err = strconv.ParseInt(rv, 10, 64)
+		if err != nil {
+			writeStatus(w, badRequest("invalid resourceVersion "+rv))
+			return
+		}
+	}
+
+	wt := &watcher{
+		resourceKey:   p.resourceKey,
+		namespace:     p.namespace,
+		labelSelector: labels,
+		fieldSelector: fields,
+		eventsChannel: make(chan *storedEvent, 1000),
+	}
+
+	s.lock.Lock()
+	var initialEvents []*storedEvent
+	if fromResourceVersion == 0 {
+		for _, obj := range s.list(p.resourceKey, p.namespace, labels, fields) {
+			initialEvents = append(initialEvents, &storedEvent{eventType: "ADDED", object: deepCopy(obj).(map[string]interface{})})
+		}
+	} else {
+		for _, event := range s.history {
+			if event.resourceVersion > fromResourceVersion && wt.matches(event) {
+				initialEvents = append(initialEvents, event)
+			}
+		}
+	}
+	s.watchers[wt] = true
+	s.lock.Unlock()
+
+	defer func() {
+		s.lock.Lock()
+		s.closeWatcher(wt)
+		s.lock.Unlock()
+	}()
+
+	useProtobuf := acceptsProtobuf(r, p)
+	if useProtobuf {
+		w.Header().Set("Content-Type", protobuf.ContentType+";stream=watch")
+	} else {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	send := func(event *storedEvent) bool {
+		if useProtobuf {
+			object, err := marshalProtobuf(coreKinds[p.resource], event.object)
+			if err != nil || protobuf.WriteWatchEvent(w, &protobuf.WatchEvent{Type: event.eventType, Object: object}) != nil {
+				return false
+			}
+		} else {
+			data, _ := json.Marshal(map[string]interface{}{"type": event.eventType, "object": event.object})
+			if _, err := w.Write(append(data, '\n')); err != nil {
+				return false
+			}
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return true
+	}
+
+	for _, event := range initialEvents {
+		if !send(event) {
+			return
+		}
+	}
+	if flusher != nil {
+		flusher.Flush()
+	}
+	for {
+		select {
+		case event, ok := <-wt.eventsChannel:
+			if !ok || !send(event) {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		case <-s.stopChannel:
+			return
+		}
+	}
+}
+
+func (s *Server) serveLogs(w http.ResponseWriter, p requestPath) {
+	s.lock.Lock()
+	_, found := s.objects[p.objectKey]
+	var logs string
+	if state := s.podStates[p.objectKey]; state != nil {
+		logs = state.logs
+	}
+	s.lock.Unlock()
+
+	if !found {
+		writeStatus(w, notFound(p.resource, p.name))
+		return
+	}
+
+	// Following logs returns everything logged so far and ends the stream, as if the container exited
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, logs)
+}
+
+// Serves requests sent through the proxy subresource of the service or pod (e.g. "services", "orcs") with the
+// handler, as if the handler was listening in the cluster. The handler sees the path following the proxy subresource
+func (s *Server) Proxy(resource string, namespace string, name string, handler http.Handler) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.proxies[objectKey{resourceKey{"/api/v1", resource}, namespace, name}] = handler
+}
+
+func (s *Server) serveProxy(w http.ResponseWriter, r *http.Request, p requestPath) {
+	// The name may be followed by a port, e.g. orcs:8080
+	key := p.objectKey
+	key.name = strings.SplitN(p.name, ":", 2)[0]
+	s.lock.Lock()
+	_, found := s.objects[key]
+	handler := s.proxies[key]
+	s.lock.Unlock()
+
+	if !found {
+		writeStatus(w, notFound(p.resource, key.name))
+		return
+	}
+	if handler == nil {
+		writeStatus(w, &unversioned.Status{
+			Status:  unversioned.StatusFailure,
+			Message: fmt.Sprintf("no endpoints available for %s %q", p.resource, key.name),
+			Reason:  unversioned.StatusReasonServiceUnavailable,
+			Code:    http.StatusServiceUnavailable,
+		})
+		return
+	}
+
+	proxied := new(http.Request)
+	*proxied = *r
+	proxiedUrl := *r.URL
+	proxied.URL = &proxiedUrl
+	proxyPath := "/namespaces/" + p.namespace + "/" + p.resource + "/" + p.name + "/proxy"
+	proxied.URL.Path = "/" + strings.TrimPrefix(r.URL.Path[strings.Index(r.URL.Path, proxyPath)+len(proxyPath):], "/")
+	proxied.URL.RawPath = ""
+	handler.ServeHTTP(w, proxied)
+}
+
+func (s *Server) nextSuffix() string {
+	s.allocations++
+	const alphabet = "bcdfghjklmnpqrstvwxz2456789"
+	suffix := make([]byte, 5)
+	n := s.allocations
+	for i := len(suffix) - 1; i >= 0; i-- {
+		suffix[i] = alphabet[n%len(alphabet)]
+		n /= len(alphabet)
+	}
+	return string(suffix)
+}
+
+func selfLink(key objectKey) string {
+	if key.namespace == "" {
+		return key.apiPath + "/" + key.resource + "/" + key.name
+	}
+	return key.apiPath + "/namespaces/" + key.namespace + "/" + key.resource + "/" + key.name
+}
+
+var coreKinds = map[string]string{
+	"namespaces":             "Namespace",
+	"pods":                   "Pod",
+	"services":               "Service",
+	"replicationcontrollers": "ReplicationController",
+	"persistentvolumes":      "PersistentVolume",
+	"events":                 "Event",
+	"nodes":                  "Node",
+}
+
+func listKind(resource string, items []map[string]interface{}) string {
+	if kind, found := coreKinds[resource]; found {
+		return kind + "List"
+	}
+	if len(items) > 0 {
+		if kind, _ := items[0]["kind"].(string); kind != "" {
+			return kind + "List"
+		}
+	}
+	return "List"
+}
+
+func notFound(resource string, name string) *unversioned.Status {
+	return &unversioned.Status{
+		Status:  unversioned.StatusFailure,
+		Message: fmt.Sprintf("%s \"%s\" not found", resource, name),
+		Reason:  unversioned.StatusReasonNotFound,
+		Details: &unversioned.StatusDetails{Name: name, Kind: resource},
+		Code:    http.StatusNotFound,
+	}
+}
+
+func badRequest(message string) *unversioned.Status {
+	return &unversioned.Status{
+		Status:  unversioned.StatusFailure,
+		Message: message,
+		Reason:  unversioned.StatusReasonBadRequest,
+		Code:    http.StatusBadRequest,
+	}
+}
+
+func invalid(resource string, name string, message string) *unversioned.Status {
+	return &unversioned.Status{
+		Status:  unversioned.StatusFailure,
+		Message: fmt.Sprintf("%s \"%s\" is invalid: %s", resource, name, message),
+		Reason:  unversioned.StatusReasonInvalid,
+		Details: &unversioned.StatusDetails{Name: name, Kind: resource},
+		Code:    http.StatusUnprocessableEntity,
+	}
+}
+
+func writeStatus(w http.ResponseWriter, status *unversioned.Status) {
+	status.Kind = "Status"
+	status.APIVersion = "v1"
+	writeJSON(w, status.Code, status)
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(obj)
+}
+
+func decodeBody(body io.Reader) (map[string]interface{}, error) {
+	dec := json.NewDecoder(body)
+	dec.UseNumber()
+	var obj map[string]interface{}
+	if err := dec.Decode(&obj); err != nil {
+		return nil, fmt.Errorf("failed decoding request body - %s", err.Error())
+	}
+	if obj == nil {
+		return nil, fmt.Errorf("request body is not an object")
+	}
+	return obj, nil
+}
+
+// Converts a v1 struct to its decoded json representation
+func toMap(obj interface{}) (map[string]interface{}, error) {
+	if m, ok := obj.(map[string]interface{}); ok {
+		return deepCopy(m).(map[string]interface{}), nil
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	return decodeBody(bytes.NewReader(data))
+}
+
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			c[k] = deepCopy(item)
+		}
+		return c
+	case []interface{}:
+		c := make([]interface{}, len(v))
+		for i, item := range v {
+			c[i] = deepCopy(item)
+		}
+		return c
+	default:
+		return v
+	}
+}
+
+// Applies a json merge patch (RFC 7386) on the target
+func mergePatch(target interface{}, patch interface{}) interface{} {
+	patchMap, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetMap, ok := target.(map[string]interface{})
+	if !ok {
+		targetMap = make(map[string]interface{})
+	}
+	for k, v := range patchMap {
+		if v == nil {
+			delete(targetMap, k)
+		} else {
+			targetMap[k] = mergePatch(targetMap[k], v)
+		}
+	}
+	return targetMap
+}
+
+// Returns the metadata of the object, creating it when missing
+func metadataOf(obj map[string]interface{}) map[string]interface{} {
+	metadata, ok := obj["metadata"].(map[string]interface{})
+	if !ok {
+		metadata = make(map[string]interface{})
+		obj["metadata"] = metadata
+	}
+	return metadata
+}
+
+// Returns the value found in the nested fields path of the object, formatted as a string
+func stringField(obj map[string]interface{}, fields ...string) string {
+	var value interface{} = obj
+	for _, field := range fields {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		value = m[field]
+	}
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
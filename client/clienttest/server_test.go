@@ -0,0 +1,1098 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package clienttest_test
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"ocopea/kubernetes/client"
+	batchv1 "ocopea/kubernetes/client/batch/v1"
+	"ocopea/kubernetes/client/clienttest"
+	"ocopea/kubernetes/client/resource"
+	"ocopea/kubernetes/client/types"
+	"ocopea/kubernetes/client/v1"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T, s *clienttest.Server) *client.Client {
+	c, err := client.NewClient(s.URL, "ocopea", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.CreateNamespace(&v1.Namespace{ObjectMeta: v1.ObjectMeta{Name: "ocopea"}}, false); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func orcsReplicationController(name string) *v1.ReplicationController {
+	replicas := 1
+	return &v1.ReplicationController{
+		ObjectMeta: v1.ObjectMeta{Name: name},
+		Spec: v1.ReplicationControllerSpec{
+			Replicas: &replicas,
+			Selector: map[string]string{"app": name},
+			Template: &v1.PodTemplateSpec{
+				ObjectMeta: v1.ObjectMeta{Labels: map[string]string{"app": name}},
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{{Name: name, Image: "ocopea/orcs-k8s-runner"}},
+				},
+			},
+		},
+	}
+}
+
+func TestDeployReplicationController(t *testing.T) {
+	s := clienttest.NewServer()
+	defer s.Close()
+	s.ScriptPods("orcs-", clienttest.PodScript{Phases: []v1.PodPhase{v1.PodPending, v1.PodRunning}})
+	c := newTestClient(t, s)
+
+	rc, err := c.DeployReplicationController("orcs", orcsReplicationController("orcs"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rc.Status.Replicas != 1 {
+		t.Errorf("expected 1 replica, got %d", rc.Status.Replicas)
+	}
+
+	pods, err := c.ListPodsInfo(map[string]string{"app": "orcs"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pods) != 1 || !strings.HasPrefix(pods[0].Name, "orcs-") || pods[0].Status.Phase != v1.PodRunning {
+		t.Fatalf("expected a single running pod scheduled by the rc, got %v", pods)
+	}
+
+	events, err := c.ListEntityEvents(pods[0].UID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reasons []string
+	for _, event := range events {
+		reasons = append(reasons, event.Reason)
+	}
+	if strings.Join(reasons, ",") != "Scheduled,Pulling,Started" {
+		t.Errorf("unexpected pod events %v", reasons)
+	}
+
+	// Like the real server of the time, pods outlive their replication controller unless it is scaled down first
+	if err = c.DeleteReplicationControllerWithOptions("orcs", client.NewDeleteOptions(-1, v1.DeletePropagationOrphan)); err != nil {
+		t.Fatal(err)
+	}
+	if !s.Get("pods", "ocopea", pods[0].Name, &v1.Pod{}) {
+		t.Errorf("expected pod %s to remain after deleting the rc", pods[0].Name)
+	}
+
+	if err = c.DeleteNamespaceAndWaitForTermination("ocopea", 3, time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if s.Get("pods", "ocopea", pods[0].Name, &v1.Pod{}) {
+		t.Errorf("expected pod %s to be deleted with its namespace", pods[0].Name)
+	}
+}
+
+func TestDeployReplicationControllerImagePullFailure(t *testing.T) {
+	s := clienttest.NewServer()
+	defer s.Close()
+	s.ScriptPods("broken-", clienttest.PodScript{Phases: []v1.PodPhase{v1.PodPending}, WaitingReason: "ErrImagePull"})
+	c := newTestClient(t, s)
+
+	_, err := c.DeployReplicationController("broken", orcsReplicationController("broken"), false)
+	if err == nil || !strings.Contains(err.Error(), "failed pulling image") {
+		t.Errorf("expected image pull failure, got %v", err)
+	}
+}
+
+func TestRunOneOffTask(t *testing.T) {
+	s := clienttest.NewServer()
+	defer s.Close()
+	s.ScriptPods("task", clienttest.PodScript{Phases: []v1.PodPhase{v1.PodPending, v1.PodSucceeded}, Logs: "done\n"})
+	c := newTestClient(t, s)
+
+	if err := c.RunOneOffTask("task", "ocopea/bootstrap", nil); err != nil {
+		t.Fatal(err)
+	}
+	if s.Get("pods", "ocopea", "task", &v1.Pod{}) {
+		t.Errorf("expected task pod to be deleted")
+	}
+}
+
+type lineCollector struct {
+	lines []string
+}
+
+func (l *lineCollector) Consume(message string) {
+	l.lines = append(l.lines, message)
+}
+
+func TestRunTask(t *testing.T) {
+	s := clienttest.NewServer()
+	defer s.Close()
+	s.ScriptPods("migrate", clienttest.PodScript{
+		Phases: []v1.PodPhase{v1.PodPending, v1.PodRunning, v1.PodSucceeded},
+		Logs:   "step 1\nstep 2\n",
+	})
+	s.ScriptPods("broken", clienttest.PodScript{Phases: []v1.PodPhase{v1.PodRunning, v1.PodFailed}, ExitCode: 3, Logs: "oops\n"})
+	c := newTestClient(t, s)
+
+	collector := &lineCollector{}
+	result, err := c.RunTask("migrate", "ocopea/migrate", nil, client.TaskOptions{
+		Timeout:            time.Minute,
+		ServiceAccountName: "migrator",
+		Output:             collector,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Succeeded() || result.ExitCode != 0 || result.Reason != "Completed" || result.Output != "step 1\nstep 2\n" {
+		t.Errorf("unexpected task result %+v", result)
+	}
+	if strings.Join(collector.lines, ",") != "step 1,step 2" {
+		t.Errorf("expected the logs to be streamed line by line, got %v", collector.lines)
+	}
+	if s.Get("pods", "ocopea", "migrate", &v1.Pod{}) {
+		t.Errorf("expected the pod of the successful task to be deleted")
+	}
+
+	result, err = c.RunTask("broken", "ocopea/migrate", nil, client.TaskOptions{KeepPodOnFailure: true})
+	if err == nil || result == nil || result.ExitCode != 3 || result.Phase != v1.PodFailed || result.Output != "oops\n" {
+		t.Errorf("expected the task to fail with exit code 3, got %+v - %v", result, err)
+	}
+	pod := &v1.Pod{}
+	if !s.Get("pods", "ocopea", "broken", pod) {
+		t.Fatalf("expected the pod of the failed task to be kept")
+	}
+	if pod.Spec.Containers[0].Name != "task" || pod.Labels["app"] != "broken" {
+		t.Errorf("unexpected task pod %+v", pod.ObjectMeta)
+	}
+}
+
+func TestService(t *testing.T) {
+	s := clienttest.NewServer()
+	defer s.Close()
+	c := newTestClient(t, s)
+
+	svc := &v1.Service{
+		ObjectMeta: v1.ObjectMeta{Name: "orcs"},
+		Spec: v1.ServiceSpec{
+			Type:     v1.ServiceTypeNodePort,
+			Selector: map[string]string{"app": "orcs"},
+			Ports:    []v1.ServicePort{{Port: 80, TargetPort: types.NewIntOrStringFromInt(8080)}},
+		},
+	}
+	if _, err := c.CreateService(svc, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CreateService(svc, false); err == nil {
+		t.Errorf("expected creating an existing service to fail")
+	}
+
+	ready, created, err := c.TestService("orcs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ready || created.Spec.ClusterIP == "" || created.Spec.Ports[0].NodePort == 0 {
+		t.Errorf("expected service with allocated addresses, got %+v", created.Spec)
+	}
+}
+
+func TestUpdateConflict(t *testing.T) {
+	s := clienttest.NewServer()
+	defer s.Close()
+	c := newTestClient(t, s)
+	configMaps := c.Resource(client.GroupVersionResource{Version: "v1", Resource: "configmaps"}, true)
+
+	cm := client.Unstructured{"kind": "ConfigMap", "data": map[string]interface{}{"k": "v1"}}
+	cm.SetName("conf")
+	created, err := configMaps.Create(cm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client.SetNestedField(created, "v2", "data", "k")
+	updated, err := configMaps.Update(created)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.GetResourceVersion() == created.GetResourceVersion() {
+		t.Errorf("expected update to assign a new resourceVersion")
+	}
+
+	// created holds the resourceVersion before the update
+	if _, err = configMaps.Update(created); !client.IsConflict(err) {
+		t.Errorf("expected conflict updating a stale object, got %v", err)
+	}
+
+	patched, err := configMaps.Patch("conf", client.MergePatchType, []byte(`{"data":{"k":"v3"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.NestedString(patched, "data", "k") != "v3" {
+		t.Errorf("expected patched value, got %v", patched)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	s := clienttest.NewServer()
+	defer s.Close()
+	c := newTestClient(t, s)
+
+	events := make(chan client.UnstructuredWatchEvent, 10)
+	closeHandle, err := c.Resource(client.GroupVersionResource{Version: "v1", Resource: "pods"}, true).
+		Watch(map[string]string{"app": "watched"}, "", events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeHandle()
+
+	for _, name := range []string{"ignored", "watched"} {
+		pod := &v1.Pod{
+			ObjectMeta: v1.ObjectMeta{Name: name, Labels: map[string]string{"app": name}},
+			Spec:       v1.PodSpec{Containers: []v1.Container{{Name: name, Image: "busybox"}}},
+		}
+		if _, err = c.CreatePod(pod, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err = c.DeletePod("watched"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expectedType := range []client.WatchEventType{client.WatchAdded, client.WatchDeleted} {
+		select {
+		case event := <-events:
+			if event.Type != expectedType || event.Object.GetName() != "watched" {
+				t.Errorf("unexpected watch event %s on %s", event.Type, event.Object.GetName())
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %s watch event", expectedType)
+		}
+	}
+}
+
+func TestWatchPods(t *testing.T) {
+	for _, useProtobuf := range []bool{false, true} {
+		s := clienttest.NewServer()
+		c := newTestClient(t, s)
+		c.Protobuf = useProtobuf
+
+		events := make(chan client.PodWatchEvent, 10)
+		closeHandle, err := c.WatchPods(map[string]string{"app": "watched"}, "", events)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range []string{"ignored", "watched"} {
+			pod := &v1.Pod{
+				ObjectMeta: v1.ObjectMeta{Name: name, Labels: map[string]string{"app": name}},
+				Spec:       v1.PodSpec{Containers: []v1.Container{{Name: name, Image: "busybox"}}},
+			}
+			if _, err = c.CreatePod(pod, false); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err = c.DeletePod("watched"); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, expectedType := range []client.WatchEventType{client.WatchAdded, client.WatchDeleted} {
+			select {
+			case event := <-events:
+				if event.Type != expectedType || event.Object.Name != "watched" ||
+					event.Object.Spec.Containers[0].Image != "busybox" {
+					t.Errorf("unexpected watch event %s on %+v, protobuf %v", event.Type, event.Object, useProtobuf)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("timed out waiting for %s watch event, protobuf %v", expectedType, useProtobuf)
+			}
+		}
+		closeHandle()
+		s.Close()
+	}
+}
+
+func TestWatchEvents(t *testing.T) {
+	s := clienttest.NewServer()
+	defer s.Close()
+	c := newTestClient(t, s)
+	c.Protobuf = true
+
+	events := make(chan client.EventWatchEvent, 10)
+	closeHandle, err := c.WatchEvents(client.EventQuery{Type: v1.EventTypeWarning}, "", events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeHandle()
+
+	recorder := client.NewEventRecorder(c, "k8spsb")
+	ref := &v1.ObjectReference{Kind: "ReplicationController", Namespace: "ocopea", Name: "orcs"}
+	if _, err = recorder.Event(ref, v1.EventTypeNormal, "AppServiceDeployed", "deployed orcs"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = recorder.Event(ref, v1.EventTypeWarning, "AppServiceFailed", "failed orcs"); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case event := <-events:
+		if event.Type != client.WatchAdded || event.Object.Reason != "AppServiceFailed" ||
+			event.Object.InvolvedObject.Name != "orcs" || event.Object.Count != 1 {
+			t.Errorf("expected the warning to be added, got %s %+v", event.Type, event.Object)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the warning")
+	}
+}
+
+func TestProtobuf(t *testing.T) {
+	s := clienttest.NewServer()
+	defer s.Close()
+	jsonClient := newTestClient(t, s)
+	protobufClient, err := client.NewClient(s.URL, "ocopea", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	protobufClient.Protobuf = true
+
+	for _, name := range []string{"orcs", "hub"} {
+		pod := &v1.Pod{
+			ObjectMeta: v1.ObjectMeta{Name: name, Labels: map[string]string{"app": name}},
+			Spec: v1.PodSpec{Containers: []v1.Container{{
+				Name:  name,
+				Image: "ocopea/" + name,
+				Ports: []v1.ContainerPort{{ContainerPort: 8080}},
+				Resources: v1.ResourceRequirements{
+					Limits: v1.ResourceList{v1.ResourceMemory: resource.MustParse("1Gi")},
+				},
+			}}},
+		}
+		if _, err = jsonClient.CreatePod(pod, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	recorder := client.NewEventRecorder(jsonClient, "k8spsb")
+	ref := &v1.ObjectReference{Kind: "Pod", Namespace: "ocopea", Name: "orcs"}
+	if _, err = recorder.Event(ref, v1.EventTypeWarning, "BackOff", "restarting orcs"); err != nil {
+		t.Fatal(err)
+	}
+
+	jsonPods, err := jsonClient.ListPodsInfo(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	protobufPods, err := protobufClient.ListPodsInfo(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(protobufPods) != 2 || !reflect.DeepEqual(jsonPods, protobufPods) {
+		t.Errorf("expected the pods listed as protobuf to equal the json ones\n%+v\n%+v", jsonPods, protobufPods)
+	}
+	jsonEvents, err := jsonClient.ListEvents(client.EventQuery{InvolvedObjectName: "orcs", Reason: "BackOff"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	protobufEvents, err := protobufClient.ListEvents(client.EventQuery{InvolvedObjectName: "orcs", Reason: "BackOff"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(protobufEvents) != 1 || protobufEvents[0].Reason != "BackOff" || !reflect.DeepEqual(jsonEvents, protobufEvents) {
+		t.Errorf("expected the BackOff event listed as protobuf to equal the json one, got %+v", protobufEvents)
+	}
+
+	// Kinds the client doesn't decode as protobuf are served as json
+	req, _ := http.NewRequest("GET", s.URL+"/api/v1/namespaces/ocopea/services", nil)
+	req.Header.Set("Accept", "application/vnd.kubernetes.protobuf, application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if contentType := resp.Header.Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("expected services to be listed as json, got %s", contentType)
+	}
+}
+
+func TestPodLogs(t *testing.T) {
+	s := clienttest.NewServer()
+	defer s.Close()
+	c := newTestClient(t, s)
+
+	pod := &v1.Pod{
+		ObjectMeta: v1.ObjectMeta{Name: "logger"},
+		Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "logger", Image: "busybox"}}},
+	}
+	if _, err := c.CreatePod(pod, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetPodLogs("ocopea", "logger", "line1\nline2\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	logs, err := c.GetPodLogs("logger")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(logs) != "line1\nline2\n" {
+		t.Errorf("unexpected logs %q", logs)
+	}
+
+	lines := make(chan string, 2)
+	closeHandle, err := c.FollowPodLogs("logger", lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeHandle()
+	for _, expected := range []string{"line1\n", "line2\n"} {
+		select {
+		case line := <-lines:
+			if line != expected {
+				t.Errorf("expected log line %q, got %q", expected, line)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for log line %q", expected)
+		}
+	}
+}
+
+func TestEvents(t *testing.T) {
+	s := clienttest.NewServer()
+	defer s.Close()
+	c := newTestClient(t, s)
+
+	recorder := client.NewEventRecorder(c, "k8spsb")
+	ref := &v1.ObjectReference{Kind: "ReplicationController", Namespace: "ocopea", Name: "orcs"}
+	for i := 0; i < 2; i++ {
+		if _, err := recorder.Event(ref, v1.EventTypeNormal, "AppServiceDeployed", "deployed orcs"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := recorder.Eventf(ref, v1.EventTypeWarning, "AppServiceFailed", "failed %s", "orcs"); err != nil {
+		t.Fatal(err)
+	}
+	otherRef := &v1.ObjectReference{Kind: "Service", Namespace: "ocopea", Name: "orcs"}
+	if _, err := recorder.Event(otherRef, v1.EventTypeNormal, "AppServiceDeployed", "deployed orcs"); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := c.ListEvents(client.EventQuery{InvolvedObjectKind: "ReplicationController", InvolvedObjectName: "orcs"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[0].Count != 2 || events[0].Source.Component != "k8spsb" || events[1].Message != "failed orcs" {
+		t.Fatalf("expected a repeated event and a warning, got %+v", events)
+	}
+
+	warnings, err := c.ListEvents(client.EventQuery{Type: v1.EventTypeWarning})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 1 || warnings[0].Reason != "AppServiceFailed" {
+		t.Errorf("expected a single warning, got %+v", warnings)
+	}
+
+	if future, _ := c.ListEvents(client.EventQuery{Since: time.Now().Add(time.Hour)}); len(future) != 0 {
+		t.Errorf("expected no events since an hour from now, got %+v", future)
+	}
+	if other, _ := c.ListEvents(client.EventQuery{Namespace: "default"}); len(other) != 0 {
+		t.Errorf("expected no events in another namespace, got %+v", other)
+	}
+}
+
+func TestDeleteReplicationController(t *testing.T) {
+	s := clienttest.NewServer()
+	defer s.Close()
+	c := newTestClient(t, s)
+
+	for _, name := range []string{"orcs", "hub"} {
+		if _, err := c.DeployReplicationController(name, orcsReplicationController(name), false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := c.DeleteReplicationController("orcs"); err != nil {
+		t.Fatal(err)
+	}
+	if pods, _ := c.ListPodsInfo(map[string]string{"app": "orcs"}); len(pods) != 0 {
+		t.Errorf("expected the pods of the deleted rc to be deleted, got %d pods", len(pods))
+	}
+	if _, err := c.GetReplicationControllerInfo("orcs"); err == nil {
+		t.Errorf("expected the rc to be deleted")
+	}
+
+	// The fake server does not garbage collect the pods of deleted replication controllers, like older api servers
+	background := client.NewDeleteOptions(-1, v1.DeletePropagationBackground)
+	if _, err := c.DeployReplicationController("web", orcsReplicationController("web"), false); err != nil {
+		t.Fatal(err)
+	}
+	if pods, _ := c.ListPodsInfo(map[string]string{"app": "web"}); len(pods) != 1 {
+		t.Fatalf("expected the rc to create a pod, got %d pods", len(pods))
+	}
+	if err := c.DeleteReplicationControllerWithOptions("web", background); err != nil {
+		t.Fatal(err)
+	}
+	if pods, _ := c.ListPodsInfo(map[string]string{"app": "web"}); len(pods) != 0 {
+		t.Errorf("expected no pods left after a background delete, got %d pods", len(pods))
+	}
+
+	orphan := client.NewDeleteOptions(0, v1.DeletePropagationOrphan)
+	if err := c.DeleteReplicationControllerWithOptions("hub", orphan); err != nil {
+		t.Fatal(err)
+	}
+	if pods, _ := c.ListPodsInfo(map[string]string{"app": "hub"}); len(pods) != 1 {
+		t.Fatalf("expected the pods of the orphaning rc to be left behind, got %d pods", len(pods))
+	}
+	if err := c.DeleteCollection("pods", map[string]string{"app": "hub"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if pods, _ := c.ListPodsInfo(nil); len(pods) != 0 {
+		t.Errorf("expected the pods to be deleted by label, got %d pods", len(pods))
+	}
+}
+
+func taskJob(name string, backoffLimit int) *batchv1.Job {
+	return &batchv1.Job{
+		ObjectMeta: v1.ObjectMeta{Name: name},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					RestartPolicy: v1.RestartPolicyNever,
+					Containers:    []v1.Container{{Name: "task", Image: "ocopea/copy-refresh"}},
+				},
+			},
+		},
+	}
+}
+
+func TestJobs(t *testing.T) {
+	s := clienttest.NewServer()
+	defer s.Close()
+	s.ScriptPods("failing-", clienttest.PodScript{Phases: []v1.PodPhase{v1.PodRunning, v1.PodFailed}, Logs: "boom"})
+	c := newTestClient(t, s)
+
+	if _, err := c.CreateJob(taskJob("refresh", 1), false); err != nil {
+		t.Fatal(err)
+	}
+	job, err := c.WaitForJob("refresh", 10*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Status.Succeeded != 1 || job.Status.CompletionTime == nil {
+		t.Errorf("expected the job to complete, got %+v", job.Status)
+	}
+
+	if _, err = c.CreateJob(taskJob("failing", 1), false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.WaitForJob("failing", 10*time.Second); err == nil || !strings.Contains(err.Error(), "BackoffLimitExceeded") {
+		t.Errorf("expected the job to exceed its backoff limit, got %v", err)
+	}
+	logs, err := c.GetJobLogs("failing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 2 {
+		t.Errorf("expected logs of the 2 failed pods, got %d", len(logs))
+	}
+	for podName, podLogs := range logs {
+		if string(podLogs) != "boom" {
+			t.Errorf("unexpected logs of pod %s: %s", podName, podLogs)
+		}
+	}
+
+	if jobs, _ := c.ListJobs(nil); len(jobs) != 2 {
+		t.Errorf("expected 2 jobs, got %d", len(jobs))
+	}
+	if err = c.DeleteJob("failing"); err != nil {
+		t.Fatal(err)
+	}
+	if pods, _ := c.ListJobPods("failing"); len(pods) != 0 {
+		t.Errorf("expected the pods of the deleted job to be deleted, got %d pods", len(pods))
+	}
+}
+
+func TestCronJobs(t *testing.T) {
+	s := clienttest.NewServer()
+	defer s.Close()
+	c := newTestClient(t, s)
+
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: v1.ObjectMeta{Name: "nightly"},
+		Spec: batchv1.CronJobSpec{
+			Schedule:          "0 2 * * *",
+			ConcurrencyPolicy: batchv1.ForbidConcurrent,
+			JobTemplate:       batchv1.JobTemplateSpec{Spec: taskJob("", 3).Spec},
+		},
+	}
+	if _, err := c.CreateCronJob(cronJob, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CreateCronJob(cronJob, true); err != nil {
+		t.Errorf("expected force create of an existing cron job to succeed, got %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := s.TriggerCronJob("ocopea", "nightly"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	history, err := c.ListCronJobHistory("nightly")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 || !strings.HasPrefix(history[0].Name, "nightly-") {
+		t.Fatalf("expected 2 jobs created by the cron job, got %d", len(history))
+	}
+
+	suspended, err := c.SuspendCronJob("nightly", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if suspended.Spec.Suspend == nil || !*suspended.Spec.Suspend || suspended.Spec.Schedule != "0 2 * * *" {
+		t.Errorf("expected the cron job to be suspended, got %+v", suspended.Spec)
+	}
+
+	if err = c.DeleteCronJob("nightly"); err != nil {
+		t.Fatal(err)
+	}
+	if jobs, _ := c.ListJobs(nil); len(jobs) != 0 {
+		t.Errorf("expected the jobs of the deleted cron job to be deleted, got %d", len(jobs))
+	}
+	if pods, _ := c.ListPodsInfo(nil); len(pods) != 0 {
+		t.Errorf("expected the pods of the deleted cron job to be deleted, got %d", len(pods))
+	}
+}
+
+func TestApplyManifests(t *testing.T) {
+	s := clienttest.NewServer()
+	defer s.Close()
+	c := newTestClient(t, s)
+
+	manifests := `
+apiVersion: v1
+kind: Service
+metadata:
+  name: orcs
+  namespace: orcs
+spec:
+  ports:
+  - port: 80
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: orcs-config
+data:
+  verbose: "true"
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: orcs
+`
+	applied, err := c.ApplyManifests(strings.NewReader(manifests))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 3 || applied[0].Object.GetKind() != "Namespace" || applied[2].Object.GetKind() != "Service" {
+		t.Errorf("expected the namespace to be created first and the service last, got %+v", applied)
+	}
+
+	svc := &v1.Service{}
+	if !s.Get("services", "orcs", "orcs", svc) || svc.Spec.Ports[0].Port != 80 {
+		t.Errorf("expected service orcs to be created in namespace orcs, got %+v", svc)
+	}
+	configMap, err := c.Resource(client.GroupVersionResource{Version: "v1", Resource: "configmaps"}, true).Get("orcs-config")
+	if err != nil || client.NestedString(configMap, "data", "verbose") != "true" {
+		t.Errorf("expected config map to be created in the client namespace, got %v %v", configMap, err)
+	}
+
+	// Applying again updates only what changed
+	applied, err = c.ApplyManifests(strings.NewReader(strings.Replace(manifests, `verbose: "true"`, `verbose: "false"`, 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, expected := range []client.ApplyOperation{client.ApplyUnchanged, client.ApplyConfigured, client.ApplyUnchanged} {
+		if applied[i].Operation != expected {
+			t.Errorf("expected %s %s to be %s, got %s", applied[i].Object.GetKind(), applied[i].Object.GetName(), expected, applied[i].Operation)
+		}
+	}
+	if client.NestedString(applied[1].Object, "data", "verbose") != "false" {
+		t.Errorf("expected config map to be updated, got %v", applied[1].Object)
+	}
+}
+
+func TestApply(t *testing.T) {
+	s := clienttest.NewServer()
+	defer s.Close()
+	c := newTestClient(t, s)
+
+	svc := &v1.Service{
+		ObjectMeta: v1.ObjectMeta{Name: "orcs", Labels: map[string]string{"app": "orcs", "tier": "web"}},
+		Spec: v1.ServiceSpec{
+			Selector: map[string]string{"app": "orcs"},
+			Ports:    []v1.ServicePort{{Name: "service-http", Port: 80}},
+		},
+	}
+	result, err := c.Apply(svc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Operation != client.ApplyCreated || result.Object.GetAnnotations()[client.LastAppliedConfigAnnotation] == "" {
+		t.Errorf("expected service to be created with the last applied annotation, got %s %v", result.Operation, result.Object)
+	}
+	if result, err = c.Apply(svc); err != nil || result.Operation != client.ApplyUnchanged {
+		t.Errorf("expected applying the same service to change nothing, got %v %v", result, err)
+	}
+
+	// A label set by others is kept while the label removed from the desired service is deleted
+	_, err = c.Resource(client.GroupVersionResource{Version: "v1", Resource: "services"}, true).Patch(
+		"orcs", client.MergePatchType, []byte(`{"metadata":{"labels":{"team":"nazgul"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	delete(svc.Labels, "tier")
+	svc.Spec.Ports[0].Port = 8080
+	if result, err = c.Apply(svc); err != nil {
+		t.Fatal(err)
+	}
+	applied, err := c.GetServiceInfo("orcs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Operation != client.ApplyConfigured || applied.Spec.Ports[0].Port != 8080 ||
+		applied.Labels["tier"] != "" || applied.Labels["team"] != "nazgul" || applied.Labels["app"] != "orcs" {
+		t.Errorf("unexpected applied service %s %+v", result.Operation, applied)
+	}
+}
+
+func TestProxy(t *testing.T) {
+	s := clienttest.NewServer()
+	defer s.Close()
+	c := newTestClient(t, s)
+
+	svc := &v1.Service{
+		ObjectMeta: v1.ObjectMeta{Name: "orcs"},
+		Spec: v1.ServiceSpec{
+			Selector: map[string]string{"app": "orcs"},
+			Ports:    []v1.ServicePort{{Port: 80, TargetPort: types.NewIntOrStringFromInt(8080)}},
+		},
+	}
+	if _, err := c.CreateService(svc, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CreatePod(&v1.Pod{ObjectMeta: v1.ObjectMeta{Name: "orcs-1"}, Spec: orcsReplicationController("orcs").Spec.Template.Spec}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// No endpoints are serving the service yet
+	resp, err := c.ProxyService("GET", "orcs", "80", "/hub-web-api/site", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected status %d without endpoints, got %d", http.StatusServiceUnavailable, resp.StatusCode)
+	}
+
+	echo := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		io.WriteString(w, r.Method+" "+r.URL.RequestURI()+" "+string(body))
+	})
+	s.Proxy("services", "ocopea", "orcs", echo)
+	s.Proxy("pods", "ocopea", "orcs-1", echo)
+
+	httpClient := &http.Client{Transport: c.ServiceProxyTransport("orcs", "80")}
+	resp, err = httpClient.Post("http://orcs/site-api/commands/add?dryRun=true", "application/json", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "POST /site-api/commands/add?dryRun=true {}" {
+		t.Errorf("unexpected response through the service proxy transport %q", string(body))
+	}
+	if requests := s.Requests(); requests[len(requests)-1] != "POST /api/v1/namespaces/ocopea/services/orcs:80/proxy/site-api/commands/add" {
+		t.Errorf("expected the request to be sent through the api server, got %s", requests[len(requests)-1])
+	}
+
+	resp, err = c.ProxyPod("GET", "orcs-1", "", &v1.PodProxyOptions{Path: "hub-web-api/site"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ = ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "GET /hub-web-api/site " {
+		t.Errorf("unexpected response through the pod proxy %q", string(body))
+	}
+}
+
+func TestJournalUndo(t *testing.T) {
+	s := clienttest.NewServer()
+	defer s.Close()
+	c := newTestClient(t, s)
+
+	legacy := &v1.Service{
+		ObjectMeta: v1.ObjectMeta{Name: "legacy", Labels: map[string]string{"app": "legacy"}},
+		Spec:       v1.ServiceSpec{Ports: []v1.ServicePort{{Port: 80, TargetPort: types.NewIntOrStringFromInt(8080)}}},
+	}
+	if _, err := c.CreateService(legacy, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CreateReplicationController(orcsReplicationController("hub"), false); err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	journalPath := filepath.Join(dir, "journal")
+	c.Journal = client.NewFileJournal(journalPath)
+	if _, err := c.CreateService(&v1.Service{
+		ObjectMeta: v1.ObjectMeta{Name: "orcs"},
+		Spec:       v1.ServiceSpec{Ports: []v1.ServicePort{{Port: 80, TargetPort: types.NewIntOrStringFromInt(8080)}}},
+	}, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ScaleReplicationController("hub", 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DeleteService("legacy"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CreateEvent(&v1.Event{ObjectMeta: v1.ObjectMeta{Name: "orcs.1"}, Reason: "Deployed"}); err != nil {
+		t.Fatal(err)
+	}
+	c.Journal = nil
+
+	entries, err := client.NewFileJournal(journalPath).Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var recorded []string
+	for _, entry := range entries {
+		recorded = append(recorded, string(entry.Operation)+" "+entry.Path)
+	}
+	expected := []string{
+		"create /api/v1/namespaces/ocopea/services/orcs",
+		"patch /api/v1/namespaces/ocopea/replicationcontrollers/hub",
+		"delete /api/v1/namespaces/ocopea/services/legacy",
+	}
+	if !reflect.DeepEqual(recorded, expected) {
+		t.Fatalf("expected journal %v, got %v", expected, recorded)
+	}
+
+	if err = c.Undo(client.NewFileJournal(journalPath)); err != nil {
+		t.Fatal(err)
+	}
+	if exists, _ := c.CheckServiceExists("orcs"); exists {
+		t.Errorf("expected the created service to be deleted")
+	}
+	restored, err := c.GetServiceInfo("legacy")
+	if err != nil {
+		t.Fatalf("expected the deleted service to be created again, got %v", err)
+	}
+	if restored.Labels["app"] != "legacy" || restored.Spec.Ports[0].Port != 80 {
+		t.Errorf("expected the service to be restored to its previous state, got %+v", restored)
+	}
+	if rc, err := c.GetReplicationControllerInfo("hub"); err != nil || *rc.Spec.Replicas != 1 {
+		t.Errorf("expected the replication controller to be scaled back to 1 replica, got %v", err)
+	}
+}
+
+func TestCloneNamespace(t *testing.T) {
+	s := clienttest.NewServer()
+	defer s.Close()
+	c := newTestClient(t, s)
+	namespaces := c.Resource(client.GroupVersionResource{Version: "v1", Resource: "namespaces"}, false)
+	if _, err := namespaces.Patch("ocopea", client.MergePatchType, []byte(`{"metadata":{"labels":{"team":"nazgul"}}}`)); err != nil {
+		t.Fatal(err)
+	}
+	secrets := c.Resource(client.GroupVersionResource{Version: "v1", Resource: "secrets"}, true)
+	claims := c.Resource(client.GroupVersionResource{Version: "v1", Resource: "persistentvolumeclaims"}, true)
+	for _, obj := range []struct {
+		r   *client.DynamicResource
+		obj client.Unstructured
+	}{
+		{secrets, client.Unstructured{"metadata": map[string]interface{}{"name": "db-credentials"}, "data": map[string]interface{}{"password": "c2VjcmV0"}}},
+		{secrets, client.Unstructured{"metadata": map[string]interface{}{"name": "default-token"}, "type": "kubernetes.io/service-account-token"}},
+		{claims, client.Unstructured{
+			"metadata": map[string]interface{}{"name": "data", "annotations": map[string]interface{}{"pv.kubernetes.io/bind-completed": "yes"}},
+			"spec":     map[string]interface{}{"volumeName": "pv-1", "accessModes": []interface{}{"ReadWriteOnce"}},
+		}},
+	} {
+		if _, err := obj.r.Create(obj.obj); err != nil {
+			t.Fatal(err)
+		}
+	}
+	svc, err := c.CreateService(&v1.Service{
+		ObjectMeta: v1.ObjectMeta{Name: "orcs"},
+		Spec: v1.ServiceSpec{
+			Type:     v1.ServiceTypeNodePort,
+			Selector: map[string]string{"app": "orcs"},
+			Ports:    []v1.ServicePort{{Name: "service-http", Port: 80}},
+		},
+	}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc := orcsReplicationController("orcs")
+	rc.Spec.Template.Spec.Containers[0].Image = "ocopea/orcs-k8s-runner:1.0"
+	rc.Spec.Template.Spec.Containers[0].Env = []v1.EnvVar{
+		{Name: "ORCS_URL", Value: "http://orcs.ocopea.svc.cluster.local"},
+		{Name: "DEBUG", Value: "false"},
+	}
+	if _, err = c.CreateReplicationController(rc, false); err != nil {
+		t.Fatal(err)
+	}
+
+	options := &client.CloneOptions{
+		Images: map[string]string{"ocopea/orcs-k8s-runner": "ocopea/orcs-k8s-runner:debug"},
+		Env:    map[string]string{"DEBUG": "true"},
+		Labels: map[string]string{"clone-of": "ocopea"},
+	}
+	results, err := c.CloneNamespace("ocopea", "ocopea-repro", options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cloned []string
+	for _, result := range results {
+		cloned = append(cloned, result.Object.GetKind()+"/"+result.Object.GetName()+" "+string(result.Operation))
+	}
+	expected := []string{
+		"Namespace/ocopea-repro created", "Secret/db-credentials created", "PersistentVolumeClaim/data created",
+		"Service/orcs created", "ReplicationController/orcs created",
+	}
+	if !reflect.DeepEqual(cloned, expected) {
+		t.Errorf("expected cloned objects %v, got %v", expected, cloned)
+	}
+
+	repro, err := client.NewClient(s.URL, "ocopea-repro", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ns, err := namespaces.Get("ocopea-repro")
+	if err != nil || ns.GetLabels()["team"] != "nazgul" || ns.GetLabels()["clone-of"] != "ocopea" {
+		t.Errorf("expected the cloned namespace to be labeled like the source, got %v %v", ns, err)
+	}
+	clonedSvc, err := repro.GetServiceInfo("orcs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clonedSvc.Spec.ClusterIP == svc.Spec.ClusterIP || clonedSvc.Spec.Ports[0].NodePort == svc.Spec.Ports[0].NodePort {
+		t.Errorf("expected the cloned service to get its own cluster ip and node port, got %+v", clonedSvc.Spec)
+	}
+	claim, err := claims.InNamespace("ocopea-repro").Get("data")
+	if err != nil || client.NestedString(claim, "spec", "volumeName") != "" ||
+		claim.GetAnnotations()["pv.kubernetes.io/bind-completed"] != "" {
+		t.Errorf("expected the cloned claim to be unbound, got %v %v", claim, err)
+	}
+	clonedRc, err := repro.GetReplicationControllerInfo("orcs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	container := clonedRc.Spec.Template.Spec.Containers[0]
+	if container.Image != "ocopea/orcs-k8s-runner:debug" || container.Env[0].Value != "http://orcs.ocopea-repro.svc.cluster.local" ||
+		container.Env[1].Value != "true" || clonedRc.Labels["clone-of"] != "ocopea" {
+		t.Errorf("unexpected cloned replication controller %+v", clonedRc)
+	}
+
+	// Cloning again leaves the clone as is
+	if results, err = c.CloneNamespace("ocopea", "ocopea-repro", options); err != nil {
+		t.Fatal(err)
+	}
+	for _, result := range results {
+		if result.Operation != client.ApplyUnchanged {
+			t.Errorf("expected cloning again to change nothing, %s %s was %s",
+				result.Object.GetKind(), result.Object.GetName(), result.Operation)
+		}
+	}
+
+	if _, err = c.CloneNamespace("ocopea", "ocopea-repro", &client.CloneOptions{SkipKinds: []string{"Pod"}}); err == nil {
+		t.Errorf("expected skipping a kind that is never cloned to fail")
+	}
+}
+
+func TestVolumeSnapshots(t *testing.T) {
+	s := clienttest.NewServer()
+	defer s.Close()
+	c := newTestClient(t, s)
+
+	storageClass := "csi-hostpath"
+	_, err := c.CreatePersistentVolumeClaim(&v1.PersistentVolumeClaim{
+		ObjectMeta: v1.ObjectMeta{Name: "pg-data", Labels: map[string]string{"app": "pg"}},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes:      []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+			Resources:        v1.ResourceRequirements{Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse("1Gi")}},
+			StorageClassName: &storageClass,
+		},
+	}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.SnapshotPersistentVolumeClaim("pg-data", "pg-data-1", "csi-hostpath-snapclass"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.RestorePersistentVolumeClaim("pg-data-copy", "pg-data-1", nil); err == nil {
+		t.Errorf("expected restoring a snapshot that is not ready to fail")
+	}
+
+	// Playing the snapshot controller
+	snapshots := c.Resource(client.VolumeSnapshotResource, true)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		snapshots.Patch("pg-data-1", client.MergePatchType, []byte(`{"status":{"readyToUse":true,"restoreSize":"2Gi"}}`))
+	}()
+	snapshot, err := c.WaitForVolumeSnapshotToBeReady("pg-data-1", 50, 100*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.NestedString(snapshot, "spec", "volumeSnapshotClassName") != "csi-hostpath-snapclass" {
+		t.Errorf("unexpected snapshot %v", snapshot)
+	}
+	if listed, err := c.ListVolumeSnapshots("pg-data"); err != nil || len(listed) != 1 || listed[0].GetName() != "pg-data-1" {
+		t.Errorf("expected the snapshot of the claim to be listed, got %v %v", listed, err)
+	}
+	if listed, err := c.ListVolumeSnapshots("mongo-data"); err != nil || len(listed) != 0 {
+		t.Errorf("expected no snapshots of other claims, got %v %v", listed, err)
+	}
+
+	restored, err := c.RestorePersistentVolumeClaim("pg-data-copy", "pg-data-1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	size := restored.Spec.Resources.Requests[v1.ResourceStorage]
+	if restored.Spec.DataSource == nil || restored.Spec.DataSource.Kind != "VolumeSnapshot" ||
+		restored.Spec.DataSource.Name != "pg-data-1" || size.String() != "2Gi" ||
+		*restored.Spec.StorageClassName != storageClass || restored.Labels["app"] != "pg" {
+		t.Errorf("unexpected restored claim %+v", restored)
+	}
+
+	if _, err = c.SnapshotPersistentVolumeClaim("pg-data", "pg-data-2", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = snapshots.Patch("pg-data-2", client.MergePatchType,
+		[]byte(`{"status":{"readyToUse":false,"error":{"message":"snapshot quota exceeded"}}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.WaitForVolumeSnapshotToBeReady("pg-data-2", 50, 100*time.Millisecond); err == nil ||
+		!strings.Contains(err.Error(), "snapshot quota exceeded") {
+		t.Errorf("expected waiting for a failed snapshot to fail, got %v", err)
+	}
+
+	if err = c.DeleteVolumeSnapshot("pg-data-1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.GetVolumeSnapshot("pg-data-1"); !client.IsNotFound(err) {
+		t.Errorf("expected deleted snapshot to be gone, got %v", err)
+	}
+	if _, err = c.SnapshotPersistentVolumeClaim("missing", "missing-1", ""); err == nil {
+		t.Errorf("expected snapshotting a missing claim to fail")
+	}
+}
@@ -0,0 +1,404 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package clienttest
+
+import (
+	"fmt"
+	"ocopea/kubernetes/client/v1"
+	"strconv"
+	"strings"
+)
+
+// The fake server simulates the controllers and the kubelet just enough for the client wait loops to complete:
+// replication controllers and jobs create their pods, replication controllers replace their deleted pods,
+// services get addresses and pods move through scripted phases
+
+const fakeNodeName = "fake-node"
+const fakeHostIP = "10.0.2.15"
+
+var podsKey = resourceKey{"/api/v1", "pods"}
+
+// PodScript describes the phases pods go through, e.g. {Pending, Running} or {Pending, Running, Succeeded}.
+// A pod starts in the first phase and moves to the next phase every time it is read by name, staying in the last one
+type PodScript struct {
+	Phases []v1.PodPhase
+
+	// Reason reported by the containers while the pod is pending, ContainerCreating by default.
+	// Setting ErrImagePull simulates an image that cannot be pulled
+	WaitingReason string
+
+	// Exit code of the containers of a failed pod, 1 by default
+	ExitCode int
+
+	// Logs returned for the pod
+	Logs string
+}
+
+type podState struct {
+	script     PodScript
+	phaseIndex int
+	podIP      string
+	logs       string
+}
+
+func (state *podState) phase() v1.PodPhase {
+	if len(state.script.Phases) == 0 {
+		return v1.PodRunning
+	}
+	return state.script.Phases[state.phaseIndex]
+}
+
+// Scripts the phases of pods created from now on whose name starts with the prefix, pods with no script are
+// running as soon as they are created. The longest matching prefix wins
+func (s *Server) ScriptPods(namePrefix string, script PodScript) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.podScripts[namePrefix] = script
+}
+
+// Moves a pod to the given phase, the pod stays in this phase from now on
+func (s *Server) SetPodPhase(namespace string, podName string, phase v1.PodPhase) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	key := objectKey{resourceKey{"/api/v1", "pods"}, namespace, podName}
+	state := s.podStates[key]
+	if state == nil {
+		return fmt.Errorf("pod %s/%s not found", namespace, podName)
+	}
+	previousPhase := state.phase()
+	state.script.Phases = []v1.PodPhase{phase}
+	state.phaseIndex = 0
+	s.updatePodStatus(key, previousPhase)
+	return nil
+}
+
+// Sets the logs returned for a pod
+func (s *Server) SetPodLogs(namespace string, podName string, logs string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	state := s.podStates[objectKey{resourceKey{"/api/v1", "pods"}, namespace, podName}]
+	if state == nil {
+		return fmt.Errorf("pod %s/%s not found", namespace, podName)
+	}
+	state.logs = logs
+	return nil
+}
+
+func (s *Server) scriptFor(podName string) PodScript {
+	var script PodScript
+	longestPrefix := -1
+	for prefix, candidate := range s.podScripts {
+		if strings.HasPrefix(podName, prefix) && len(prefix) > longestPrefix {
+			script = candidate
+			longestPrefix = len(prefix)
+		}
+	}
+	return script
+}
+
+// Fills the fields the cluster sets on new core objects. Lock must be held
+func (s *Server) prepareCreate(key objectKey, obj map[string]interface{}) {
+	if key.apiPath != "/api/v1" {
+		return
+	}
+	switch key.resource {
+	case "namespaces":
+		obj["status"] = map[string]interface{}{"phase": string(v1.NamespaceActive)}
+	case "persistentvolumes":
+		if stringField(obj, "status", "phase") == "" {
+			obj["status"] = map[string]interface{}{"phase": string(v1.VolumeAvailable)}
+		}
+	case "services":
+		s.allocateServiceAddresses(obj)
+	case "replicationcontrollers":
+		obj["status"] = map[string]interface{}{"replicas": 0}
+	case "pods":
+		s.allocations++
+		name := stringField(obj, "metadata", "name")
+		script := s.scriptFor(name)
+		if len(script.Phases) == 0 && stringMap(obj, "metadata", "labels")[jobNameLabel] != "" {
+			script.Phases = defaultJobPodPhases
+		}
+		state := &podState{
+			script: script,
+			podIP:  fmt.Sprintf("172.17.%d.%d", s.allocations/250, s.allocations%250+2),
+		}
+		state.logs = state.script.Logs
+		s.podStates[key] = state
+		spec, _ := obj["spec"].(map[string]interface{})
+		if spec != nil && stringField(spec, "nodeName") == "" {
+			spec["nodeName"] = fakeNodeName
+		}
+		obj["status"] = podStatus(obj, state)
+	}
+}
+
+// Runs the controllers reacting to a written object. Lock must be held
+func (s *Server) afterWrite(key objectKey, obj map[string]interface{}, created bool) {
+	if key.resourceKey == jobsKey {
+		s.reconcileJob(key, obj)
+		return
+	}
+	if key.apiPath != "/api/v1" {
+		return
+	}
+	switch key.resource {
+	case "replicationcontrollers":
+		s.reconcileReplicationController(key, obj)
+	case "pods":
+		if created {
+			s.recordPodEvent(key, "Normal", "Scheduled", fmt.Sprintf("Successfully assigned %s to %s", key.name, fakeNodeName))
+			s.recordPhaseEvent(key, "")
+		}
+	}
+}
+
+// Moves the pod to its next scripted phase, returns the pod. Lock must be held
+func (s *Server) advancePod(key objectKey) map[string]interface{} {
+	state := s.podStates[key]
+	if state != nil && state.phaseIndex < len(state.script.Phases)-1 {
+		previousPhase := state.phase()
+		state.phaseIndex++
+		s.updatePodStatus(key, previousPhase)
+	}
+	return s.objects[key]
+}
+
+// Lock must be held
+func (s *Server) updatePodStatus(key objectKey, previousPhase v1.PodPhase) {
+	pod := s.objects[key]
+	pod["status"] = podStatus(pod, s.podStates[key])
+	s.store(key, pod, "MODIFIED")
+	s.recordPhaseEvent(key, previousPhase)
+}
+
+func podStatus(pod map[string]interface{}, state *podState) map[string]interface{} {
+	phase := state.phase()
+	var containerStatuses []interface{}
+	containers, _ := nestedObjects(pod, "spec", "containers")
+	for _, container := range containers {
+		containerState := map[string]interface{}{}
+		switch phase {
+		case v1.PodPending:
+			reason := state.script.WaitingReason
+			if reason == "" {
+				reason = "ContainerCreating"
+			}
+			containerState["waiting"] = map[string]interface{}{"reason": reason}
+		case v1.PodRunning:
+			containerState["running"] = map[string]interface{}{"startedAt": now()}
+		case v1.PodSucceeded:
+			containerState["terminated"] = map[string]interface{}{"exitCode": 0, "reason": "Completed", "finishedAt": now()}
+		case v1.PodFailed:
+			exitCode := state.script.ExitCode
+			if exitCode == 0 {
+				exitCode = 1
+			}
+			containerState["terminated"] = map[string]interface{}{"exitCode": exitCode, "reason": "Error", "finishedAt": now()}
+		}
+		containerStatuses = append(containerStatuses, map[string]interface{}{
+			"name":         container["name"],
+			"image":        container["image"],
+			"imageID":      "docker://" + stringField(container, "image"),
+			"ready":        phase == v1.PodRunning,
+			"restartCount": 0,
+			"state":        containerState,
+		})
+	}
+
+	ready := v1.ConditionFalse
+	if phase == v1.PodRunning {
+		ready = v1.ConditionTrue
+	}
+	return map[string]interface{}{
+		"phase":             string(phase),
+		"hostIP":            fakeHostIP,
+		"podIP":             state.podIP,
+		"startTime":         now(),
+		"conditions":        []interface{}{map[string]interface{}{"type": string(v1.PodReady), "status": string(ready)}},
+		"containerStatuses": containerStatuses,
+	}
+}
+
+// Records the event the kubelet reports when a pod enters its current phase. Lock must be held
+func (s *Server) recordPhaseEvent(key objectKey, previousPhase v1.PodPhase) {
+	state := s.podStates[key]
+	phase := state.phase()
+	if phase == previousPhase {
+		return
+	}
+	switch phase {
+	case v1.PodPending:
+		if state.script.WaitingReason == "ErrImagePull" {
+			s.recordPodEvent(key, "Warning", "Failed", "Failed to pull image")
+		} else {
+			s.recordPodEvent(key, "Normal", "Pulling", "pulling image")
+		}
+	case v1.PodRunning:
+		s.recordPodEvent(key, "Normal", "Started", "Started container")
+	case v1.PodSucceeded:
+		s.recordPodEvent(key, "Normal", "Completed", "Container completed")
+	case v1.PodFailed:
+		s.recordPodEvent(key, "Warning", "Failed", fmt.Sprintf("Container failed with exit code %d", state.script.ExitCode))
+	}
+}
+
+// Lock must be held
+func (s *Server) recordPodEvent(key objectKey, eventType string, reason string, message string) {
+	pod := s.objects[key]
+	timestamp := now()
+	event := map[string]interface{}{
+		"metadata": map[string]interface{}{"generateName": key.name + "."},
+		"involvedObject": map[string]interface{}{
+			"kind":       "Pod",
+			"namespace":  key.namespace,
+			"name":       key.name,
+			"uid":        stringField(pod, "metadata", "uid"),
+			"apiVersion": "v1",
+		},
+		"reason":         reason,
+		"message":        message,
+		"type":           eventType,
+		"count":          1,
+		"firstTimestamp": timestamp,
+		"lastTimestamp":  timestamp,
+		"source":         map[string]interface{}{"component": "kubelet", "host": fakeNodeName},
+	}
+	s.create(objectKey{resourceKey: resourceKey{"/api/v1", "events"}, namespace: key.namespace}, event)
+}
+
+// Creates or deletes pods until the number of pods matching the selector is the number of replicas.
+// Like the api server of the time, deleting a replication controller does not delete its pods. Lock must be held
+func (s *Server) reconcileReplicationController(key objectKey, rc map[string]interface{}) {
+	replicas := 1
+	if value := stringField(rc, "spec", "replicas"); value != "" {
+		replicas, _ = strconv.Atoi(value)
+	}
+	spec, _ := rc["spec"].(map[string]interface{})
+	template, _ := spec["template"].(map[string]interface{})
+
+	pods := s.list(podsKey, key.namespace, replicationControllerSelector(rc), nil)
+	for i := len(pods); i < replicas && template != nil; i++ {
+		pod := deepCopy(template).(map[string]interface{})
+		metadata := metadataOf(pod)
+		metadata["generateName"] = key.name + "-"
+		delete(metadata, "name")
+		s.create(objectKey{resourceKey: podsKey, namespace: key.namespace}, pod)
+	}
+	for i := len(pods) - 1; i >= replicas; i-- {
+		s.delete(objectKey{podsKey, key.namespace, stringField(pods[i], "metadata", "name")})
+	}
+
+	observed := len(pods)
+	if observed < replicas && template != nil {
+		observed = replicas
+	} else if observed > replicas {
+		observed = replicas
+	}
+	rc["status"] = map[string]interface{}{
+		"replicas":           observed,
+		"observedGeneration": 1,
+	}
+	s.store(key, rc, "MODIFIED")
+}
+
+// Returns the selector of the pods the replication controller manages, defaulting to the labels of its template
+func replicationControllerSelector(rc map[string]interface{}) selector {
+	podSelector := stringMap(rc, "spec", "selector")
+	if len(podSelector) == 0 {
+		podSelector = stringMap(rc, "spec", "template", "metadata", "labels")
+	}
+	var sel selector
+	for k, v := range podSelector {
+		sel = append(sel, requirement{key: k, operator: "=", values: []string{v}})
+	}
+	return sel
+}
+
+// Replaces a deleted pod managed by a replication controller, like the replication manager does. Lock must be held
+func (s *Server) replaceDeletedPod(pod map[string]interface{}) {
+	rcsKey := resourceKey{"/api/v1", "replicationcontrollers"}
+	namespace := stringField(pod, "metadata", "namespace")
+	for _, rc := range s.list(rcsKey, namespace, nil, nil) {
+		if sel := replicationControllerSelector(rc); len(sel) > 0 && sel.matchesLabels(pod) {
+			s.reconcileReplicationController(objectKey{rcsKey, namespace, stringField(rc, "metadata", "name")}, rc)
+		}
+	}
+}
+
+// Lock must be held
+func (s *Server) allocateServiceAddresses(svc map[string]interface{}) {
+	spec, _ := svc["spec"].(map[string]interface{})
+	if spec == nil {
+		spec = make(map[string]interface{})
+		svc["spec"] = spec
+	}
+	serviceType := stringField(spec, "type")
+	if serviceType == "" {
+		serviceType = string(v1.ServiceTypeClusterIP)
+		spec["type"] = serviceType
+	}
+	s.allocations++
+	if stringField(spec, "clusterIP") == "" {
+		spec["clusterIP"] = fmt.Sprintf("10.0.%d.%d", s.allocations/250, s.allocations%250+1)
+	}
+
+	if serviceType == string(v1.ServiceTypeNodePort) || serviceType == string(v1.ServiceTypeLoadBalancer) {
+		ports, _ := nestedObjects(svc, "spec", "ports")
+		for _, port := range ports {
+			if nodePort := stringField(port, "nodePort"); nodePort == "" || nodePort == "0" {
+				s.allocations++
+				port["nodePort"] = 30000 + s.allocations%2768
+			}
+		}
+	}
+
+	status := map[string]interface{}{"loadBalancer": map[string]interface{}{}}
+	if serviceType == string(v1.ServiceTypeLoadBalancer) {
+		status["loadBalancer"] = map[string]interface{}{
+			"ingress": []interface{}{map[string]interface{}{"ip": fmt.Sprintf("192.0.2.%d", s.allocations%250+1)}},
+		}
+	}
+	svc["status"] = status
+}
+
+func stringMap(obj map[string]interface{}, fields ...string) map[string]string {
+	var value interface{} = obj
+	for _, field := range fields {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = m[field]
+	}
+	m, _ := value.(map[string]interface{})
+	result := make(map[string]string, len(m))
+	for k, v := range m {
+		if str, ok := v.(string); ok {
+			result[k] = str
+		}
+	}
+	return result
+}
+
+// Returns the list of objects found in the nested fields path, e.g. the containers of a pod
+func nestedObjects(obj map[string]interface{}, fields ...string) ([]map[string]interface{}, bool) {
+	var value interface{} = obj
+	for _, field := range fields {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		value = m[field]
+	}
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, false
+	}
+	result := make([]map[string]interface{}, 0, len(list))
+	for _, item := range list {
+		if m, ok := item.(map[string]interface{}); ok {
+			result = append(result, m)
+		}
+	}
+	return result, true
+}
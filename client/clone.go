@@ -0,0 +1,273 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+)
+
+// Kinds cloned by CloneNamespace in cloning order, so workloads are created after the objects they use
+var cloneKinds = []string{"Secret", "ConfigMap", "PersistentVolumeClaim", "Service", "ReplicationController"}
+
+// Annotations of persistent volume claims that describe the binding of the claim in the source namespace
+var boundClaimAnnotationPrefixes = []string{
+	"pv.kubernetes.io/",
+	"volume.beta.kubernetes.io/storage-provisioner",
+	"volume.kubernetes.io/storage-provisioner",
+	"volume.kubernetes.io/selected-node",
+}
+
+type CloneOptions struct {
+	// Kinds that are not cloned, e.g. Secret
+	SkipKinds []string
+
+	// Only objects with these labels are cloned, all objects by default
+	LabelFilters map[string]string
+
+	// Images replacing those of the cloned containers, by source image (e.g. ocopea/orcs:1.0) or by image name
+	// matching any tag (e.g. ocopea/orcs)
+	Images map[string]string
+
+	// Values replacing those of the env vars of the cloned containers with the same name. Containers lacking
+	// the env vars are left as is
+	Env map[string]string
+
+	// Labels added to the destination namespace and to every cloned object
+	Labels map[string]string
+}
+
+// Returns the kinds the options clone, failing kinds to skip that are not cloned anyway
+func (options *CloneOptions) clonedKinds() ([]string, error) {
+	skip := make(map[string]bool, len(options.SkipKinds))
+	for _, kind := range options.SkipKinds {
+		skip[kind] = true
+	}
+	kinds := make([]string, 0, len(cloneKinds))
+	for _, kind := range cloneKinds {
+		if skip[kind] {
+			delete(skip, kind)
+		} else {
+			kinds = append(kinds, kind)
+		}
+	}
+	if len(skip) > 0 {
+		return nil, fmt.Errorf("kinds to skip %v are never cloned, cloned kinds are %s",
+			options.SkipKinds, strings.Join(cloneKinds, ", "))
+	}
+	return kinds, nil
+}
+
+// Returns the name of the image without its tag or digest, e.g. ocopea/orcs for ocopea/orcs:1.0
+func imageName(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	// A colon before the last slash belongs to the registry port, e.g. registry:5000/orcs
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image
+}
+
+// Rewrites the references to the source namespace found in the value: namespace fields and the dns names of
+// services, e.g. orcs.staging.svc.cluster.local
+func rewriteNamespaceReferences(value interface{}, src string, dst string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if key == "namespace" && item == src {
+				v[key] = dst
+			} else {
+				v[key] = rewriteNamespaceReferences(item, src, dst)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = rewriteNamespaceReferences(item, src, dst)
+		}
+	case string:
+		return strings.Replace(v, "."+src+".svc", "."+dst+".svc", -1)
+	}
+	return value
+}
+
+// Applies the image and env var replacements of the options to the containers of the pod spec
+func remapContainers(podSpec map[string]interface{}, options *CloneOptions) {
+	for _, field := range []string{"initContainers", "containers"} {
+		containers, _ := podSpec[field].([]interface{})
+		for _, item := range containers {
+			container, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			image, _ := container["image"].(string)
+			if mapped, found := options.Images[image]; found {
+				container["image"] = mapped
+			} else if mapped, found := options.Images[imageName(image)]; found {
+				container["image"] = mapped
+			}
+
+			env, _ := container["env"].([]interface{})
+			for _, envItem := range env {
+				envVar, ok := envItem.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				name, _ := envVar["name"].(string)
+				if value, found := options.Env[name]; found {
+					envVar["value"] = value
+					delete(envVar, "valueFrom")
+				}
+			}
+		}
+	}
+}
+
+// Returns the object to create in the destination namespace for an object of the source namespace, or nil for
+// objects that should not be cloned. The fields the server sets, e.g. the cluster ip of services and the volume
+// claims are bound to, are left for the server to set again
+func cloneObject(obj Unstructured, kind string, src string, dst string, options *CloneOptions) Unstructured {
+	// Created by the controllers of every namespace, for its service accounts
+	if kind == "Secret" && NestedString(obj, "type") == "kubernetes.io/service-account-token" ||
+		kind == "ConfigMap" && obj.GetName() == "kube-root-ca.crt" {
+		return nil
+	}
+
+	clone := Unstructured(deepCopyJSON(map[string]interface{}(restorableObject(obj))).(map[string]interface{}))
+	clone["apiVersion"] = "v1"
+	clone["kind"] = kind
+	metadata, _ := clone["metadata"].(map[string]interface{})
+	delete(metadata, "ownerReferences")
+	delete(metadata, "finalizers")
+	rewriteNamespaceReferences(map[string]interface{}(clone), src, dst)
+	clone.SetNamespace(dst)
+	for key, value := range options.Labels {
+		SetNestedField(clone, value, "metadata", "labels", key)
+	}
+
+	switch kind {
+	case "Service":
+		spec, _ := clone["spec"].(map[string]interface{})
+		if spec["clusterIP"] != "None" {
+			delete(spec, "clusterIP")
+			delete(spec, "clusterIPs")
+		}
+		delete(spec, "healthCheckNodePort")
+		ports, _ := spec["ports"].([]interface{})
+		for _, port := range ports {
+			if portMap, ok := port.(map[string]interface{}); ok {
+				delete(portMap, "nodePort")
+			}
+		}
+	case "PersistentVolumeClaim":
+		spec, _ := clone["spec"].(map[string]interface{})
+		delete(spec, "volumeName")
+		annotations, _ := metadata["annotations"].(map[string]interface{})
+		for key := range annotations {
+			for _, prefix := range boundClaimAnnotationPrefixes {
+				if strings.HasPrefix(key, prefix) {
+					delete(annotations, key)
+				}
+			}
+		}
+	case "ReplicationController":
+		if podSpec, ok := NestedField(clone, "spec", "template", "spec"); ok {
+			if podSpecMap, ok := podSpec.(map[string]interface{}); ok {
+				remapContainers(podSpecMap, options)
+			}
+		}
+	}
+	return clone
+}
+
+// Returns a copy of a decoded json value
+func deepCopyJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			copied[key] = deepCopyJSON(item)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = deepCopyJSON(item)
+		}
+		return copied
+	}
+	return value
+}
+
+// Returns the namespace object for the destination namespace, labeled with the labels of the source namespace
+// and the labels of the options
+func cloneNamespaceObject(srcNamespace Unstructured, dst string, options *CloneOptions) Unstructured {
+	labels := make(map[string]interface{})
+	for key, value := range srcNamespace.GetLabels() {
+		labels[key] = value
+	}
+	for key, value := range options.Labels {
+		labels[key] = value
+	}
+	return Unstructured{
+		"apiVersion": "v1",
+		"kind":       "Namespace",
+		"metadata":   map[string]interface{}{"name": dst, "labels": labels},
+	}
+}
+
+// Clones the secrets, config maps, persistent volume claims, services and replication controllers of the source
+// namespace into the destination namespace, e.g. for reproducing a bug in a copy of staging. The destination
+// namespace is created when missing and labeled like the source namespace. References to the source namespace are
+// rewritten and the images and env vars of the containers are replaced according to the options. Cloned objects
+// are applied, so cloning again updates the objects cloned before.
+// Claims are cloned as definitions, each gets a new volume, and the data of volumes is not copied.
+// Returns the results of the objects applied so far in cloning order, starting with the namespace
+func (c *Client) CloneNamespace(srcNamespace string, dstNamespace string, options *CloneOptions) ([]*ApplyResult, error) {
+	if options == nil {
+		options = &CloneOptions{}
+	}
+	if srcNamespace == dstNamespace {
+		return nil, fmt.Errorf("Failed cloning namespace %s onto itself", srcNamespace)
+	}
+	kinds, err := options.clonedKinds()
+	if err != nil {
+		return nil, fmt.Errorf("Failed cloning namespace %s - %s", srcNamespace, err.Error())
+	}
+
+	src, err := c.Resource(coreResource("namespaces"), false).Get(srcNamespace)
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting namespace %s - %s", srcNamespace, err.Error())
+	}
+	results := make([]*ApplyResult, 0)
+	result, err := c.Apply(cloneNamespaceObject(src, dstNamespace, options))
+	if err != nil {
+		return results, err
+	}
+	results = append(results, result)
+
+	for _, kind := range kinds {
+		resource := pluralResourceName(kind)
+		objects, err := c.Resource(coreResource(resource), true).InNamespace(srcNamespace).List(options.LabelFilters)
+		if err != nil {
+			return results, fmt.Errorf("Failed listing %s of namespace %s - %s", resource, srcNamespace, err.Error())
+		}
+		sort.Slice(objects, func(i, j int) bool { return objects[i].GetName() < objects[j].GetName() })
+		for _, obj := range objects {
+			clone := cloneObject(obj, kind, srcNamespace, dstNamespace, options)
+			if clone == nil {
+				log.Printf("skipped %s %s of namespace %s\n", kind, obj.GetName(), srcNamespace)
+				continue
+			}
+			result, err := c.Apply(clone)
+			if err != nil {
+				return results, fmt.Errorf("Failed cloning %s %s to namespace %s - %s", kind, obj.GetName(), dstNamespace, err.Error())
+			}
+			log.Printf("%s %s %s in namespace %s\n", kind, clone.GetName(), result.Operation, dstNamespace)
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,278 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"ocopea/kubernetes/client/v1"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// CopyProgress is reported while files are copied to or from a pod
+type CopyProgress struct {
+	// Path of the file being copied, relative to the parent of the copied path, e.g. data/db/dump.sql
+	Path string
+
+	// Bytes of the file copied so far out of its size
+	FileBytes int64
+	FileSize  int64
+
+	// Bytes of all the files copied so far
+	TotalBytes int64
+}
+
+type CopyOptions struct {
+	// Container of the pod, may be empty for pods with a single container
+	Container string
+
+	// Called every time a chunk of a file is copied, and once for every directory or empty file
+	Progress func(progress CopyProgress)
+}
+
+// Counts the bytes of the copied file, reporting the progress
+type copyProgressCounter struct {
+	progress CopyProgress
+	report   func(progress CopyProgress)
+}
+
+func (p *copyProgressCounter) start(name string, size int64) {
+	p.progress.Path = name
+	p.progress.FileBytes = 0
+	p.progress.FileSize = size
+	if p.report != nil && size == 0 {
+		p.report(p.progress)
+	}
+}
+
+func (p *copyProgressCounter) Write(b []byte) (int, error) {
+	p.progress.FileBytes += int64(len(b))
+	p.progress.TotalBytes += int64(len(b))
+	if p.report != nil {
+		p.report(p.progress)
+	}
+	return len(b), nil
+}
+
+// Writes a tar archive of the local file or directory, naming the entries after archiveName, e.g. a local
+// directory /tmp/x archived as data has the entries data/, data/a.txt, data/sub/ etc.
+// Modes and modification times are kept, ownership is left for the extracting user
+func writeCopyArchive(w io.Writer, localPath string, archiveName string, progress *copyProgressCounter) error {
+	tw := tar.NewWriter(w)
+	err := filepath.Walk(localPath, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(localPath, filePath)
+		if err != nil {
+			return err
+		}
+		name := path.Join(archiveName, filepath.ToSlash(rel))
+
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(filePath); err != nil {
+				return err
+			}
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return fmt.Errorf("Failed archiving %s - %s", filePath, err.Error())
+		}
+		header.Name = name
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		header.Uid, header.Gid, header.Uname, header.Gname = 0, 0, "", ""
+		if err = tw.WriteHeader(header); err != nil {
+			return err
+		}
+		progress.start(name, header.Size)
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(filePath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(io.MultiWriter(tw, progress), f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// Returns the local path of an archive entry named after archiveName, failing entries escaping the destination
+func copyEntryPath(destination string, archiveName string, entryName string) (string, error) {
+	entryName = path.Clean(strings.TrimPrefix(entryName, "/"))
+	if entryName == archiveName {
+		return destination, nil
+	}
+	if !strings.HasPrefix(entryName, archiveName+"/") {
+		return "", fmt.Errorf("unexpected archive entry %s", entryName)
+	}
+	return filepath.Join(destination, filepath.FromSlash(strings.TrimPrefix(entryName, archiveName+"/"))), nil
+}
+
+// Extracts a tar archive written by tar -c of archiveName to the local destination, keeping modes and
+// modification times. Symbolic links pointing outside of the destination are skipped
+func extractCopyArchive(r io.Reader, destination string, archiveName string, progress *copyProgressCounter) error {
+	tr := tar.NewReader(r)
+	directories := make([]*tar.Header, 0)
+	destinations := make(map[*tar.Header]string)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("Failed reading archive - %s", err.Error())
+		}
+		target, err := copyEntryPath(destination, archiveName, header.Name)
+		if err != nil {
+			return err
+		}
+		mode := os.FileMode(header.Mode).Perm()
+		progress.start(path.Clean(header.Name), header.Size)
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			// Read only directories are restricted once the files in them are extracted
+			if err = os.MkdirAll(target, 0700); err != nil {
+				return err
+			}
+			directories = append(directories, header)
+			destinations[header] = target
+		case tar.TypeReg:
+			if err = os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(io.MultiWriter(f, progress), tr)
+			f.Close()
+			if err != nil {
+				return fmt.Errorf("Failed extracting %s - %s", header.Name, err.Error())
+			}
+			if err = os.Chmod(target, mode); err != nil {
+				return err
+			}
+			os.Chtimes(target, header.ModTime, header.ModTime)
+		case tar.TypeSymlink:
+			linked := header.Linkname
+			if !filepath.IsAbs(linked) {
+				linked = filepath.Join(filepath.Dir(target), linked)
+			}
+			if rel, err := filepath.Rel(destination, linked); err != nil || strings.HasPrefix(rel, "..") || filepath.IsAbs(header.Linkname) {
+				log.Printf("skipping symbolic link %s pointing outside of %s\n", header.Name, destination)
+				continue
+			}
+			os.Remove(target)
+			if err = os.Symlink(header.Linkname, target); err != nil {
+				return err
+			}
+		default:
+			log.Printf("skipping %s of unsupported type %c\n", header.Name, header.Typeflag)
+		}
+	}
+
+	// Deepest directories first, so restricting a parent does not prevent updating its children
+	for i := len(directories) - 1; i >= 0; i-- {
+		header := directories[i]
+		target := destinations[header]
+		if err := os.Chmod(target, os.FileMode(header.Mode).Perm()); err != nil {
+			return err
+		}
+		os.Chtimes(target, header.ModTime, header.ModTime)
+	}
+	return nil
+}
+
+// Copies the local file or directory to the remote path in the pod, creating the parent directories of the
+// remote path. Modes and modification times are kept. The container must have sh and tar
+func (c *Client) CopyToPod(podName string, localPath string, remotePath string, options *CopyOptions) error {
+	if options == nil {
+		options = &CopyOptions{}
+	}
+	if _, err := os.Lstat(localPath); err != nil {
+		return fmt.Errorf("Failed copying %s to pod %s - %s", localPath, podName, err.Error())
+	}
+	remotePath = path.Clean(remotePath)
+	remoteDir, archiveName := path.Split(remotePath)
+	if remoteDir == "" {
+		remoteDir = "."
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(writeCopyArchive(writer, localPath, archiveName, &copyProgressCounter{report: options.Progress}))
+	}()
+	// Unblocks the archive writer in case the command exits before reading it all
+	defer reader.Close()
+
+	stderr := &bytes.Buffer{}
+	err := c.Exec(podName, &v1.PodExecOptions{
+		Container: options.Container,
+		Command:   []string{"sh", "-c", `mkdir -p "$1" && tar -xf - -C "$1"`, "sh", remoteDir},
+	}, reader, ioutil.Discard, stderr)
+	if err != nil {
+		return fmt.Errorf("Failed copying %s to %s in pod %s - %s %s", localPath, remotePath, podName, err.Error(), stderr.String())
+	}
+	return nil
+}
+
+// Copies the remote file or directory of the pod to the local path, creating the local parent directories.
+// Modes and modification times are kept. The container must have tar
+func (c *Client) CopyFromPod(podName string, remotePath string, localPath string, options *CopyOptions) error {
+	if options == nil {
+		options = &CopyOptions{}
+	}
+	remotePath = path.Clean(remotePath)
+	remoteDir, archiveName := path.Split(remotePath)
+	if remoteDir == "" {
+		remoteDir = "."
+	}
+	if err := os.MkdirAll(filepath.Dir(localPath), 0755); err != nil {
+		return fmt.Errorf("Failed copying %s from pod %s - %s", remotePath, podName, err.Error())
+	}
+
+	reader, writer := io.Pipe()
+	extracted := make(chan error, 1)
+	go func() {
+		err := extractCopyArchive(reader, localPath, archiveName, &copyProgressCounter{report: options.Progress})
+		if err != nil {
+			// Failing the writes of the command output
+			reader.CloseWithError(err)
+		} else {
+			// Draining the padding tar writes after the end of the archive
+			io.Copy(ioutil.Discard, reader)
+		}
+		extracted <- err
+	}()
+
+	stderr := &bytes.Buffer{}
+	err := c.Exec(podName, &v1.PodExecOptions{
+		Container: options.Container,
+		Command:   []string{"tar", "-cf", "-", "-C", remoteDir, archiveName},
+	}, nil, writer, stderr)
+	writer.CloseWithError(err)
+	extractErr := <-extracted
+	if err == nil {
+		err = extractErr
+	}
+	if err != nil {
+		return fmt.Errorf("Failed copying %s from pod %s to %s - %s %s", remotePath, podName, localPath, err.Error(), stderr.String())
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Serves the exec subresource of pods whose file system is the root directory, running the commands of
+// CopyToPod and CopyFromPod. Speaks v4.channel.k8s.io, which can't signal the end of stdin
+func newExecTestServer(t *testing.T, root string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer psb-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if !strings.HasSuffix(r.URL.Path, "/exec") || r.Header.Get("Upgrade") != "websocket" {
+			w.Write([]byte(`{"items":[]}`))
+			return
+		}
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n"+
+			"Sec-WebSocket-Accept: %s\r\nSec-WebSocket-Protocol: %s\r\n\r\n",
+			wsAcceptKey(r.Header.Get("Sec-WebSocket-Key")), execProtocolV4)
+		rw.Flush()
+		ws := &wsConn{conn: conn, reader: bufio.NewReader(rw)}
+		defer ws.Close()
+
+		command := r.URL.Query()["command"]
+		var exitErr error
+		switch {
+		case command[0] == "sh":
+			stdin, stdinWriter := io.Pipe()
+			go func() {
+				for {
+					_, message, err := ws.readMessage()
+					if err != nil {
+						stdinWriter.CloseWithError(err)
+						return
+					}
+					if len(message) > 0 && message[0] == execStdinChannel {
+						stdinWriter.Write(message[1:])
+					}
+				}
+			}()
+			dir := filepath.Join(root, command[len(command)-1])
+			os.MkdirAll(dir, 0755)
+			// The entries are named after the first one, which is the copied file or directory
+			archive := bufio.NewReader(stdin)
+			header, err := archive.Peek(512)
+			if err == nil {
+				archiveName := strings.Split(string(header[:100]), "/")[0]
+				archiveName = strings.TrimRight(archiveName, "\x00")
+				err = extractCopyArchive(archive, filepath.Join(dir, archiveName), archiveName, &copyProgressCounter{})
+			}
+			exitErr = err
+		case command[0] == "tar":
+			dir, name := command[len(command)-2], command[len(command)-1]
+			if _, err = os.Stat(filepath.Join(root, dir, name)); err != nil {
+				ws.writeFrame(wsOpBinary, append([]byte{execStderrChannel}, "tar: "+name+": No such file or directory"...))
+				ws.writeFrame(wsOpBinary, append([]byte{execErrorChannel},
+					`{"status":"Failure","message":"command terminated with non-zero exit code","reason":"NonZeroExitCode",`+
+						`"details":{"causes":[{"reason":"ExitCode","message":"2"}]}}`...))
+				return
+			}
+			archive := &bytes.Buffer{}
+			exitErr = writeCopyArchive(archive, filepath.Join(root, dir, name), name, &copyProgressCounter{})
+			for archive.Len() > 0 {
+				ws.writeFrame(wsOpBinary, append([]byte{execStdoutChannel}, archive.Next(1000)...))
+			}
+		}
+		if exitErr != nil {
+			t.Error(exitErr)
+			ws.writeFrame(wsOpBinary, append([]byte{execErrorChannel}, `{"status":"Failure","message":"failed"}`...))
+			return
+		}
+		ws.writeFrame(wsOpBinary, append([]byte{execErrorChannel}, `{"status":"Success"}`...))
+	}))
+}
+
+func writeTestFile(t *testing.T, name string, contents string, mode os.FileMode) {
+	if err := ioutil.WriteFile(name, []byte(contents), mode); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(name, mode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCopyToAndFromPod(t *testing.T) {
+	local, err := ioutil.TempDir("", "copy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(local)
+	podRoot := filepath.Join(local, "pod")
+
+	app := filepath.Join(local, "app")
+	os.MkdirAll(filepath.Join(app, "bin"), 0755)
+	os.MkdirAll(filepath.Join(app, "empty"), 0700)
+	writeTestFile(t, filepath.Join(app, "config.yml"), "port: 8080\n", 0640)
+	writeTestFile(t, filepath.Join(app, "bin", "start.sh"), "#!/bin/sh\nexec app\n", 0755)
+
+	s := newExecTestServer(t, podRoot)
+	defer s.Close()
+	c, err := NewClientWithCredentials(s.URL, "ocopea", &StaticTokenProvider{Token: "psb-token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var progress []CopyProgress
+	err = c.CopyToPod("orcs-1", app, "/data/app", &CopyOptions{Progress: func(p CopyProgress) {
+		progress = append(progress, p)
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := progress[len(progress)-1]
+	if last.TotalBytes != int64(len("port: 8080\n")+len("#!/bin/sh\nexec app\n")) {
+		t.Errorf("unexpected progress %v", progress)
+	}
+
+	copied := filepath.Join(local, "copied", "app")
+	if err = c.CopyFromPod("orcs-1", "/data/app", copied, nil); err != nil {
+		t.Fatal(err)
+	}
+	expectedModes := map[string]os.FileMode{
+		"config.yml":   0640,
+		"bin/start.sh": 0755,
+		"empty":        os.ModeDir | 0700,
+	}
+	for _, dir := range []string{filepath.Join(podRoot, "data", "app"), copied} {
+		for name, mode := range expectedModes {
+			info, err := os.Stat(filepath.Join(dir, filepath.FromSlash(name)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Mode() != mode {
+				t.Errorf("expected %s of %s to have mode %v, got %v", name, dir, mode, info.Mode())
+			}
+		}
+	}
+	if b, _ := ioutil.ReadFile(filepath.Join(copied, "bin", "start.sh")); string(b) != "#!/bin/sh\nexec app\n" {
+		t.Errorf("unexpected contents of copied file %q", string(b))
+	}
+
+	// Single files are copied as well
+	if err = c.CopyFromPod("orcs-1", "/data/app/config.yml", filepath.Join(local, "config.yml"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := ioutil.ReadFile(filepath.Join(local, "config.yml")); string(b) != "port: 8080\n" {
+		t.Errorf("unexpected contents of copied file %q", string(b))
+	}
+
+	err = c.CopyFromPod("orcs-1", "/data/missing", filepath.Join(local, "missing"), nil)
+	if err == nil || !strings.Contains(err.Error(), "exit code 2") || !strings.Contains(err.Error(), "No such file") {
+		t.Errorf("expected copying a missing path to fail with the exit code of tar, got %v", err)
+	}
+}
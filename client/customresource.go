@@ -0,0 +1,120 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+var CustomResourceDefinitionResource = GroupVersionResource{
+	Group:    "apiextensions.k8s.io",
+	Version:  "v1",
+	Resource: "customresourcedefinitions",
+}
+
+// Builds a minimal custom resource definition serving a single version of the kind.
+// The schema preserves unknown fields so objects of any structure are accepted
+func NewCustomResourceDefinition(group string, version string, kind string, plural string, namespaced bool) Unstructured {
+	scope := "Cluster"
+	if namespaced {
+		scope = "Namespaced"
+	}
+	return Unstructured{
+		"apiVersion": CustomResourceDefinitionResource.Group + "/" + CustomResourceDefinitionResource.Version,
+		"kind":       "CustomResourceDefinition",
+		"metadata": map[string]interface{}{
+			"name": plural + "." + group,
+		},
+		"spec": map[string]interface{}{
+			"group": group,
+			"scope": scope,
+			"names": map[string]interface{}{
+				"kind":     kind,
+				"plural":   plural,
+				"singular": strings.ToLower(kind),
+			},
+			"versions": []interface{}{
+				map[string]interface{}{
+					"name":    version,
+					"served":  true,
+					"storage": true,
+					"schema": map[string]interface{}{
+						"openAPIV3Schema": map[string]interface{}{
+							"type":                                 "object",
+							"x-kubernetes-preserve-unknown-fields": true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Creates the custom resource definition, in force mode an already existing definition is returned as is
+func (c *Client) CreateCustomResourceDefinition(crd Unstructured, force bool) (Unstructured, error) {
+	crdResource := c.Resource(CustomResourceDefinitionResource, false)
+	created, err := crdResource.Create(crd)
+	if err != nil {
+		if IsConflict(err) && force {
+			log.Printf("conflict creating custom resource definition %s, force mode, getting info only", crd.GetName())
+			return crdResource.Get(crd.GetName())
+		}
+		return nil, fmt.Errorf("Failed creating custom resource definition %s - %s", crd.GetName(), err.Error())
+	}
+	return created, nil
+}
+
+func (c *Client) GetCustomResourceDefinition(name string) (Unstructured, error) {
+	return c.Resource(CustomResourceDefinitionResource, false).Get(name)
+}
+
+func (c *Client) DeleteCustomResourceDefinition(name string) error {
+	return c.Resource(CustomResourceDefinitionResource, false).Delete(name)
+}
+
+// Returns true once the api server started serving the custom resources of the definition
+func IsCustomResourceDefinitionEstablished(crd Unstructured) bool {
+	conditions, found := NestedField(crd, "status", "conditions")
+	if !found {
+		return false
+	}
+	conditionList, ok := conditions.([]interface{})
+	if !ok {
+		return false
+	}
+	for _, condition := range conditionList {
+		conditionMap, ok := condition.(map[string]interface{})
+		if ok && conditionMap["type"] == "Established" {
+			return conditionMap["status"] == "True"
+		}
+	}
+	return false
+}
+
+func (c *Client) WaitForCustomResourceDefinitionToBeEstablished(name string, maxRetries int, sleepDuration time.Duration) error {
+	getter := func() (interface{}, error) {
+		return c.GetCustomResourceDefinition(name)
+	}
+	established := func(obj interface{}) (bool, error) {
+		return IsCustomResourceDefinitionEstablished(obj.(Unstructured)), nil
+	}
+	_, err := WaitFor(context.Background(), getter, established, WaitOptions{
+		Description: "custom resource definition " + name + " to be established",
+		Timeout:     time.Duration(maxRetries) * sleepDuration,
+		Interval:    sleepDuration,
+		Watch:       c.WatchTrigger(CustomResourceDefinitionResource, false, nil),
+		Progress:    logWaitProgress("Waiting for custom resource definition " + name + " to be established"),
+	})
+	if IsWaitTimeout(err) {
+		return fmt.Errorf("Custom resource definition %s was not established after %d retries", name, maxRetries)
+	}
+	return err
+}
+
+// Returns a dynamic accessor for namespaced custom resources of the given group, version and plural name
+func (c *Client) CustomResource(group string, version string, plural string) *DynamicResource {
+	return c.Resource(GroupVersionResource{Group: group, Version: version, Resource: plural}, true)
+}
@@ -0,0 +1,118 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testTypes = `package example
+
+import (
+	"ocopea/kubernetes/client/resource"
+	"time"
+)
+
+type Phase string
+
+type Limits map[string]resource.Quantity
+
+type Widget struct {
+	Name     string
+	Phase    Phase
+	Created  time.Time
+	Replicas *int
+	Labels   map[string]string
+	Owners   []Owner
+	Ports    []int
+	Limits   Limits
+	Data     map[string][]byte
+	Status
+}
+
+type Owner struct {
+	Name string
+	Size resource.Quantity
+}
+
+type Status struct {
+	Ready bool
+}
+`
+
+func TestGenerate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "deepcopygen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err = ioutil.WriteFile(filepath.Join(dir, "types.go"), []byte(testTypes), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := generateAll(dir, "ocopea/kubernetes/client/example", []string{"."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	generated := string(files[filepath.Join(dir, generatedFileName)])
+	expected := []string{
+		"func (in Limits) DeepCopy() Limits {",
+		"(*out)[key] = *val.DeepCopy()",
+		"*out = new(int)\n\t\t**out = **in",
+		"for i := range *in {\n\t\t\t(*in)[i].DeepCopyInto(&(*out)[i])",
+		"*out = make([]int, len(*in))\n\t\tcopy(*out, *in)",
+		"in, out := &in.Limits, &out.Limits\n\t\t*out = make(Limits, len(*in))",
+		"outVal := val\n\t\t\tif val != nil {",
+		"in.Size.DeepCopyInto(&out.Size)",
+		"func (in *Status) DeepCopyInto(out *Status) {\n\t*out = *in\n}",
+	}
+	for _, e := range expected {
+		if !strings.Contains(generated, e) {
+			t.Errorf("expected generated source to contain %q, got\n%s", e, generated)
+		}
+	}
+	// Fields that are copied by value are left to the shallow copy
+	for _, unexpected := range []string{"in.Phase", "in.Created", "in.Status", "func (in Phase)"} {
+		if strings.Contains(generated, unexpected) {
+			t.Errorf("unexpected %s in generated source\n%s", unexpected, generated)
+		}
+	}
+}
+
+func TestGenerateUnknownType(t *testing.T) {
+	dir, err := ioutil.TempDir("", "deepcopygen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	source := "package example\n\nimport \"net/url\"\n\ntype Link struct {\n\tURL url.URL\n}\n"
+	if err = ioutil.WriteFile(filepath.Join(dir, "types.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = generateAll(dir, "ocopea/kubernetes/client/example", []string{"."})
+	if err == nil || !strings.Contains(err.Error(), "net/url.URL") {
+		t.Errorf("expected generating the deep copy of a type of an unknown package to fail, got %v", err)
+	}
+}
+
+// The checked in deep copy methods must match the checked in types, run go generate after changing them
+func TestGeneratedMethodsUpToDate(t *testing.T) {
+	files, err := generateAll("..", "ocopea/kubernetes/client",
+		[]string{"v1", "unversioned", "types", "batch/v1", "apps/v1", "networking/v1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for fileName, source := range files {
+		existing, err := ioutil.ReadFile(fileName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(existing, source) {
+			t.Errorf("%s is out of date, run go generate in client/deepcopygen", fileName)
+		}
+	}
+}
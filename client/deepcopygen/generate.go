@@ -0,0 +1,460 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Types of packages that are not generated, by qualified name, and whether they need deep copying. Those that do
+// must have DeepCopyInto and DeepCopy methods with pointer receivers
+var externalTypes = map[string]bool{
+	"time.Time": false,
+	"ocopea/kubernetes/client/resource.Quantity": true,
+}
+
+// typeContext resolves the names used by the declarations of a source file
+type typeContext struct {
+	pkg *typesPackage
+
+	// Import paths by the names the file refers to them by
+	imports map[string]string
+}
+
+type typeDecl struct {
+	name string
+	expr ast.Expr
+	ctx  *typeContext
+}
+
+type typesPackage struct {
+	importPath string
+	name       string
+	dir        string
+
+	// Declarations by name and in source order
+	types map[string]*typeDecl
+	decls []*typeDecl
+}
+
+// Parses the types declared by the non generated, non test source files of the package directory
+func parsePackage(importPath string, dir string) (*typesPackage, error) {
+	fileNames, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(fileNames)
+	pkg := &typesPackage{importPath: importPath, dir: dir, types: make(map[string]*typeDecl)}
+	fset := token.NewFileSet()
+	for _, fileName := range fileNames {
+		if strings.HasSuffix(fileName, "_test.go") || filepath.Base(fileName) == generatedFileName {
+			continue
+		}
+		src, err := ioutil.ReadFile(fileName)
+		if err != nil {
+			return nil, err
+		}
+		file, err := parser.ParseFile(fset, fileName, src, 0)
+		if err != nil {
+			return nil, err
+		}
+		pkg.name = file.Name.Name
+
+		ctx := &typeContext{pkg: pkg, imports: make(map[string]string)}
+		for _, spec := range file.Imports {
+			importPath, _ := strconv.Unquote(spec.Path.Value)
+			name := path.Base(importPath)
+			if spec.Name != nil {
+				name = spec.Name.Name
+			}
+			ctx.imports[name] = importPath
+		}
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				d := &typeDecl{name: typeSpec.Name.Name, expr: typeSpec.Type, ctx: ctx}
+				pkg.types[d.name] = d
+				pkg.decls = append(pkg.decls, d)
+			}
+		}
+	}
+	if pkg.name == "" {
+		return nil, fmt.Errorf("no source files in %s", dir)
+	}
+	return pkg, nil
+}
+
+// generator generates the deep copy methods of the types of a set of packages, which may refer to each other
+type generator struct {
+	packages map[string]*typesPackage
+	deep     map[*typeDecl]bool
+	visiting map[*typeDecl]bool
+
+	// State of the package being generated
+	pkg     *typesPackage
+	imports map[string]string
+	buf     *bytes.Buffer
+}
+
+func newGenerator(packages []*typesPackage) *generator {
+	g := &generator{
+		packages: make(map[string]*typesPackage),
+		deep:     make(map[*typeDecl]bool),
+		visiting: make(map[*typeDecl]bool),
+	}
+	for _, pkg := range packages {
+		g.packages[pkg.importPath] = pkg
+	}
+	return g
+}
+
+// Resolves a type name, returning its declaration, or its qualified name for predeclared types and types of
+// packages that are not generated
+func (g *generator) resolve(expr ast.Expr, ctx *typeContext) (*typeDecl, string, error) {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if d, found := ctx.pkg.types[e.Name]; found {
+			return d, "", nil
+		}
+		if types.Universe.Lookup(e.Name) != nil {
+			return nil, e.Name, nil
+		}
+		return nil, "", fmt.Errorf("unknown type %s in package %s", e.Name, ctx.pkg.importPath)
+	case *ast.SelectorExpr:
+		x, _ := e.X.(*ast.Ident)
+		if x == nil {
+			return nil, "", fmt.Errorf("unsupported type %s", types.ExprString(expr))
+		}
+		importPath, found := ctx.imports[x.Name]
+		if !found {
+			return nil, "", fmt.Errorf("unknown package %s in package %s", x.Name, ctx.pkg.importPath)
+		}
+		if pkg, found := g.packages[importPath]; found {
+			if d, found := pkg.types[e.Sel.Name]; found {
+				return d, "", nil
+			}
+			return nil, "", fmt.Errorf("unknown type %s", types.ExprString(expr))
+		}
+		return nil, importPath + "." + e.Sel.Name, nil
+	}
+	return nil, "", fmt.Errorf("unsupported type %s", types.ExprString(expr))
+}
+
+// Returns true in case copying values of the type shares pointers, maps or slices
+func (g *generator) needsDeepCopy(expr ast.Expr, ctx *typeContext) (bool, error) {
+	switch e := expr.(type) {
+	case *ast.StarExpr, *ast.MapType:
+		return true, nil
+	case *ast.ArrayType:
+		if e.Len == nil {
+			return true, nil
+		}
+		return false, fmt.Errorf("unsupported array type %s", types.ExprString(expr))
+	case *ast.Ident, *ast.SelectorExpr:
+		d, name, err := g.resolve(expr, ctx)
+		if err != nil || d != nil {
+			return g.declNeedsDeepCopy(d), err
+		}
+		if types.Universe.Lookup(name) != nil {
+			if name == "error" {
+				return false, fmt.Errorf("unsupported type %s", name)
+			}
+			return false, nil
+		}
+		deep, found := externalTypes[name]
+		if !found {
+			return false, fmt.Errorf("unknown external type %s, add it to the external types of the generator", name)
+		}
+		return deep, nil
+	}
+	return false, fmt.Errorf("unsupported type %s", types.ExprString(expr))
+}
+
+func (g *generator) declNeedsDeepCopy(d *typeDecl) bool {
+	if d == nil {
+		return false
+	}
+	if deep, found := g.deep[d]; found {
+		return deep
+	}
+	// Types referring to themselves do so through pointers or slices, which need deep copying anyway
+	if g.visiting[d] {
+		return false
+	}
+	g.visiting[d] = true
+	defer delete(g.visiting, d)
+
+	deep := false
+	if structType, ok := d.expr.(*ast.StructType); ok {
+		for _, field := range structType.Fields.List {
+			if fieldDeep, _ := g.needsDeepCopy(field.Type, d.ctx); fieldDeep {
+				deep = true
+			}
+		}
+	} else {
+		deep, _ = g.needsDeepCopy(d.expr, d.ctx)
+	}
+	g.deep[d] = deep
+	return deep
+}
+
+// Returns true in case values of the type are copied by their DeepCopyInto method
+func (g *generator) hasDeepCopyMethods(expr ast.Expr, ctx *typeContext) bool {
+	d, name, err := g.resolve(expr, ctx)
+	if err != nil {
+		return false
+	}
+	if d != nil {
+		_, isStruct := d.expr.(*ast.StructType)
+		return isStruct
+	}
+	return externalTypes[name]
+}
+
+// Returns the name the generated file refers to the package by, importing it
+func (g *generator) importName(importPath string) string {
+	if name, found := g.imports[importPath]; found {
+		return name
+	}
+	name := path.Base(importPath)
+	for _, importedName := range g.imports {
+		if importedName == name {
+			name = path.Base(path.Dir(importPath)) + name
+		}
+	}
+	g.imports[importPath] = name
+	return name
+}
+
+// Returns the type expression in the generated file for a type expression of the context
+func (g *generator) typeString(expr ast.Expr, ctx *typeContext) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if ctx.pkg == g.pkg || types.Universe.Lookup(e.Name) != nil {
+			return e.Name
+		}
+		return g.importName(ctx.pkg.importPath) + "." + e.Name
+	case *ast.SelectorExpr:
+		importPath := ctx.imports[e.X.(*ast.Ident).Name]
+		if importPath == g.pkg.importPath {
+			return e.Sel.Name
+		}
+		return g.importName(importPath) + "." + e.Sel.Name
+	case *ast.StarExpr:
+		return "*" + g.typeString(e.X, ctx)
+	case *ast.ArrayType:
+		return "[]" + g.typeString(e.Elt, ctx)
+	case *ast.MapType:
+		return "map[" + g.typeString(e.Key, ctx) + "]" + g.typeString(e.Value, ctx)
+	}
+	return types.ExprString(expr)
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(g.buf, format, args...)
+}
+
+// Writes the statements making out a deep copy of in, where out is a shallow copy of in already
+func (g *generator) writeCopy(in string, out string, expr ast.Expr, ctx *typeContext) error {
+	if g.hasDeepCopyMethods(expr, ctx) {
+		g.printf("%s.DeepCopyInto(&%s)\n", in, out)
+		return nil
+	}
+	typeName := g.typeString(expr, ctx)
+	if d, _, _ := g.resolve(expr, ctx); d != nil {
+		// Named map, slice and pointer types are copied like their underlying type
+		expr, ctx = d.expr, d.ctx
+	}
+	g.printf("if %s != nil {\nin, out := &%s, &%s\n", in, in, out)
+	if err := g.writeCopyBody(expr, ctx, typeName); err != nil {
+		return err
+	}
+	g.printf("}\n")
+	return nil
+}
+
+// Writes the statements making *out a deep copy of *in, both pointers to non nil values of the map, slice or
+// pointer type
+func (g *generator) writeCopyBody(expr ast.Expr, ctx *typeContext, typeName string) error {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		g.printf("*out = new(%s)\n", g.typeString(e.X, ctx))
+		deep, err := g.needsDeepCopy(e.X, ctx)
+		if err != nil {
+			return err
+		}
+		if !deep {
+			g.printf("**out = **in\n")
+		} else if g.hasDeepCopyMethods(e.X, ctx) {
+			g.printf("(*in).DeepCopyInto(*out)\n")
+		} else {
+			g.printf("**out = **in\n")
+			return g.writeCopy("(**in)", "(**out)", e.X, ctx)
+		}
+	case *ast.ArrayType:
+		g.printf("*out = make(%s, len(*in))\n", typeName)
+		deep, err := g.needsDeepCopy(e.Elt, ctx)
+		if err != nil {
+			return err
+		}
+		if deep && g.hasDeepCopyMethods(e.Elt, ctx) {
+			g.printf("for i := range *in {\n(*in)[i].DeepCopyInto(&(*out)[i])\n}\n")
+			return nil
+		}
+		g.printf("copy(*out, *in)\n")
+		if deep {
+			g.printf("for i := range *in {\n")
+			if err = g.writeCopy("(*in)[i]", "(*out)[i]", e.Elt, ctx); err != nil {
+				return err
+			}
+			g.printf("}\n")
+		}
+	case *ast.MapType:
+		g.printf("*out = make(%s, len(*in))\nfor key, val := range *in {\n", typeName)
+		deep, err := g.needsDeepCopy(e.Value, ctx)
+		if err != nil {
+			return err
+		}
+		if deep && g.hasDeepCopyMethods(e.Value, ctx) {
+			g.printf("(*out)[key] = *val.DeepCopy()\n")
+		} else if deep {
+			g.printf("outVal := val\n")
+			if err = g.writeCopy("val", "outVal", e.Value, ctx); err != nil {
+				return err
+			}
+			g.printf("(*out)[key] = outVal\n")
+		} else {
+			g.printf("(*out)[key] = val\n")
+		}
+		g.printf("}\n")
+	default:
+		return fmt.Errorf("unsupported type %s", types.ExprString(expr))
+	}
+	return nil
+}
+
+// Returns the name of a struct field, the type name for embedded fields
+func embeddedFieldName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return embeddedFieldName(e.X)
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	case *ast.Ident:
+		return e.Name
+	}
+	return ""
+}
+
+func (g *generator) writeStructMethods(d *typeDecl, structType *ast.StructType) error {
+	g.printf("// DeepCopyInto copies the receiver into out, in must be non nil\n")
+	g.printf("func (in *%s) DeepCopyInto(out *%s) {\n*out = *in\n", d.name, d.name)
+	for _, field := range structType.Fields.List {
+		deep, err := g.needsDeepCopy(field.Type, d.ctx)
+		if err != nil {
+			return fmt.Errorf("failed generating deep copy of %s.%s - %s", d.ctx.pkg.importPath, d.name, err.Error())
+		}
+		if !deep {
+			continue
+		}
+		names := []string{embeddedFieldName(field.Type)}
+		if len(field.Names) > 0 {
+			names = names[:0]
+			for _, name := range field.Names {
+				names = append(names, name.Name)
+			}
+		}
+		for _, name := range names {
+			if err = g.writeCopy("in."+name, "out."+name, field.Type, d.ctx); err != nil {
+				return fmt.Errorf("failed generating deep copy of %s.%s - %s", d.ctx.pkg.importPath, d.name, err.Error())
+			}
+		}
+	}
+	g.printf("}\n\n")
+
+	g.printf("// DeepCopy returns a copy of the receiver sharing no pointers, maps or slices with it\n")
+	g.printf("func (in *%s) DeepCopy() *%s {\nif in == nil {\nreturn nil\n}\n", d.name, d.name)
+	g.printf("out := new(%s)\nin.DeepCopyInto(out)\nreturn out\n}\n\n", d.name)
+	return nil
+}
+
+// Writes the methods of named map, slice and pointer types
+func (g *generator) writeNamedTypeMethods(d *typeDecl) error {
+	g.printf("// DeepCopyInto copies the receiver into out, in must be non nil\n")
+	g.printf("func (in %s) DeepCopyInto(out *%s) {\n{\nin := &in\n", d.name, d.name)
+	if err := g.writeCopyBody(d.expr, d.ctx, d.name); err != nil {
+		return fmt.Errorf("failed generating deep copy of %s.%s - %s", d.ctx.pkg.importPath, d.name, err.Error())
+	}
+	g.printf("}\n}\n\n")
+
+	g.printf("// DeepCopy returns a copy of the receiver sharing no pointers, maps or slices with it\n")
+	g.printf("func (in %s) DeepCopy() %s {\nif in == nil {\nreturn nil\n}\n", d.name, d.name)
+	g.printf("out := new(%s)\nin.DeepCopyInto(out)\nreturn *out\n}\n\n", d.name)
+	return nil
+}
+
+// Returns the formatted source of the deep copy methods of the types of the package
+func (g *generator) generate(pkg *typesPackage) ([]byte, error) {
+	g.pkg = pkg
+	g.imports = make(map[string]string)
+	g.buf = &bytes.Buffer{}
+	for _, d := range pkg.decls {
+		if structType, ok := d.expr.(*ast.StructType); ok {
+			if err := g.writeStructMethods(d, structType); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		deep, err := g.needsDeepCopy(d.expr, d.ctx)
+		if err != nil {
+			// Types that are never copied as fields, e.g. interfaces, get no methods
+			continue
+		}
+		if deep {
+			if err = g.writeNamedTypeMethods(d); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "// Code generated by deepcopygen. DO NOT EDIT.\n\npackage %s\n\n", pkg.name)
+	if len(g.imports) > 0 {
+		importPaths := make([]string, 0, len(g.imports))
+		for importPath := range g.imports {
+			importPaths = append(importPaths, importPath)
+		}
+		sort.Strings(importPaths)
+		buf.WriteString("import (\n")
+		for _, importPath := range importPaths {
+			if name := g.imports[importPath]; name != path.Base(importPath) {
+				fmt.Fprintf(buf, "%s %q\n", name, importPath)
+			} else {
+				fmt.Fprintf(buf, "%q\n", importPath)
+			}
+		}
+		buf.WriteString(")\n\n")
+	}
+	buf.Write(g.buf.Bytes())
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed formatting deep copy methods of %s - %s", pkg.importPath, err.Error())
+	}
+	return source, nil
+}
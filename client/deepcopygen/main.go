@@ -0,0 +1,62 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+
+// Command deepcopygen generates DeepCopyInto and DeepCopy methods for the types of the api packages of the client,
+// so objects can be cached and modified without sharing maps, slices or pointers with the originals.
+// Run go generate in this directory after changing the types, including after running typegen
+package main
+
+//go:generate go run . -root .. v1 unversioned types batch/v1 apps/v1 networking/v1
+
+import (
+	"flag"
+	"io/ioutil"
+	"log"
+	"path"
+	"path/filepath"
+)
+
+// Name of the generated file in every package
+const generatedFileName = "deepcopy_generated.go"
+
+// Returns the generated source files by file name, for the packages of the root directory
+func generateAll(root string, baseImportPath string, packageDirs []string) (map[string][]byte, error) {
+	packages := make([]*typesPackage, 0, len(packageDirs))
+	for _, dir := range packageDirs {
+		pkg, err := parsePackage(path.Join(baseImportPath, dir), filepath.Join(root, filepath.FromSlash(dir)))
+		if err != nil {
+			return nil, err
+		}
+		packages = append(packages, pkg)
+	}
+
+	g := newGenerator(packages)
+	files := make(map[string][]byte, len(packages))
+	for _, pkg := range packages {
+		source, err := g.generate(pkg)
+		if err != nil {
+			return nil, err
+		}
+		files[filepath.Join(pkg.dir, generatedFileName)] = source
+	}
+	return files, nil
+}
+
+func main() {
+	root := flag.String("root", "..", "directory of the packages")
+	baseImportPath := flag.String("base", "ocopea/kubernetes/client", "import path of the root directory")
+	flag.Parse()
+	if flag.NArg() == 0 {
+		log.Fatal("usage: deepcopygen [-root dir] [-base import path] package dir...")
+	}
+
+	files, err := generateAll(*root, *baseImportPath, flag.Args())
+	if err != nil {
+		log.Fatal(err)
+	}
+	for fileName, source := range files {
+		if err = ioutil.WriteFile(fileName, source, 0644); err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("generated %s\n", fileName)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"ocopea/kubernetes/client/v1"
+	"strings"
+	"time"
+)
+
+// Maximal time to wait for the pods of a replication controller to terminate before deleting it
+const replicationControllerScaleDownTimeout = 5 * time.Minute
+
+// Returns delete options with the given propagation policy, gracePeriodSeconds is ignored when negative
+func NewDeleteOptions(gracePeriodSeconds int64, propagationPolicy v1.DeletionPropagation) *v1.DeleteOptions {
+	options := &v1.DeleteOptions{PropagationPolicy: &propagationPolicy}
+	if gracePeriodSeconds >= 0 {
+		options.GracePeriodSeconds = &gracePeriodSeconds
+	}
+	return options
+}
+
+// Returns true in case the options ask to leave the dependents of the deleted object behind
+func isOrphaning(options *v1.DeleteOptions) bool {
+	return options != nil && options.PropagationPolicy != nil && *options.PropagationPolicy == v1.DeletePropagationOrphan
+}
+
+// Sets the number of replicas of the replication controller
+func (c *Client) ScaleReplicationController(rcName string, replicas int) (*v1.ReplicationController, error) {
+	resource := "/api/v1/namespaces/" + c.Namespace + "/replicationcontrollers/" + rcName
+	patch := strings.NewReader(fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas))
+	resp, err := c.doHttpPath("PATCH", resource, patch, string(MergePatchType))
+	if err != nil {
+		return nil, fmt.Errorf("Failed scaling replication controller %s - %s", rcName, err.Error())
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError("PATCH", resource, resp)
+	}
+	rc := &v1.ReplicationController{}
+	return rc, json.NewDecoder(resp.Body).Decode(rc)
+}
+
+// Deletes the replication controller. Unless the propagation policy is Orphan the replication controller is
+// scaled to zero and its pods are waited for to terminate first, even with the Background or Foreground policy,
+// since older api servers ignore the propagation policy and leave the pods running
+func (c *Client) DeleteReplicationControllerWithOptions(rcName string, options *v1.DeleteOptions) error {
+	if !isOrphaning(options) {
+		rc, err := c.ScaleReplicationController(rcName, 0)
+		if err != nil {
+			return fmt.Errorf("Failed scaling down replication controller %s before deleting it - %s", rcName, err.Error())
+		}
+		if err = c.waitForPodsToTerminate(rcName, replicationControllerPodSelector(rc)); err != nil {
+			return err
+		}
+	}
+	return c.deleteEntityWithOptions("namespaces/"+c.Namespace+"/replicationcontrollers/"+rcName, options)
+}
+
+// Returns the labels of the pods the replication controller manages
+func replicationControllerPodSelector(rc *v1.ReplicationController) map[string]string {
+	if len(rc.Spec.Selector) > 0 {
+		return rc.Spec.Selector
+	}
+	if rc.Spec.Template != nil {
+		return rc.Spec.Template.Labels
+	}
+	return nil
+}
+
+func (c *Client) waitForPodsToTerminate(rcName string, podSelector map[string]string) error {
+	if len(podSelector) == 0 {
+		return nil
+	}
+	_, err := WaitFor(context.Background(), c.PodListGetter(podSelector), PodsTerminated, WaitOptions{
+		Description: "pods of replication controller " + rcName + " to terminate",
+		Timeout:     replicationControllerScaleDownTimeout,
+		Interval:    time.Second,
+		Watch:       c.WatchTrigger(coreResource("pods"), true, podSelector),
+	})
+	return err
+}
+
+// Deletes all the objects of a namespaced core resource (e.g. "pods") matching the label filters.
+// Note that no label filters means deleting all the objects of the resource in the namespace
+func (c *Client) DeleteCollection(resource string, labelFilters map[string]string, options *v1.DeleteOptions) error {
+	return c.deleteEntityWithOptions(
+		"namespaces/"+c.Namespace+"/"+resource+buildLabelsQueryString(labelFilters),
+		options)
+}
@@ -0,0 +1,246 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+type FieldDiffType string
+
+const (
+	// The field is set in the desired object but missing from the live one
+	FieldAdded FieldDiffType = "added"
+	// The field is found in the live object but not in the desired one, only reported for list items
+	// since other fields missing from the desired object are defaulted by the server
+	FieldRemoved FieldDiffType = "removed"
+	FieldChanged FieldDiffType = "changed"
+)
+
+// FieldDiff is a single difference between the desired and the live object
+type FieldDiff struct {
+	// Path of the field, e.g. spec.template.spec.containers[name=orcs].image
+	Path    string        `json:"path"`
+	Type    FieldDiffType `json:"type"`
+	Desired interface{}   `json:"desired,omitempty"`
+	Live    interface{}   `json:"live,omitempty"`
+}
+
+// ObjectDiff lists the differences between a desired object and the live one, sorted by path
+type ObjectDiff struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+
+	// False in case the object does not exist in the cluster
+	Exists bool        `json:"exists"`
+	Fields []FieldDiff `json:"fields"`
+}
+
+// Returns true in case the live object matches the desired one
+func (d *ObjectDiff) Empty() bool {
+	return d.Exists && len(d.Fields) == 0
+}
+
+// Formats the diff for humans, e.g.
+//
+//	ReplicationController orcs:
+//	  ~ spec.replicas: 1 -> 2
+func (d *ObjectDiff) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s %s:\n", d.Kind, d.Name)
+	if !d.Exists {
+		buf.WriteString("  does not exist\n")
+		return buf.String()
+	}
+	if len(d.Fields) == 0 {
+		buf.WriteString("  no differences\n")
+	}
+	for _, field := range d.Fields {
+		switch field.Type {
+		case FieldAdded:
+			fmt.Fprintf(&buf, "  + %s: %s\n", field.Path, formatDiffValue(field.Desired))
+		case FieldRemoved:
+			fmt.Fprintf(&buf, "  - %s: %s\n", field.Path, formatDiffValue(field.Live))
+		default:
+			fmt.Fprintf(&buf, "  ~ %s: %s -> %s\n", field.Path, formatDiffValue(field.Live), formatDiffValue(field.Desired))
+		}
+	}
+	return buf.String()
+}
+
+func formatDiffValue(value interface{}) string {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(b)
+}
+
+// Returns the live object without status and the fields the server and apply keep in its metadata
+func liveConfiguration(live Unstructured) Unstructured {
+	u := Unstructured{}
+	for key, value := range live {
+		if key != "status" {
+			u[key] = value
+		}
+	}
+	if metadata, ok := live["metadata"].(map[string]interface{}); ok {
+		metadataCopy := make(map[string]interface{}, len(metadata))
+		for key, value := range metadata {
+			metadataCopy[key] = value
+		}
+		for _, field := range serverSetMetadataFields {
+			delete(metadataCopy, field)
+		}
+		if annotations, ok := metadataCopy["annotations"].(map[string]interface{}); ok {
+			annotationsCopy := make(map[string]interface{}, len(annotations))
+			for key, value := range annotations {
+				if key != LastAppliedConfigAnnotation {
+					annotationsCopy[key] = value
+				}
+			}
+			metadataCopy["annotations"] = annotationsCopy
+		}
+		u["metadata"] = metadataCopy
+	}
+	return u
+}
+
+// Returns the names of the list items in case all of them are objects with a unique name, e.g. containers or env
+func namedListItems(list []interface{}) (map[string]interface{}, bool) {
+	named := make(map[string]interface{}, len(list))
+	for _, item := range list {
+		name, ok := NestedField(item.(map[string]interface{}), "name")
+		if !ok {
+			return nil, false
+		}
+		nameStr, ok := name.(string)
+		if _, duplicate := named[nameStr]; !ok || duplicate {
+			return nil, false
+		}
+		named[nameStr] = item
+	}
+	return named, true
+}
+
+func isObjectList(list []interface{}) bool {
+	for _, item := range list {
+		if _, ok := item.(map[string]interface{}); !ok {
+			return false
+		}
+	}
+	return len(list) > 0
+}
+
+// Appends the differences between the desired and the live values found in path
+func diffValues(path string, desired interface{}, live interface{}, diffs []FieldDiff) []FieldDiff {
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			break
+		}
+		for key, value := range d {
+			fieldPath := key
+			if path != "" {
+				fieldPath = path + "." + key
+			}
+			liveValue, found := l[key]
+			if !found {
+				diffs = append(diffs, FieldDiff{Path: fieldPath, Type: FieldAdded, Desired: value})
+				continue
+			}
+			diffs = diffValues(fieldPath, value, liveValue, diffs)
+		}
+		return diffs
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok {
+			break
+		}
+		if isObjectList(d) && isObjectList(l) {
+			desiredItems, desiredNamed := namedListItems(d)
+			liveItems, liveNamed := namedListItems(l)
+			if desiredNamed && liveNamed {
+				for name, item := range desiredItems {
+					itemPath := path + "[name=" + name + "]"
+					if liveItem, found := liveItems[name]; found {
+						diffs = diffValues(itemPath, item, liveItem, diffs)
+					} else {
+						diffs = append(diffs, FieldDiff{Path: itemPath, Type: FieldAdded, Desired: item})
+					}
+				}
+				for name, item := range liveItems {
+					if _, found := desiredItems[name]; !found {
+						diffs = append(diffs, FieldDiff{Path: path + "[name=" + name + "]", Type: FieldRemoved, Live: item})
+					}
+				}
+				return diffs
+			}
+		}
+		for i := range d {
+			itemPath := path + "[" + strconv.Itoa(i) + "]"
+			if i < len(l) {
+				diffs = diffValues(itemPath, d[i], l[i], diffs)
+			} else {
+				diffs = append(diffs, FieldDiff{Path: itemPath, Type: FieldAdded, Desired: d[i]})
+			}
+		}
+		for i := len(d); i < len(l); i++ {
+			diffs = append(diffs, FieldDiff{Path: path + "[" + strconv.Itoa(i) + "]", Type: FieldRemoved, Live: l[i]})
+		}
+		return diffs
+	}
+
+	if !reflect.DeepEqual(desired, live) {
+		diffs = append(diffs, FieldDiff{Path: path, Type: FieldChanged, Desired: desired, Live: live})
+	}
+	return diffs
+}
+
+// Compares the desired object (typed, e.g. *v1.ReplicationController, or Unstructured) with the live one.
+// Status, fields set by the server and fields the desired object leaves unset (e.g. defaulted by the server)
+// are ignored
+func DiffObjects(desired interface{}, live interface{}) (*ObjectDiff, error) {
+	desiredConfig, err := desiredConfiguration(desired)
+	if err != nil {
+		return nil, fmt.Errorf("Failed comparing object - %s", err.Error())
+	}
+	liveUnstructured, ok := live.(Unstructured)
+	if !ok {
+		if liveUnstructured, err = ToUnstructured(live); err != nil {
+			return nil, fmt.Errorf("Failed comparing %s %s - %s", desiredConfig.GetKind(), desiredConfig.GetName(), err.Error())
+		}
+	}
+
+	// The kind and api version of typed live objects are usually empty, those are compared by the caller
+	kind, name := desiredConfig.GetKind(), desiredConfig.GetName()
+	delete(desiredConfig, "kind")
+	delete(desiredConfig, "apiVersion")
+
+	fields := diffValues(
+		"", map[string]interface{}(desiredConfig), map[string]interface{}(liveConfiguration(liveUnstructured)), []FieldDiff{})
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Path < fields[j].Path })
+	return &ObjectDiff{Kind: kind, Name: name, Exists: true, Fields: fields}, nil
+}
+
+// Compares the desired object (typed, e.g. *v1.ReplicationController, or Unstructured) with the live object
+// of the same name, see DiffObjects
+func (c *Client) Diff(desired interface{}) (*ObjectDiff, error) {
+	desiredConfig, err := desiredConfiguration(desired)
+	if err != nil {
+		return nil, fmt.Errorf("Failed comparing object - %s", err.Error())
+	}
+	kind := manifestKindOf(desiredConfig.GetAPIVersion(), desiredConfig.GetKind())
+	live, err := c.manifestResource(manifestObject{object: desiredConfig, kind: kind}).Get(desiredConfig.GetName())
+	if IsNotFound(err) {
+		return &ObjectDiff{Kind: desiredConfig.GetKind(), Name: desiredConfig.GetName(), Fields: []FieldDiff{}}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("Failed getting %s %s - %s", desiredConfig.GetKind(), desiredConfig.GetName(), err.Error())
+	}
+	return DiffObjects(desired, live)
+}
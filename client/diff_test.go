@@ -0,0 +1,103 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"ocopea/kubernetes/client/types"
+	"ocopea/kubernetes/client/v1"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func orcsTestReplicationController(image string, env ...v1.EnvVar) *v1.ReplicationController {
+	replicas := 1
+	return &v1.ReplicationController{
+		ObjectMeta: v1.ObjectMeta{Name: "orcs", Labels: map[string]string{"nazKind": "app"}},
+		Spec: v1.ReplicationControllerSpec{
+			Replicas: &replicas,
+			Selector: map[string]string{"app": "orcs"},
+			Template: &v1.PodTemplateSpec{
+				ObjectMeta: v1.ObjectMeta{Labels: map[string]string{"app": "orcs"}},
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{{Name: "orcs", Image: image, Env: env}},
+				},
+			},
+		},
+	}
+}
+
+func TestDiffObjects(t *testing.T) {
+	live := orcsTestReplicationController("ocopea/orcs:1", v1.EnvVar{Name: "A", Value: "1"}, v1.EnvVar{Name: "B", Value: "2"})
+	// Fields set by the server are not a difference
+	live.UID = "9d1b3a"
+	live.ResourceVersion = "12"
+	live.Status.Replicas = 1
+	live.SetDefaults()
+
+	diff, err := DiffObjects(orcsTestReplicationController("ocopea/orcs:1", v1.EnvVar{Name: "B", Value: "2"}, v1.EnvVar{Name: "A", Value: "1"}), live)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !diff.Empty() || diff.Kind != "ReplicationController" || diff.Name != "orcs" {
+		t.Errorf("expected no differences, got %s", diff.String())
+	}
+
+	desired := orcsTestReplicationController("ocopea/orcs:2", v1.EnvVar{Name: "A", Value: "3"}, v1.EnvVar{Name: "C", Value: "4"})
+	if diff, err = DiffObjects(desired, live); err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, field := range diff.Fields {
+		paths = append(paths, string(field.Type)+" "+field.Path)
+	}
+	expected := []string{
+		"changed spec.template.spec.containers[name=orcs].env[name=A].value",
+		"removed spec.template.spec.containers[name=orcs].env[name=B]",
+		"added spec.template.spec.containers[name=orcs].env[name=C]",
+		"changed spec.template.spec.containers[name=orcs].image",
+	}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected differences %v, got %v", expected, paths)
+	}
+	if text := diff.String(); !strings.Contains(text, `~ spec.template.spec.containers[name=orcs].image: "ocopea/orcs:1" -> "ocopea/orcs:2"`) {
+		t.Errorf("unexpected diff text %s", text)
+	}
+}
+
+func TestDiffObjectsDefaults(t *testing.T) {
+	// Target ports are serialized as 0 when left unset, the server defaults them to the port
+	desired := &v1.Service{
+		ObjectMeta: v1.ObjectMeta{Name: "orcs"},
+		Spec:       v1.ServiceSpec{Ports: []v1.ServicePort{{Name: "http", Port: 8080}}},
+	}
+	live := &v1.Service{
+		ObjectMeta: v1.ObjectMeta{Name: "orcs", UID: "9d1b3a"},
+		Spec: v1.ServiceSpec{
+			ClusterIP:       "10.0.0.12",
+			Type:            v1.ServiceTypeClusterIP,
+			SessionAffinity: v1.ServiceAffinityNone,
+			Ports: []v1.ServicePort{{
+				Name: "http", Port: 8080, Protocol: v1.ProtocolTCP, TargetPort: types.NewIntOrStringFromInt(8080),
+			}},
+		},
+	}
+
+	diff, err := DiffObjects(desired, live)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !diff.Empty() {
+		t.Errorf("expected no differences, got %s", diff.String())
+	}
+	if desired.Spec.Ports[0].TargetPort.IntVal != 0 {
+		t.Errorf("expected the desired object to be left as is, got %+v", desired.Spec.Ports[0])
+	}
+
+	desired.Spec.Ports[0].TargetPort.IntVal = 9090
+	if diff, err = DiffObjects(desired, live); err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.Fields) != 1 || diff.Fields[0].Path != "spec.ports[name=http].targetPort" {
+		t.Errorf("expected the target port to differ, got %s", diff.String())
+	}
+}
@@ -0,0 +1,363 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+)
+
+// GroupVersionResource identifies a kind of resource served by the api server by its group, version and
+// plural resource name (e.g. {"batch", "v1", "jobs"}). The core group is identified by an empty group
+type GroupVersionResource struct {
+	Group    string
+	Version  string
+	Resource string
+}
+
+func (gvr GroupVersionResource) String() string {
+	if gvr.Group == "" {
+		return gvr.Version + "/" + gvr.Resource
+	}
+	return gvr.Group + "/" + gvr.Version + "/" + gvr.Resource
+}
+
+// The root path the resource is served from, core resources are served from /api while others from /apis
+func (gvr GroupVersionResource) apiPath() string {
+	if gvr.Group == "" {
+		return "/api/" + gvr.Version
+	}
+	return "/apis/" + gvr.Group + "/" + gvr.Version
+}
+
+// Unstructured is a kubernetes object of any kind, represented as decoded json
+type Unstructured map[string]interface{}
+
+// Returns the value found in the nested fields path of the object
+func NestedField(obj map[string]interface{}, fields ...string) (interface{}, bool) {
+	var value interface{} = obj
+	for _, field := range fields {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		value, ok = m[field]
+		if !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// Returns the string found in the nested fields path of the object, empty string if not found
+func NestedString(obj map[string]interface{}, fields ...string) string {
+	value, found := NestedField(obj, fields...)
+	if !found {
+		return ""
+	}
+	str, _ := value.(string)
+	return str
+}
+
+// Sets a value in the nested fields path of the object, creating the intermediate maps when missing
+func SetNestedField(obj map[string]interface{}, value interface{}, fields ...string) {
+	m := obj
+	for _, field := range fields[:len(fields)-1] {
+		child, ok := m[field].(map[string]interface{})
+		if !ok {
+			child = make(map[string]interface{})
+			m[field] = child
+		}
+		m = child
+	}
+	m[fields[len(fields)-1]] = value
+}
+
+func (u Unstructured) GetAPIVersion() string {
+	return NestedString(u, "apiVersion")
+}
+
+func (u Unstructured) GetKind() string {
+	return NestedString(u, "kind")
+}
+
+func (u Unstructured) GetName() string {
+	return NestedString(u, "metadata", "name")
+}
+
+func (u Unstructured) SetName(name string) {
+	SetNestedField(u, name, "metadata", "name")
+}
+
+func (u Unstructured) GetNamespace() string {
+	return NestedString(u, "metadata", "namespace")
+}
+
+func (u Unstructured) SetNamespace(namespace string) {
+	SetNestedField(u, namespace, "metadata", "namespace")
+}
+
+func (u Unstructured) GetResourceVersion() string {
+	return NestedString(u, "metadata", "resourceVersion")
+}
+
+func (u Unstructured) GetLabels() map[string]string {
+	return u.nestedStringMap("metadata", "labels")
+}
+
+func (u Unstructured) GetAnnotations() map[string]string {
+	return u.nestedStringMap("metadata", "annotations")
+}
+
+func (u Unstructured) nestedStringMap(fields ...string) map[string]string {
+	stringMap := make(map[string]string)
+	value, found := NestedField(u, fields...)
+	if !found {
+		return stringMap
+	}
+	if m, ok := value.(map[string]interface{}); ok {
+		for k, v := range m {
+			if str, ok := v.(string); ok {
+				stringMap[k] = str
+			}
+		}
+	}
+	return stringMap
+}
+
+// Converts a typed object (e.g. *v1.Pod) to its unstructured representation
+func ToUnstructured(obj interface{}) (Unstructured, error) {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	u := Unstructured{}
+	err = decodeUnstructured(bytes.NewReader(b), &u)
+	return u, err
+}
+
+// Converts an unstructured object into the typed object pointed by objPtr (e.g. &v1.Pod{})
+func FromUnstructured(u Unstructured, objPtr interface{}) error {
+	b, err := json.Marshal(u)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, objPtr)
+}
+
+// Numbers are kept as json.Number so int64 fields survive a round trip
+func decodeUnstructured(r io.Reader, objPtr interface{}) error {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	return dec.Decode(objPtr)
+}
+
+// PatchType is the content type of a patch request
+type PatchType string
+
+const (
+	JSONPatchType           PatchType = "application/json-patch+json"
+	MergePatchType          PatchType = "application/merge-patch+json"
+	StrategicMergePatchType PatchType = "application/strategic-merge-patch+json"
+)
+
+// WatchEventType is the type of change reported by a watch
+type WatchEventType string
+
+const (
+	WatchAdded    WatchEventType = "ADDED"
+	WatchModified WatchEventType = "MODIFIED"
+	WatchDeleted  WatchEventType = "DELETED"
+	WatchError    WatchEventType = "ERROR"
+)
+
+// UnstructuredWatchEvent is a single change reported by a watch on a dynamic resource
+type UnstructuredWatchEvent struct {
+	Type   WatchEventType `json:"type"`
+	Object Unstructured   `json:"object"`
+}
+
+// DynamicResource provides access to objects of any kind, addressed by group/version/resource.
+// Useful for kinds the typed client does not cover, such as custom resources
+type DynamicResource struct {
+	client     *Client
+	resource   GroupVersionResource
+	namespaced bool
+	namespace  string
+}
+
+// Returns a dynamic accessor for the resource. Namespaced resources are accessed in the client namespace
+// unless InNamespace is used
+func (c *Client) Resource(resource GroupVersionResource, namespaced bool) *DynamicResource {
+	return &DynamicResource{
+		client:     c,
+		resource:   resource,
+		namespaced: namespaced,
+		namespace:  c.Namespace,
+	}
+}
+
+// Returns a copy of the dynamic resource accessing objects of the given namespace
+func (r *DynamicResource) InNamespace(namespace string) *DynamicResource {
+	nsResource := *r
+	nsResource.namespace = namespace
+	return &nsResource
+}
+
+func (r *DynamicResource) collectionPath() string {
+	if r.namespaced {
+		return r.resource.apiPath() + "/namespaces/" + r.namespace + "/" + r.resource.Resource
+	}
+	return r.resource.apiPath() + "/" + r.resource.Resource
+}
+
+func (r *DynamicResource) objectPath(name string) string {
+	return r.collectionPath() + "/" + name
+}
+
+// Sends the request and decodes the response in case it returned with the expected status
+func (r *DynamicResource) do(
+	method string,
+	path string,
+	body io.Reader,
+	contentType string,
+	responsePtr interface{},
+	expectedStatusCodes ...int) error {
+	resp, err := r.client.doHttpPath(method, path, body, contentType)
+	if err != nil {
+		return fmt.Errorf("Failed %s on %s - %s", method, path, err.Error())
+	}
+	defer resp.Body.Close()
+
+	for _, statusCode := range expectedStatusCodes {
+		if resp.StatusCode == statusCode {
+			if responsePtr == nil {
+				return nil
+			}
+			return decodeUnstructured(resp.Body, responsePtr)
+		}
+	}
+	return newStatusError(method, path, resp)
+}
+
+func (r *DynamicResource) Get(name string) (Unstructured, error) {
+	obj := Unstructured{}
+	err := r.do("GET", r.objectPath(name), nil, "application/json", &obj, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (r *DynamicResource) List(labelFilters map[string]string) ([]Unstructured, error) {
+	respList := struct {
+		Items []Unstructured `json:"items"`
+	}{}
+	err := r.do("GET", r.collectionPath()+buildLabelsQueryString(labelFilters), nil, "application/json", &respList, http.StatusOK)
+	if err != nil {
+		return nil, fmt.Errorf("Failed listing %s - %s", r.resource.String(), err.Error())
+	}
+	if respList.Items == nil {
+		return []Unstructured{}, nil
+	}
+	return respList.Items, nil
+}
+
+func (r *DynamicResource) Create(obj Unstructured) (Unstructured, error) {
+	body, err := r.client.structToReader(obj)
+	if err != nil {
+		return nil, fmt.Errorf("Failed formatting %s %s to json - %s", r.resource.String(), obj.GetName(), err.Error())
+	}
+	created := Unstructured{}
+	err = r.do("POST", r.collectionPath(), body, "application/json", &created, http.StatusCreated, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (r *DynamicResource) Update(obj Unstructured) (Unstructured, error) {
+	body, err := r.client.structToReader(obj)
+	if err != nil {
+		return nil, fmt.Errorf("Failed formatting %s %s to json - %s", r.resource.String(), obj.GetName(), err.Error())
+	}
+	updated := Unstructured{}
+	err = r.do("PUT", r.objectPath(obj.GetName()), body, "application/json", &updated, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (r *DynamicResource) Patch(name string, patchType PatchType, data []byte) (Unstructured, error) {
+	patched := Unstructured{}
+	err := r.do("PATCH", r.objectPath(name), bytes.NewReader(data), string(patchType), &patched, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return patched, nil
+}
+
+func (r *DynamicResource) Delete(name string) error {
+	return r.do("DELETE", r.objectPath(name), nil, "application/json", nil, http.StatusOK, http.StatusAccepted)
+}
+
+// Watches changes to objects of the resource and sends them to the consumer channel until the close handle
+// is called or the server ends the watch, closing the consumer channel once done.
+// Use an empty resourceVersion to start watching from the current state
+func (r *DynamicResource) Watch(
+	labelFilters map[string]string,
+	resourceVersion string,
+	consumerChannel chan UnstructuredWatchEvent) (CloseHandle, error) {
+
+	path := r.collectionPath() + watchQuery(buildLabelsQueryString(labelFilters), resourceVersion)
+	resp, err := r.client.doHttpPath("GET", path, nil, "application/json")
+	if err != nil {
+		return nil, fmt.Errorf("Failed watching %s - %s", r.resource.String(), err.Error())
+	} else if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, newStatusError("GET", path, resp)
+	}
+
+	done := make(chan struct{})
+	log.Printf("watching %s\n", r.resource.String())
+	go func() {
+		defer close(consumerChannel)
+		defer resp.Body.Close()
+		dec := json.NewDecoder(resp.Body)
+		dec.UseNumber()
+		for {
+			var event UnstructuredWatchEvent
+			err := dec.Decode(&event)
+			if err != nil {
+				select {
+				case <-done:
+				default:
+					if err != io.EOF {
+						log.Printf("Error reading watch of %s - %s", r.resource.String(), err.Error())
+					}
+				}
+				return
+			}
+			select {
+			case consumerChannel <- event:
+			case <-done:
+				log.Printf("stopped watching %s\n", r.resource.String())
+				return
+			}
+		}
+	}()
+
+	var closeOnce sync.Once
+	return func() {
+		closeOnce.Do(func() {
+			log.Printf("done watching %s\n", r.resource.String())
+			close(done)
+			resp.Body.Close()
+		})
+	}, nil
+}
@@ -0,0 +1,144 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var snapshotsResource = GroupVersionResource{
+	Group:    "snapshot.storage.k8s.io",
+	Version:  "v1",
+	Resource: "volumesnapshots",
+}
+
+func TestDynamicResourcePaths(t *testing.T) {
+	requestedPaths := []string{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPaths = append(requestedPaths, r.Method+" "+r.URL.Path)
+		switch r.Method {
+		case "POST":
+			w.WriteHeader(http.StatusCreated)
+			io.Copy(w, r.Body)
+		case "GET":
+			if r.URL.Path == "/apis/snapshot.storage.k8s.io/v1/namespaces/ocopea/volumesnapshots/missing" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			io.WriteString(w, `{"kind":"VolumeSnapshot","metadata":{"name":"snap1","generation":9007199254740993}}`)
+		}
+	}))
+	defer ts.Close()
+
+	c := &Client{Url: ts.URL, Namespace: "ocopea"}
+
+	snapshot := Unstructured{"kind": "VolumeSnapshot"}
+	snapshot.SetName("snap1")
+	created, err := c.Resource(snapshotsResource, true).Create(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.GetName() != "snap1" {
+		t.Errorf("unexpected created object name %s", created.GetName())
+	}
+
+	got, err := c.Resource(snapshotsResource, true).InNamespace("other").Get("snap1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if generation, _ := NestedField(got, "metadata", "generation"); generation.(json.Number).String() != "9007199254740993" {
+		t.Errorf("large numbers should survive decoding, got %v", generation)
+	}
+
+	_, err = c.Resource(snapshotsResource, true).Get("missing")
+	if !IsNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+
+	_, err = c.Resource(CustomResourceDefinitionResource, false).Get("volumesnapshots.snapshot.storage.k8s.io")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedPaths := []string{
+		"POST /apis/snapshot.storage.k8s.io/v1/namespaces/ocopea/volumesnapshots",
+		"GET /apis/snapshot.storage.k8s.io/v1/namespaces/other/volumesnapshots/snap1",
+		"GET /apis/snapshot.storage.k8s.io/v1/namespaces/ocopea/volumesnapshots/missing",
+		"GET /apis/apiextensions.k8s.io/v1/customresourcedefinitions/volumesnapshots.snapshot.storage.k8s.io",
+	}
+	for i, expectedPath := range expectedPaths {
+		if i >= len(requestedPaths) || requestedPaths[i] != expectedPath {
+			t.Errorf("expected request %s, got %v", expectedPath, requestedPaths)
+		}
+	}
+}
+
+func TestDynamicResourceWatch(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("watch") != "true" {
+			t.Errorf("expected a watch request, got %s", r.URL.String())
+		}
+		io.WriteString(w, `{"type":"ADDED","object":{"metadata":{"name":"snap1"}}}`+"\n")
+		io.WriteString(w, `{"type":"DELETED","object":{"metadata":{"name":"snap1"}}}`+"\n")
+	}))
+	defer ts.Close()
+
+	c := &Client{Url: ts.URL, Namespace: "ocopea"}
+	events := make(chan UnstructuredWatchEvent, 2)
+	closeHandle, err := c.Resource(snapshotsResource, true).Watch(nil, "", events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeHandle()
+
+	for _, expectedType := range []WatchEventType{WatchAdded, WatchDeleted} {
+		select {
+		case event := <-events:
+			if event.Type != expectedType || event.Object.GetName() != "snap1" {
+				t.Errorf("unexpected watch event %s on %s", event.Type, event.Object.GetName())
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %s watch event", expectedType)
+		}
+	}
+}
+
+func TestDynamicResourceWatchEnds(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"type":"ADDED","object":{"metadata":{"name":"snap1"}}}`+"\n")
+	}))
+	defer ts.Close()
+
+	c := &Client{Url: ts.URL, Namespace: "ocopea"}
+	events := make(chan UnstructuredWatchEvent, 2)
+	closeHandle, err := c.Resource(snapshotsResource, true).Watch(nil, "", events)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	received := 0
+	timeout := time.After(5 * time.Second)
+	for ended := false; !ended; {
+		select {
+		case _, ok := <-events:
+			if ok {
+				received++
+			} else {
+				ended = true
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for the events channel to be closed when the watch ended")
+		}
+	}
+	if received != 1 {
+		t.Errorf("expected a single watch event, got %d", received)
+	}
+
+	// Closing more than once must not block
+	closeHandle()
+	closeHandle()
+}
@@ -0,0 +1,57 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"ocopea/kubernetes/client/protobuf"
+	"ocopea/kubernetes/client/unversioned"
+)
+
+// StatusError is returned when the api server responds with an unexpected http status
+type StatusError struct {
+	Method     string
+	Resource   string
+	StatusCode int
+	Status     string
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("http error on %s %s - %s - %s", e.Method, e.Resource, e.Status, e.Body)
+}
+
+// Builds a StatusError out of a response, consuming the response body. Protobuf encoded statuses are converted to json
+func newStatusError(method string, resource string, resp *http.Response) *StatusError {
+	contents, _ := ioutil.ReadAll(resp.Body)
+	if isProtobuf(resp) {
+		status := &unversioned.Status{}
+		if _, err := protobuf.Unmarshal(contents, status); err == nil {
+			contents, _ = json.Marshal(status)
+		}
+	}
+	return &StatusError{
+		Method:     method,
+		Resource:   resource,
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Body:       string(contents),
+	}
+}
+
+func hasStatusCode(err error, statusCode int) bool {
+	statusError, ok := err.(*StatusError)
+	return ok && statusError.StatusCode == statusCode
+}
+
+// Returns true in case the error was returned because the requested resource does not exist
+func IsNotFound(err error) bool {
+	return hasStatusCode(err, http.StatusNotFound)
+}
+
+// Returns true in case the error was returned because of an already existing resource or a stale resourceVersion
+func IsConflict(err error) bool {
+	return hasStatusCode(err, http.StatusConflict)
+}
@@ -0,0 +1,249 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"ocopea/kubernetes/client/types"
+	"ocopea/kubernetes/client/unversioned"
+	"ocopea/kubernetes/client/v1"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EventQuery selects events, fields left empty are not used for filtering
+type EventQuery struct {
+	// Namespace the events are listed in, defaults to the namespace of the client
+	Namespace string
+
+	InvolvedObjectKind      string
+	InvolvedObjectName      string
+	InvolvedObjectNamespace string
+	InvolvedObjectUID       types.UID
+	Reason                  string
+	Type                    string
+
+	// Only events that last occurred at or after this time are returned
+	Since time.Time
+}
+
+// Returns the field selector matching the query, e.g. involvedObject.kind=Pod,involvedObject.name=orcs-x7k2p
+func (q EventQuery) fieldSelector() string {
+	var fields []string
+	add := func(field string, value string) {
+		if value != "" {
+			fields = append(fields, field+"="+value)
+		}
+	}
+	add("involvedObject.kind", q.InvolvedObjectKind)
+	add("involvedObject.name", q.InvolvedObjectName)
+	add("involvedObject.namespace", q.InvolvedObjectNamespace)
+	add("involvedObject.uid", string(q.InvolvedObjectUID))
+	add("reason", q.Reason)
+	add("type", q.Type)
+	return strings.Join(fields, ",")
+}
+
+// Returns the events collection of the query namespace, e.g. namespaces/ocopea/events, and the query string
+// selecting the events
+func (q EventQuery) resource(clientNamespace string) (string, string) {
+	namespace := q.Namespace
+	if namespace == "" {
+		namespace = clientNamespace
+	}
+	selector := ""
+	if fields := q.fieldSelector(); fields != "" {
+		selector = "?fieldSelector=" + url.QueryEscape(fields)
+	}
+	return "namespaces/" + namespace + "/events", selector
+}
+
+// Returns true in case the event matches the query
+func (q EventQuery) matches(event *v1.Event) bool {
+	return (q.InvolvedObjectKind == "" || q.InvolvedObjectKind == event.InvolvedObject.Kind) &&
+		(q.InvolvedObjectName == "" || q.InvolvedObjectName == event.InvolvedObject.Name) &&
+		(q.InvolvedObjectNamespace == "" || q.InvolvedObjectNamespace == event.InvolvedObject.Namespace) &&
+		(q.InvolvedObjectUID == "" || q.InvolvedObjectUID == event.InvolvedObject.UID) &&
+		(q.Reason == "" || q.Reason == event.Reason) &&
+		(q.Type == "" || q.Type == event.Type) &&
+		(q.Since.IsZero() || !eventTime(event).Before(q.Since))
+}
+
+// Returns the time the event last occurred at
+func eventTime(event *v1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	return event.FirstTimestamp.Time
+}
+
+// Sorts events by the time they last occurred at, oldest first
+func sortEvents(events []*v1.Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventTime(events[i]).Before(eventTime(events[j]))
+	})
+}
+
+// Lists the events matching the query, sorted by the time they last occurred at
+func (c *Client) ListEvents(query EventQuery) ([]*v1.Event, error) {
+	collection, selector := query.resource(c.Namespace)
+	respEventList := &v1.EventList{}
+	if err := c.list("/api/v1/"+collection+selector, respEventList, &respEventList.TypeMeta); err != nil {
+		if _, ok := err.(*StatusError); ok {
+			return nil, err
+		}
+		return nil, fmt.Errorf("Failed listing k8s events - %s", err.Error())
+	}
+
+	// The since time can't be expressed as a field selector, older api servers also ignore some of the fields
+	eventList := make([]*v1.Event, 0)
+	for i := range respEventList.Items {
+		if query.matches(&respEventList.Items[i]) {
+			eventList = append(eventList, &respEventList.Items[i])
+		}
+	}
+	sortEvents(eventList)
+	return eventList, nil
+}
+
+// EventWatchEvent is a single change to an event, Status describes the failure of ERROR events instead of the event
+type EventWatchEvent struct {
+	Type   WatchEventType
+	Object *v1.Event
+	Status *unversioned.Status
+}
+
+// Watches changes to the events matching the query and sends them to the consumer channel until the close handle
+// is called or the server ends the watch. Use an empty resourceVersion to start watching from the current state
+func (c *Client) WatchEvents(
+	query EventQuery,
+	resourceVersion string,
+	consumerChannel chan EventWatchEvent) (CloseHandle, error) {
+
+	collection, selector := query.resource(c.Namespace)
+	path := "/api/v1/" + collection + watchQuery(selector, resourceVersion)
+	return c.watchCollection(path, "events", func(event *rawWatchEvent, closeChannel chan bool) bool {
+		k8sEvent := &v1.Event{}
+		status, err := event.decode(k8sEvent, &k8sEvent.TypeMeta)
+		if err != nil {
+			log.Printf("Failed decoding event %s event - %s", event.Type, err.Error())
+			return false
+		}
+		if status != nil {
+			k8sEvent = nil
+		} else if !query.matches(k8sEvent) {
+			return true
+		}
+		select {
+		case consumerChannel <- EventWatchEvent{Type: event.Type, Object: k8sEvent, Status: status}:
+			return true
+		case _ = <-closeChannel:
+			return false
+		}
+	})
+}
+
+// Creates the event in the namespace of the client
+func (c *Client) CreateEvent(event *v1.Event) (*v1.Event, error) {
+	respEvent := &v1.Event{}
+	err := c.createEntity("events", event.Name, event, respEvent, false)
+	return respEvent, err
+}
+
+func (c *Client) UpdateEvent(event *v1.Event) (*v1.Event, error) {
+	respEvent := &v1.Event{}
+	err := c.updateEntity("events", event.Name, event, respEvent)
+	return respEvent, err
+}
+
+// EventSink is where the event recorder writes events to, implemented by the clients
+type EventSink interface {
+	CreateEvent(event *v1.Event) (*v1.Event, error)
+	UpdateEvent(event *v1.Event) (*v1.Event, error)
+}
+
+// Maximal number of recorded events kept for aggregating repeated events
+const maxAggregatedEvents = 4096
+
+// EventRecorder records events on behalf of a component, e.g. k8spsb.
+// Repeating an event that was already recorded increments its count instead of creating another event
+type EventRecorder struct {
+	sink     EventSink
+	source   v1.EventSource
+	lock     sync.Mutex
+	recorded map[string]*v1.Event
+}
+
+func NewEventRecorder(sink EventSink, component string) *EventRecorder {
+	host, _ := os.Hostname()
+	return &EventRecorder{
+		sink:     sink,
+		source:   v1.EventSource{Component: component, Host: host},
+		recorded: make(map[string]*v1.Event),
+	}
+}
+
+// Returns a reference to an object, to be used as the object events are recorded about
+func ObjectReferenceTo(kind string, meta *v1.ObjectMeta) *v1.ObjectReference {
+	return &v1.ObjectReference{
+		Kind:            kind,
+		APIVersion:      "v1",
+		Namespace:       meta.Namespace,
+		Name:            meta.Name,
+		UID:             meta.UID,
+		ResourceVersion: meta.ResourceVersion,
+	}
+}
+
+// Records an event about the object, eventType is either v1.EventTypeNormal or v1.EventTypeWarning
+func (r *EventRecorder) Event(object *v1.ObjectReference, eventType string, reason string, message string) (*v1.Event, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	key := strings.Join([]string{
+		object.Kind, object.Namespace, object.Name, string(object.UID), eventType, reason, message}, "/")
+	now := unversioned.Now()
+	if previous, found := r.recorded[key]; found {
+		event := *previous
+		event.Count++
+		event.LastTimestamp = now
+		updated, err := r.sink.UpdateEvent(&event)
+		if err == nil {
+			r.recorded[key] = updated
+			return updated, nil
+		}
+		// The event may have expired on the server, recording it again
+		log.Printf("Failed updating event %s, creating a new one - %s\n", event.Name, err.Error())
+	}
+
+	event := &v1.Event{
+		ObjectMeta: v1.ObjectMeta{
+			Name: fmt.Sprintf("%s.%x", object.Name, time.Now().UnixNano()),
+		},
+		InvolvedObject: *object,
+		Reason:         reason,
+		Message:        message,
+		Source:         r.source,
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+		Type:           eventType,
+	}
+	created, err := r.sink.CreateEvent(event)
+	if err != nil {
+		return nil, fmt.Errorf("Failed recording event %s for %s %s - %s", reason, object.Kind, object.Name, err.Error())
+	}
+	if len(r.recorded) >= maxAggregatedEvents {
+		r.recorded = make(map[string]*v1.Event)
+	}
+	r.recorded[key] = created
+	return created, nil
+}
+
+func (r *EventRecorder) Eventf(object *v1.ObjectReference, eventType string, reason string, messageFmt string, args ...interface{}) (*v1.Event, error) {
+	return r.Event(object, eventType, reason, fmt.Sprintf(messageFmt, args...))
+}
@@ -0,0 +1,46 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"errors"
+	"ocopea/kubernetes/client/v1"
+	"testing"
+)
+
+func TestEventQueryFieldSelector(t *testing.T) {
+	query := EventQuery{InvolvedObjectKind: "Pod", InvolvedObjectName: "orcs-x7k2p", Type: v1.EventTypeWarning}
+	expected := "involvedObject.kind=Pod,involvedObject.name=orcs-x7k2p,type=Warning"
+	if selector := query.fieldSelector(); selector != expected {
+		t.Errorf("expected %s, got %s", expected, selector)
+	}
+	if selector := (EventQuery{}).fieldSelector(); selector != "" {
+		t.Errorf("expected empty selector, got %s", selector)
+	}
+}
+
+func TestEventRecorderRecreatesExpiredEvents(t *testing.T) {
+	f := NewFakeClient("ocopea")
+	recorder := NewEventRecorder(f, "deployer")
+	ref := &v1.ObjectReference{Kind: "Service", Namespace: "ocopea", Name: "orcs"}
+
+	first, err := recorder.Event(ref, v1.EventTypeNormal, "ServiceDeployed", "deployed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.InjectError("update", "events", errors.New("gone"))
+	second, err := recorder.Event(ref, v1.EventTypeNormal, "ServiceDeployed", "deployed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Name == second.Name || second.Count != 1 {
+		t.Errorf("expected a new event once updating failed, got %+v", second)
+	}
+	f.ClearErrors()
+	third, err := recorder.Event(ref, v1.EventTypeNormal, "ServiceDeployed", "deployed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if third.Name != second.Name || third.Count != 2 {
+		t.Errorf("expected the new event to be aggregated, got %+v", third)
+	}
+}
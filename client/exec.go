@@ -0,0 +1,178 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"ocopea/kubernetes/client/unversioned"
+	"ocopea/kubernetes/client/v1"
+	"strconv"
+	"strings"
+)
+
+// Sub protocols of the exec websocket, v5 adds closing the stdin stream which v4 lacks
+const (
+	execProtocolV4 = "v4.channel.k8s.io"
+	execProtocolV5 = "v5.channel.k8s.io"
+)
+
+// Every exec message starts with the channel it belongs to
+const (
+	execStdinChannel  = 0
+	execStdoutChannel = 1
+	execStderrChannel = 2
+	execErrorChannel  = 3
+	execCloseChannel  = 255
+)
+
+// Size of the stdin chunks sent to the command
+const execStdinChunkSize = 32 * 1024
+
+// ExecError is returned when a command executed in a pod fails
+type ExecError struct {
+	PodName string
+	Command []string
+
+	// Exit code of the command, -1 when the command did not run (e.g. not found in the container)
+	ExitCode int
+	Message  string
+}
+
+func (e *ExecError) Error() string {
+	return fmt.Sprintf("command %s in pod %s failed with exit code %d - %s",
+		strings.Join(e.Command, " "), e.PodName, e.ExitCode, e.Message)
+}
+
+// Executes the command of the options in the container of the pod, streaming stdin to the command and its output
+// to stdout and stderr. Streams that are nil are not attached. Returns an ExecError in case the command fails.
+// Api servers that only speak v4.channel.k8s.io can't signal the end of stdin, so commands reading stdin should
+// stop by themselves (e.g. tar stops at the end of the archive)
+func (c *Client) Exec(podName string, options *v1.PodExecOptions, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	query := url.Values{}
+	for _, arg := range options.Command {
+		query.Add("command", arg)
+	}
+	if options.Container != "" {
+		query.Set("container", options.Container)
+	}
+	query.Set("stdin", strconv.FormatBool(stdin != nil))
+	query.Set("stdout", strconv.FormatBool(stdout != nil))
+	query.Set("stderr", strconv.FormatBool(stderr != nil))
+	query.Set("tty", strconv.FormatBool(options.TTY))
+
+	path := "/api/v1/namespaces/" + c.Namespace + "/pods/" + podName + "/exec"
+	req, err := http.NewRequest("GET", c.Url+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("Failed exec on pod %s - %s", podName, err.Error())
+	}
+	if err = c.authenticate(req); err != nil {
+		return err
+	}
+	ws, protocol, err := c.dialWebSocket(req, []string{execProtocolV5, execProtocolV4})
+	if err != nil {
+		return fmt.Errorf("Failed exec on pod %s - %s", podName, err.Error())
+	}
+	defer ws.Close()
+
+	stdinDone := make(chan error, 1)
+	if stdin != nil {
+		go func() {
+			err := streamExecStdin(ws, stdin, protocol == execProtocolV5)
+			stdinDone <- err
+			if err != nil {
+				// Aborting the command, the read loop below fails on the closed connection
+				ws.conn.Close()
+			}
+		}()
+	}
+
+	var status []byte
+	for {
+		_, message, err := ws.readMessage()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			select {
+			case stdinErr := <-stdinDone:
+				if stdinErr != nil {
+					return fmt.Errorf("Failed reading stdin of exec on pod %s - %s", podName, stdinErr.Error())
+				}
+			default:
+			}
+			return fmt.Errorf("Failed reading exec stream of pod %s - %s", podName, err.Error())
+		}
+		if len(message) == 0 {
+			continue
+		}
+
+		switch message[0] {
+		case execStdoutChannel:
+			if stdout != nil {
+				if _, err = stdout.Write(message[1:]); err != nil {
+					return err
+				}
+			}
+		case execStderrChannel:
+			if stderr != nil {
+				if _, err = stderr.Write(message[1:]); err != nil {
+					return err
+				}
+			}
+		case execErrorChannel:
+			status = append(status, message[1:]...)
+		}
+	}
+	return execStatusError(podName, options.Command, status)
+}
+
+// Sends stdin to the command in chunks, returns errors reading stdin. Errors writing are ignored since those
+// happen when the command exits without consuming all of its input, the exit status is reported anyway
+func streamExecStdin(ws *wsConn, stdin io.Reader, canClose bool) error {
+	buf := make([]byte, execStdinChunkSize+1)
+	buf[0] = execStdinChannel
+	for {
+		n, err := stdin.Read(buf[1:])
+		if n > 0 {
+			if ws.writeFrame(wsOpBinary, buf[:n+1]) != nil {
+				return nil
+			}
+		}
+		if err == io.EOF {
+			if canClose {
+				ws.writeFrame(wsOpBinary, []byte{execCloseChannel, execStdinChannel})
+			}
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// Parses the status the api server reports on the error channel once the command exits
+func execStatusError(podName string, command []string, b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	status := unversioned.Status{}
+	if err := json.Unmarshal(b, &status); err != nil {
+		return &ExecError{PodName: podName, Command: command, ExitCode: -1, Message: string(b)}
+	}
+	if status.Status == unversioned.StatusSuccess {
+		return nil
+	}
+
+	exitErr := &ExecError{PodName: podName, Command: command, ExitCode: -1, Message: status.Message}
+	if status.Reason == "NonZeroExitCode" && status.Details != nil {
+		for _, cause := range status.Details.Causes {
+			if cause.Type == "ExitCode" {
+				if exitCode, err := strconv.Atoi(cause.Message); err == nil {
+					exitErr.ExitCode = exitCode
+				}
+			}
+		}
+	}
+	return exitErr
+}
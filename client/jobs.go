@@ -0,0 +1,215 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	batchv1 "ocopea/kubernetes/client/batch/v1"
+	"ocopea/kubernetes/client/v1"
+	"sort"
+	"strings"
+	"time"
+)
+
+var JobResource = GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
+var CronJobResource = GroupVersionResource{Group: "batch", Version: "v1", Resource: "cronjobs"}
+
+// Label the job controller sets on the pods of a job
+const JobNameLabel = "job-name"
+
+// Creates an object of a namespaced group resource in the namespace of the client.
+// In force mode an already existing object is read into responsePtr instead
+func (c *Client) createGroupEntity(resource GroupVersionResource, name string, obj interface{}, responsePtr interface{}, force bool) error {
+	r := c.Resource(resource, true)
+	body, err := c.structToReader(obj)
+	if err != nil {
+		return fmt.Errorf("Failed formatting %s %s to json - %s", resource.Resource, name, err.Error())
+	}
+	err = r.do("POST", r.collectionPath(), body, "application/json", responsePtr, http.StatusCreated)
+	if err != nil && IsConflict(err) && force {
+		log.Printf("conflict creating %s/%s, force mode, getting info only", resource.Resource, name)
+		return r.do("GET", r.objectPath(name), nil, "application/json", responsePtr, http.StatusOK)
+	}
+	return err
+}
+
+// Deletes an object of a namespaced group resource along with its dependents
+func (c *Client) deleteGroupEntity(resource GroupVersionResource, name string) error {
+	r := c.Resource(resource, true)
+	body, err := c.structToReader(NewDeleteOptions(-1, v1.DeletePropagationBackground))
+	if err != nil {
+		return err
+	}
+	return r.do("DELETE", r.objectPath(name), body, "application/json", nil, http.StatusOK, http.StatusAccepted)
+}
+
+func (c *Client) CreateJob(job *batchv1.Job, force bool) (*batchv1.Job, error) {
+	respJob := &batchv1.Job{}
+	err := c.createGroupEntity(JobResource, job.Name, job, respJob, force)
+	if err != nil {
+		return respJob, fmt.Errorf("Failed creating job %s - %s", job.Name, err.Error())
+	}
+	return respJob, nil
+}
+
+func (c *Client) GetJob(jobName string) (*batchv1.Job, error) {
+	jobs := c.Resource(JobResource, true)
+	job := &batchv1.Job{}
+	err := jobs.do("GET", jobs.objectPath(jobName), nil, "application/json", job, http.StatusOK)
+	return job, err
+}
+
+func (c *Client) ListJobs(labelFilters map[string]string) ([]*batchv1.Job, error) {
+	jobs := c.Resource(JobResource, true)
+	respJobList := &batchv1.JobList{}
+	err := jobs.do("GET", jobs.collectionPath()+buildLabelsQueryString(labelFilters), nil, "application/json", respJobList, http.StatusOK)
+	if err != nil {
+		return nil, fmt.Errorf("Failed listing k8s jobs - %s", err.Error())
+	}
+	jobList := make([]*batchv1.Job, 0, len(respJobList.Items))
+	for i := range respJobList.Items {
+		jobList = append(jobList, &respJobList.Items[i])
+	}
+	return jobList, nil
+}
+
+// Deletes the job along with its pods. Pods are also deleted by label, since older api servers leave them behind
+func (c *Client) DeleteJob(jobName string) error {
+	if err := c.deleteGroupEntity(JobResource, jobName); err != nil {
+		return err
+	}
+	return c.DeleteCollection("pods", map[string]string{JobNameLabel: jobName}, nil)
+}
+
+// Returns the pods the job created, oldest first
+func (c *Client) ListJobPods(jobName string) ([]*v1.Pod, error) {
+	pods, err := c.ListPodsInfo(map[string]string{JobNameLabel: jobName})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(pods, func(i, j int) bool {
+		return pods[i].CreationTimestamp.Time.Before(pods[j].CreationTimestamp.Time)
+	})
+	return pods, nil
+}
+
+// Returns the logs of the pods of the job by pod name, a job has more than a single pod when it was retried
+func (c *Client) GetJobLogs(jobName string) (map[string][]byte, error) {
+	pods, err := c.ListJobPods(jobName)
+	if err != nil {
+		return nil, err
+	}
+	logs := make(map[string][]byte, len(pods))
+	for _, pod := range pods {
+		podLogs, err := c.GetPodLogs(pod.Name)
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting logs of job %s - %s", jobName, err.Error())
+		}
+		logs[pod.Name] = podLogs
+	}
+	return logs, nil
+}
+
+// Waits for the job to complete, returning an error in case the job failed or did not finish within the timeout
+func (c *Client) WaitForJob(jobName string, timeout time.Duration) (*batchv1.Job, error) {
+	obj, err := WaitFor(context.Background(), c.JobGetter(jobName), JobFinished, WaitOptions{
+		Description: "job " + jobName + " to complete",
+		Timeout:     timeout,
+		Interval:    3 * time.Second,
+		Watch:       c.WatchTrigger(coreResource("pods"), true, map[string]string{JobNameLabel: jobName}),
+	})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*batchv1.Job), err
+}
+
+// Returns the condition of the job with the given type in case it is true
+func jobCondition(job *batchv1.Job, conditionType batchv1.JobConditionType) *batchv1.JobCondition {
+	for i := range job.Status.Conditions {
+		condition := &job.Status.Conditions[i]
+		if condition.Type == conditionType && condition.Status == v1.ConditionTrue {
+			return condition
+		}
+	}
+	return nil
+}
+
+func (c *Client) CreateCronJob(cronJob *batchv1.CronJob, force bool) (*batchv1.CronJob, error) {
+	respCronJob := &batchv1.CronJob{}
+	err := c.createGroupEntity(CronJobResource, cronJob.Name, cronJob, respCronJob, force)
+	if err != nil {
+		return respCronJob, fmt.Errorf("Failed creating cron job %s - %s", cronJob.Name, err.Error())
+	}
+	return respCronJob, nil
+}
+
+func (c *Client) GetCronJob(cronJobName string) (*batchv1.CronJob, error) {
+	cronJobs := c.Resource(CronJobResource, true)
+	cronJob := &batchv1.CronJob{}
+	err := cronJobs.do("GET", cronJobs.objectPath(cronJobName), nil, "application/json", cronJob, http.StatusOK)
+	return cronJob, err
+}
+
+func (c *Client) ListCronJobs(labelFilters map[string]string) ([]*batchv1.CronJob, error) {
+	cronJobs := c.Resource(CronJobResource, true)
+	respCronJobList := &batchv1.CronJobList{}
+	err := cronJobs.do("GET", cronJobs.collectionPath()+buildLabelsQueryString(labelFilters), nil, "application/json", respCronJobList, http.StatusOK)
+	if err != nil {
+		return nil, fmt.Errorf("Failed listing k8s cron jobs - %s", err.Error())
+	}
+	cronJobList := make([]*batchv1.CronJob, 0, len(respCronJobList.Items))
+	for i := range respCronJobList.Items {
+		cronJobList = append(cronJobList, &respCronJobList.Items[i])
+	}
+	return cronJobList, nil
+}
+
+// Deletes the cron job along with the jobs it created
+func (c *Client) DeleteCronJob(cronJobName string) error {
+	jobs, err := c.ListCronJobHistory(cronJobName)
+	if err != nil {
+		return err
+	}
+	if err = c.deleteGroupEntity(CronJobResource, cronJobName); err != nil {
+		return fmt.Errorf("Failed deleting cron job %s - %s", cronJobName, err.Error())
+	}
+	for _, job := range jobs {
+		if err = c.DeleteJob(job.Name); err != nil && !IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Suspends or resumes the scheduling of the cron job, jobs already running are not affected
+func (c *Client) SuspendCronJob(cronJobName string, suspend bool) (*batchv1.CronJob, error) {
+	cronJobs := c.Resource(CronJobResource, true)
+	patch := strings.NewReader(fmt.Sprintf(`{"spec":{"suspend":%t}}`, suspend))
+	cronJob := &batchv1.CronJob{}
+	err := cronJobs.do("PATCH", cronJobs.objectPath(cronJobName), patch, string(MergePatchType), cronJob, http.StatusOK)
+	return cronJob, err
+}
+
+// Returns the jobs the cron job created that were not cleaned up yet, oldest first
+func (c *Client) ListCronJobHistory(cronJobName string) ([]*batchv1.Job, error) {
+	jobs, err := c.ListJobs(nil)
+	if err != nil {
+		return nil, err
+	}
+	history := make([]*batchv1.Job, 0)
+	for _, job := range jobs {
+		for _, owner := range job.OwnerReferences {
+			if owner.Kind == "CronJob" && owner.Name == cronJobName {
+				history = append(history, job)
+				break
+			}
+		}
+	}
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].CreationTimestamp.Time.Before(history[j].CreationTimestamp.Time)
+	})
+	return history, nil
+}
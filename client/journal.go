@@ -0,0 +1,366 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"ocopea/kubernetes/client/v1"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+type JournalOperation string
+
+const (
+	JournalCreate JournalOperation = "create"
+	JournalUpdate JournalOperation = "update"
+	JournalPatch  JournalOperation = "patch"
+	JournalDelete JournalOperation = "delete"
+)
+
+// JournalEntry records a mutating call made by the client
+type JournalEntry struct {
+	Time      time.Time        `json:"time"`
+	Operation JournalOperation `json:"operation"`
+
+	// Path of the object in the api server, e.g. /api/v1/namespaces/ocopea/services/orcs
+	Path string `json:"path"`
+
+	// State of the object before the call, nil for created objects
+	Previous Unstructured `json:"previous,omitempty"`
+}
+
+// JournalSink keeps the entries of a journal, see MemoryJournal and FileJournal
+type JournalSink interface {
+	Record(entry *JournalEntry) error
+
+	// Returns the entries recorded so far in the order they were recorded
+	Entries() ([]*JournalEntry, error)
+}
+
+// MemoryJournal keeps the journal entries in memory
+type MemoryJournal struct {
+	lock    sync.Mutex
+	entries []*JournalEntry
+}
+
+func NewMemoryJournal() *MemoryJournal {
+	return &MemoryJournal{}
+}
+
+func (j *MemoryJournal) Record(entry *JournalEntry) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	j.entries = append(j.entries, entry)
+	return nil
+}
+
+func (j *MemoryJournal) Entries() ([]*JournalEntry, error) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	return append([]*JournalEntry{}, j.entries...), nil
+}
+
+// FileJournal appends the journal entries to a file, one json entry per line, so the changes of a process
+// that crashed can still be undone
+type FileJournal struct {
+	Path string
+	lock sync.Mutex
+}
+
+func NewFileJournal(path string) *FileJournal {
+	return &FileJournal{Path: path}
+}
+
+func (j *FileJournal) Record(entry *JournalEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("Failed formatting journal entry of %s - %s", entry.Path, err.Error())
+	}
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	f, err := os.OpenFile(j.Path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("Failed opening journal %s - %s", j.Path, err.Error())
+	}
+	defer f.Close()
+	if _, err = f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("Failed writing journal %s - %s", j.Path, err.Error())
+	}
+	return nil
+}
+
+func (j *FileJournal) Entries() ([]*JournalEntry, error) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	f, err := os.Open(j.Path)
+	if os.IsNotExist(err) {
+		return []*JournalEntry{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("Failed opening journal %s - %s", j.Path, err.Error())
+	}
+	defer f.Close()
+
+	entries := make([]*JournalEntry, 0)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		entry := &JournalEntry{}
+		if err = decodeUnstructured(bytes.NewReader(scanner.Bytes()), entry); err != nil {
+			return nil, fmt.Errorf("Failed parsing journal %s entry %d - %s", j.Path, len(entries)+1, err.Error())
+		}
+		entries = append(entries, entry)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Failed reading journal %s - %s", j.Path, err.Error())
+	}
+	return entries, nil
+}
+
+// The object or collection a request is sent to, e.g. /api/v1/namespaces/ocopea/services/orcs
+type journalTarget struct {
+	collectionPath string
+	name           string
+	subresource    string
+	resource       string
+}
+
+func (t *journalTarget) objectPath(name string) string {
+	return t.collectionPath + "/" + name
+}
+
+// Returns the target of a mutating request that should be journaled, nil otherwise. Subresources (e.g. proxy,
+// log or status) are not journaled, nor are events since those record changes rather than making them
+func journalTargetOf(method string, path string) *journalTarget {
+	if method != "POST" && method != "PUT" && method != "PATCH" && method != "DELETE" {
+		return nil
+	}
+	path = strings.SplitN(path, "?", 2)[0]
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	var prefix, rest []string
+	switch {
+	case len(segments) >= 3 && segments[0] == "api":
+		prefix, rest = segments[:2], segments[2:]
+	case len(segments) >= 4 && segments[0] == "apis":
+		prefix, rest = segments[:3], segments[3:]
+	default:
+		return nil
+	}
+	if rest[0] == "namespaces" && len(rest) >= 3 && rest[2] != "status" && rest[2] != "finalize" {
+		prefix, rest = append(prefix, rest[:2]...), rest[2:]
+	}
+
+	t := &journalTarget{collectionPath: "/" + strings.Join(append(prefix, rest[0]), "/"), resource: rest[0]}
+	if len(rest) > 1 {
+		t.name = rest[1]
+	}
+	if len(rest) > 2 {
+		t.subresource = strings.Join(rest[2:], "/")
+	}
+	if t.subresource != "" || t.resource == "events" || (t.name == "" && method != "POST" && method != "DELETE") {
+		return nil
+	}
+	return t
+}
+
+// Sends a mutating request, recording it in the journal of the client along with the state of the objects
+// it changes. Failing to record is logged, the request was already made by then
+func (c *Client) doJournaledHttp(
+	target *journalTarget,
+	method string,
+	path string,
+	r io.Reader,
+	contentType string) (*http.Response, error) {
+
+	// Reading the objects before they change, a delete without a name deletes every object listed by its query
+	var previous []Unstructured
+	var err error
+	if target.name != "" {
+		previous, err = c.journaledObjects(target.objectPath(target.name))
+	} else if method == "DELETE" {
+		previous, err = c.journaledObjects(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed journaling %s on %s - %s", method, path, err.Error())
+	}
+
+	resp, err := c.sendHttp(method, path, r, contentType)
+	if err != nil || resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp, err
+	}
+
+	entries := make([]*JournalEntry, 0, len(previous))
+	if method == "POST" {
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		created := Unstructured{}
+		if err != nil || decodeUnstructured(bytes.NewReader(body), &created) != nil || created.GetName() == "" {
+			log.Printf("Failed journaling %s on %s, the created object could not be read\n", method, path)
+			return resp, nil
+		}
+		entries = append(entries, &JournalEntry{Operation: JournalCreate, Path: target.objectPath(created.GetName())})
+	} else {
+		operation := map[string]JournalOperation{"PUT": JournalUpdate, "PATCH": JournalPatch, "DELETE": JournalDelete}[method]
+		for _, obj := range previous {
+			entries = append(entries, &JournalEntry{Operation: operation, Path: target.objectPath(obj.GetName()), Previous: obj})
+		}
+	}
+	for _, entry := range entries {
+		entry.Time = time.Now()
+		if err = c.Journal.Record(entry); err != nil {
+			log.Printf("Failed journaling %s on %s - %s\n", entry.Operation, entry.Path, err.Error())
+		}
+	}
+	return resp, nil
+}
+
+// Returns the current state of the object, or of the objects listed, found in the path. Objects that don't
+// exist are not returned
+func (c *Client) journaledObjects(path string) ([]Unstructured, error) {
+	resp, err := c.sendHttp("GET", path, nil, "application/json")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError("GET", path, resp)
+	}
+
+	obj := Unstructured{}
+	if err = decodeUnstructured(resp.Body, &obj); err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(obj.GetKind(), "List") {
+		return []Unstructured{obj}, nil
+	}
+
+	// Items of lists usually lack their kind, which is needed to create them again
+	items, _ := NestedField(obj, "items")
+	itemList, _ := items.([]interface{})
+	objects := make([]Unstructured, 0, len(itemList))
+	for _, item := range itemList {
+		if itemMap, ok := item.(map[string]interface{}); ok {
+			u := Unstructured(itemMap)
+			if u.GetKind() == "" {
+				u["kind"] = strings.TrimSuffix(obj.GetKind(), "List")
+				u["apiVersion"] = obj.GetAPIVersion()
+			}
+			objects = append(objects, u)
+		}
+	}
+	return objects, nil
+}
+
+// Returns the previous state of a journaled object ready to be sent back to the api server
+func restorableObject(previous Unstructured) Unstructured {
+	obj := Unstructured{}
+	for key, value := range previous {
+		if key != "status" {
+			obj[key] = value
+		}
+	}
+	if metadata, ok := previous["metadata"].(map[string]interface{}); ok {
+		metadataCopy := make(map[string]interface{}, len(metadata))
+		for key, value := range metadata {
+			metadataCopy[key] = value
+		}
+		for _, field := range append(serverSetMetadataFields, "deletionGracePeriodSeconds") {
+			delete(metadataCopy, field)
+		}
+		obj["metadata"] = metadataCopy
+	}
+	return obj
+}
+
+// Reverts the changes recorded in the journal in reverse order: created objects are deleted, deleted objects
+// are created again and updated objects are restored to their previous state. Reverting carries on past
+// failures, which are returned together. The changes made by undoing are not journaled
+func (c *Client) Undo(journal JournalSink) error {
+	entries, err := journal.Entries()
+	if err != nil {
+		return err
+	}
+	undoing := *c
+	undoing.Journal = nil
+
+	var failures []string
+	for i := len(entries) - 1; i >= 0; i-- {
+		if err = undoing.undoEntry(entries[i]); err != nil {
+			failures = append(failures, err.Error())
+		} else {
+			log.Printf("Undid %s of %s\n", entries[i].Operation, entries[i].Path)
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("Failed undoing %d of %d changes - %s", len(failures), len(entries), strings.Join(failures, ", "))
+	}
+	return nil
+}
+
+func (c *Client) undoEntry(entry *JournalEntry) error {
+	switch entry.Operation {
+	case JournalCreate:
+		// Deleting the dependents as well, e.g. the pods of replication controllers
+		body, err := c.structToReader(NewDeleteOptions(-1, v1.DeletePropagationBackground))
+		if err != nil {
+			return err
+		}
+		resp, err := c.sendHttp("DELETE", entry.Path, body, "application/json")
+		if err != nil {
+			return fmt.Errorf("Failed deleting %s - %s", entry.Path, err.Error())
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusNotFound {
+			return newStatusError("DELETE", entry.Path, resp)
+		}
+		return nil
+	case JournalDelete, JournalUpdate, JournalPatch:
+		if entry.Previous == nil {
+			return nil
+		}
+		return c.restoreObject(entry.Path, restorableObject(entry.Previous))
+	}
+	return fmt.Errorf("unknown journal operation %s on %s", entry.Operation, entry.Path)
+}
+
+// Restores the object found in path to its previous state, creating it in case it does not exist anymore
+func (c *Client) restoreObject(path string, obj Unstructured) error {
+	current, err := c.journaledObjects(path)
+	if err != nil {
+		return fmt.Errorf("Failed getting %s - %s", path, err.Error())
+	}
+	method, requestPath, expected := "PUT", path, http.StatusOK
+	if len(current) == 0 {
+		method, requestPath, expected = "POST", path[:strings.LastIndex(path, "/")], http.StatusCreated
+	} else {
+		SetNestedField(obj, current[0].GetResourceVersion(), "metadata", "resourceVersion")
+	}
+
+	body, err := c.structToReader(obj)
+	if err != nil {
+		return fmt.Errorf("Failed formatting %s to json - %s", path, err.Error())
+	}
+	resp, err := c.sendHttp(method, requestPath, body, "application/json")
+	if err != nil {
+		return fmt.Errorf("Failed restoring %s - %s", path, err.Error())
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != expected && resp.StatusCode != http.StatusOK {
+		return newStatusError(method, requestPath, resp)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJournalTargetOf(t *testing.T) {
+	journaled := map[string]string{
+		"POST /api/v1/namespaces":                                                 "/api/v1/namespaces",
+		"DELETE /api/v1/namespaces/ocopea":                                        "/api/v1/namespaces/ocopea",
+		"POST /api/v1/namespaces/ocopea/services":                                 "/api/v1/namespaces/ocopea/services",
+		"PATCH /api/v1/namespaces/ocopea/replicationcontrollers/orcs":             "/api/v1/namespaces/ocopea/replicationcontrollers/orcs",
+		"DELETE /api/v1/namespaces/ocopea/pods?labelSelector=app%3Dorcs":          "/api/v1/namespaces/ocopea/pods",
+		"PUT /apis/batch/v1/namespaces/ocopea/jobs/backup":                        "/apis/batch/v1/namespaces/ocopea/jobs/backup",
+		"DELETE /apis/apiextensions.k8s.io/v1/customresourcedefinitions/sites.io": "/apis/apiextensions.k8s.io/v1/customresourcedefinitions/sites.io",
+	}
+	for request, expected := range journaled {
+		parts := strings.SplitN(request, " ", 2)
+		target := journalTargetOf(parts[0], parts[1])
+		if target == nil {
+			t.Errorf("expected %s to be journaled", request)
+			continue
+		}
+		actual := target.collectionPath
+		if target.name != "" {
+			actual = target.objectPath(target.name)
+		}
+		if actual != expected {
+			t.Errorf("expected %s to target %s, got %s", request, expected, actual)
+		}
+	}
+
+	for _, request := range [][]string{
+		{"GET", "/api/v1/namespaces/ocopea/services/orcs"},
+		{"POST", "/api/v1/namespaces/ocopea/services/orcs:80/proxy/site-api/commands/add"},
+		{"POST", "/api/v1/namespaces/ocopea/events"},
+		{"PUT", "/api/v1/namespaces/ocopea/finalize"},
+		{"PUT", "/api/v1/namespaces/ocopea/pods"},
+	} {
+		if journalTargetOf(request[0], request[1]) != nil {
+			t.Errorf("expected %s %s not to be journaled", request[0], request[1])
+		}
+	}
+}
+
+// Fake api server recording the requests undoing the journal, the hub replication controller exists with 3 replicas
+func newUndoServer(requests *[]string, bodies map[string]Unstructured) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := r.Method + " " + r.URL.Path
+		*requests = append(*requests, request)
+		body := Unstructured{}
+		if decodeUnstructured(r.Body, &body) == nil {
+			bodies[request] = body
+		}
+		switch {
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/replicationcontrollers/hub"):
+			io.WriteString(w, `{"kind":"ReplicationController","metadata":{"name":"hub","resourceVersion":"7"},"spec":{"replicas":3}}`)
+		case r.Method == "GET":
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == "POST":
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(body)
+		default:
+			json.NewEncoder(w).Encode(body)
+		}
+	}))
+}
+
+func TestUndo(t *testing.T) {
+	dir, err := ioutil.TempDir("", "journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, journal := range []JournalSink{NewMemoryJournal(), NewFileJournal(filepath.Join(dir, "journal"))} {
+		var requests []string
+		bodies := make(map[string]Unstructured)
+		ts := newUndoServer(&requests, bodies)
+
+		for _, entry := range []*JournalEntry{
+			{Operation: JournalCreate, Path: "/api/v1/namespaces/ocopea/services/orcs"},
+			{Operation: JournalUpdate, Path: "/api/v1/namespaces/ocopea/replicationcontrollers/hub", Previous: Unstructured{
+				"kind":     "ReplicationController",
+				"metadata": map[string]interface{}{"name": "hub", "resourceVersion": "5", "uid": "hub-uid"},
+				"spec":     map[string]interface{}{"replicas": 1},
+				"status":   map[string]interface{}{"replicas": 1},
+			}},
+			{Operation: JournalDelete, Path: "/api/v1/namespaces/ocopea/services/legacy", Previous: Unstructured{
+				"kind":     "Service",
+				"metadata": map[string]interface{}{"name": "legacy", "resourceVersion": "3", "labels": map[string]interface{}{"app": "legacy"}},
+			}},
+		} {
+			if err := journal.Record(entry); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// Undoing is not journaled even when the client has a journal
+		c := &Client{Url: ts.URL, Namespace: "ocopea", Journal: journal}
+		if err := c.Undo(journal); err != nil {
+			t.Fatal(err)
+		}
+		ts.Close()
+
+		expected := []string{
+			"GET /api/v1/namespaces/ocopea/services/legacy",
+			"POST /api/v1/namespaces/ocopea/services",
+			"GET /api/v1/namespaces/ocopea/replicationcontrollers/hub",
+			"PUT /api/v1/namespaces/ocopea/replicationcontrollers/hub",
+			"DELETE /api/v1/namespaces/ocopea/services/orcs",
+		}
+		if !reflect.DeepEqual(requests, expected) {
+			t.Errorf("expected the changes to be undone in reverse order %v, got %v", expected, requests)
+		}
+
+		created := bodies["POST /api/v1/namespaces/ocopea/services"]
+		if created.GetName() != "legacy" || created.GetLabels()["app"] != "legacy" || created.GetResourceVersion() != "" {
+			t.Errorf("expected the deleted service to be created again without its resource version, got %v", created)
+		}
+		updated := bodies["PUT /api/v1/namespaces/ocopea/replicationcontrollers/hub"]
+		replicas, _ := NestedField(updated, "spec", "replicas")
+		if updated.GetResourceVersion() != "7" || fmt.Sprint(replicas) != "1" || updated["status"] != nil {
+			t.Errorf("expected the replication controller to be restored on top of its current version, got %v", updated)
+		}
+		if entries, _ := journal.Entries(); len(entries) != 3 {
+			t.Errorf("expected undoing not to be journaled, got %d entries", len(entries))
+		}
+	}
+}
@@ -0,0 +1,278 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	batchv1 "ocopea/kubernetes/client/batch/v1"
+	"ocopea/kubernetes/client/v1"
+	"ocopea/kubernetes/client/yaml"
+	"sort"
+	"strings"
+)
+
+// manifestKind describes how objects of a kind found in manifests are decoded and created
+type manifestKind struct {
+	resource   GroupVersionResource
+	namespaced bool
+
+	// Returns a pointer to a new typed object of the kind, nil for kinds decoded as unstructured objects
+	newObject func() interface{}
+
+	// Objects are created in ascending order, so the objects others depend on exist by the time those are created
+	order int
+}
+
+// Creation order of the kinds found in manifests, kinds that are not listed are created last
+const (
+	namespacesOrder = iota
+	policiesOrder
+	configOrder
+	storageOrder
+	definitionsOrder
+	workloadsOrder
+	servicesOrder
+	unknownKindsOrder
+)
+
+// Kinds of manifest objects by group and kind, e.g. "batch/Job". The core group is empty, e.g. "/Pod"
+var manifestKinds = map[string]manifestKind{
+	"/Namespace":      {coreResource("namespaces"), false, func() interface{} { return &v1.Namespace{} }, namespacesOrder},
+	"/ResourceQuota":  {coreResource("resourcequotas"), true, func() interface{} { return &v1.ResourceQuota{} }, policiesOrder},
+	"/LimitRange":     {coreResource("limitranges"), true, func() interface{} { return &v1.LimitRange{} }, policiesOrder},
+	"/ServiceAccount": {coreResource("serviceaccounts"), true, func() interface{} { return &v1.ServiceAccount{} }, configOrder},
+	"/Secret":         {coreResource("secrets"), true, func() interface{} { return &v1.Secret{} }, configOrder},
+	"/ConfigMap":      {coreResource("configmaps"), true, nil, configOrder},
+	"/PersistentVolume": {
+		coreResource("persistentvolumes"), false, func() interface{} { return &v1.PersistentVolume{} }, storageOrder},
+	"/PersistentVolumeClaim": {
+		coreResource("persistentvolumeclaims"), true, func() interface{} { return &v1.PersistentVolumeClaim{} }, storageOrder},
+	"apiextensions.k8s.io/CustomResourceDefinition": {CustomResourceDefinitionResource, false, nil, definitionsOrder},
+	"/Pod": {coreResource("pods"), true, func() interface{} { return &v1.Pod{} }, workloadsOrder},
+	"/ReplicationController": {
+		coreResource("replicationcontrollers"), true, func() interface{} { return &v1.ReplicationController{} }, workloadsOrder},
+	"batch/Job":     {JobResource, true, func() interface{} { return &batchv1.Job{} }, workloadsOrder},
+	"batch/CronJob": {CronJobResource, true, func() interface{} { return &batchv1.CronJob{} }, workloadsOrder},
+	"/Service":      {coreResource("services"), true, func() interface{} { return &v1.Service{} }, servicesOrder},
+	"/Endpoints":    {coreResource("endpoints"), true, func() interface{} { return &v1.Endpoints{} }, servicesOrder},
+}
+
+// Kinds of other groups that are created along with the core kinds of the same order
+var manifestKindOrders = map[string]int{
+	"PodSecurityPolicy":   policiesOrder,
+	"StorageClass":        storageOrder,
+	"VolumeSnapshotClass": storageOrder,
+	"VolumeSnapshot":      storageOrder,
+	"ClusterRole":         definitionsOrder,
+	"ClusterRoleBinding":  definitionsOrder,
+	"Role":                definitionsOrder,
+	"RoleBinding":         definitionsOrder,
+	"ReplicaSet":          workloadsOrder,
+	"Deployment":          workloadsOrder,
+	"StatefulSet":         workloadsOrder,
+	"DaemonSet":           workloadsOrder,
+	"Ingress":             servicesOrder,
+}
+
+// Kinds of other groups that are not namespaced
+var clusterScopedKinds = map[string]bool{
+	"Node":                true,
+	"PodSecurityPolicy":   true,
+	"StorageClass":        true,
+	"VolumeSnapshotClass": true,
+	"ClusterRole":         true,
+	"ClusterRoleBinding":  true,
+	"PriorityClass":       true,
+}
+
+// Splits an api version to its group and version, e.g. batch/v1 to batch and v1. The core group is empty
+func splitAPIVersion(apiVersion string) (string, string) {
+	if i := strings.Index(apiVersion, "/"); i >= 0 {
+		return apiVersion[:i], apiVersion[i+1:]
+	}
+	return "", apiVersion
+}
+
+// Returns the lower case plural resource name of a kind, e.g. NetworkPolicy to networkpolicies
+func pluralResourceName(kind string) string {
+	resource := strings.ToLower(kind)
+	switch {
+	case strings.HasSuffix(resource, "s"), strings.HasSuffix(resource, "x"), strings.HasSuffix(resource, "ch"):
+		return resource + "es"
+	case strings.HasSuffix(resource, "y") && !strings.HasSuffix(resource, "ay") && !strings.HasSuffix(resource, "ey"):
+		return resource[:len(resource)-1] + "ies"
+	}
+	return resource + "s"
+}
+
+// Returns how objects of the api version and kind are handled, kinds we don't know are guessed
+func manifestKindOf(apiVersion string, kind string) manifestKind {
+	group, version := splitAPIVersion(apiVersion)
+	if known, found := manifestKinds[group+"/"+kind]; found {
+		known.resource.Version = version
+		return known
+	}
+	order, found := manifestKindOrders[kind]
+	if !found {
+		order = unknownKindsOrder
+	}
+	return manifestKind{
+		resource:   GroupVersionResource{Group: group, Version: version, Resource: pluralResourceName(kind)},
+		namespaced: !clusterScopedKinds[kind],
+		order:      order,
+	}
+}
+
+// Converts a decoded yaml document to a value json can encode, yaml maps may have keys of any type
+func yamlToJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			converted[fmt.Sprint(key)] = yamlToJSONValue(item)
+		}
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, len(v))
+		for i, item := range v {
+			converted[i] = yamlToJSONValue(item)
+		}
+		return converted
+	}
+	return value
+}
+
+// Decodes the objects found in yaml or json manifests. Multiple yaml documents separated by --- and List
+// objects are supported. Objects of kinds the client has types for are decoded to the typed object
+// (e.g. *v1.Service or *batchv1.Job), others are decoded to Unstructured
+func DecodeManifests(reader io.Reader) ([]interface{}, error) {
+	unstructuredObjects, err := DecodeUnstructuredManifests(reader)
+	if err != nil {
+		return nil, err
+	}
+	objects := make([]interface{}, 0, len(unstructuredObjects))
+	for _, u := range unstructuredObjects {
+		kind := manifestKindOf(u.GetAPIVersion(), u.GetKind())
+		if kind.newObject == nil {
+			objects = append(objects, u)
+			continue
+		}
+		obj := kind.newObject()
+		if err = FromUnstructured(u, obj); err != nil {
+			return nil, fmt.Errorf("Failed decoding manifest %s %s - %s", u.GetKind(), u.GetName(), err.Error())
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+// Decodes the objects found in yaml or json manifests as unstructured objects, keeping fields the
+// typed objects of the client don't have. Items of List objects are returned instead of the list
+func DecodeUnstructuredManifests(reader io.Reader) ([]Unstructured, error) {
+	decoder := yaml.NewDecoder(reader)
+	objects := make([]Unstructured, 0)
+	for document := 1; ; document++ {
+		var value interface{}
+		err := decoder.Decode(&value)
+		if err == io.EOF {
+			return objects, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("Failed parsing manifest document %d - %s", document, err.Error())
+		}
+		if value == nil {
+			// Empty document, e.g. only comments
+			continue
+		}
+
+		b, err := json.Marshal(yamlToJSONValue(value))
+		if err != nil {
+			return nil, fmt.Errorf("Failed converting manifest document %d to json - %s", document, err.Error())
+		}
+		u := Unstructured{}
+		if err = decodeUnstructured(bytes.NewReader(b), &u); err != nil {
+			return nil, fmt.Errorf("Failed parsing manifest document %d, an object was expected - %s", document, err.Error())
+		}
+		if objects, err = appendManifestObject(objects, u); err != nil {
+			return nil, fmt.Errorf("Failed decoding manifest document %d - %s", document, err.Error())
+		}
+	}
+}
+
+// Appends the manifest object, or the items of List objects
+func appendManifestObject(objects []Unstructured, u Unstructured) ([]Unstructured, error) {
+	if u.GetKind() == "" || u.GetAPIVersion() == "" {
+		return nil, fmt.Errorf("object %s is missing its kind or apiVersion", u.GetName())
+	}
+	if !strings.HasSuffix(u.GetKind(), "List") {
+		return append(objects, u), nil
+	}
+
+	items, _ := NestedField(u, "items")
+	itemList, ok := items.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("items of %s are not a list", u.GetKind())
+	}
+	for _, item := range itemList {
+		itemMap, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("item of %s is not an object", u.GetKind())
+		}
+		var err error
+		if objects, err = appendManifestObject(objects, Unstructured(itemMap)); err != nil {
+			return nil, err
+		}
+	}
+	return objects, nil
+}
+
+// A manifest object ready for creation
+type manifestObject struct {
+	object Unstructured
+	kind   manifestKind
+}
+
+// Returns the manifest objects sorted in creation order, objects of the same order keep the order they were found in
+func sortManifestObjects(objects []Unstructured) []manifestObject {
+	sorted := make([]manifestObject, 0, len(objects))
+	for _, u := range objects {
+		sorted = append(sorted, manifestObject{object: u, kind: manifestKindOf(u.GetAPIVersion(), u.GetKind())})
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].kind.order < sorted[j].kind.order
+	})
+	return sorted
+}
+
+// Returns the dynamic resource the manifest object is created with.
+// Namespaced objects are created in the namespace of the object, defaulting to the namespace of the client
+func (c *Client) manifestResource(obj manifestObject) *DynamicResource {
+	r := c.Resource(obj.kind.resource, obj.kind.namespaced)
+	if namespace := obj.object.GetNamespace(); obj.kind.namespaced && namespace != "" {
+		r = r.InNamespace(namespace)
+	}
+	return r
+}
+
+// Applies the objects found in yaml or json manifests in dependency order - namespaces first, then policies,
+// secrets and configuration, storage, definitions, workloads and at last services.
+// Returns the results of the objects applied so far in application order
+func (c *Client) ApplyManifests(reader io.Reader) ([]*ApplyResult, error) {
+	objects, err := DecodeUnstructuredManifests(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*ApplyResult, 0, len(objects))
+	for _, obj := range sortManifestObjects(objects) {
+		result, err := c.Apply(obj.object)
+		if err != nil {
+			return results, err
+		}
+		log.Printf("%s %s %s\n", obj.object.GetKind(), obj.object.GetName(), result.Operation)
+		results = append(results, result)
+	}
+	return results, nil
+}
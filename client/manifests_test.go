@@ -0,0 +1,123 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	batchv1 "ocopea/kubernetes/client/batch/v1"
+	"ocopea/kubernetes/client/v1"
+	"strings"
+	"testing"
+)
+
+const testManifests = `
+# The orcs service
+apiVersion: v1
+kind: Service
+metadata:
+  name: orcs
+  labels:
+    app: orcs
+spec:
+  ports:
+  - port: 80
+    targetPort: 8080
+  selector:
+    app: orcs
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: orcs-config
+  data:
+    verbose: "true"
+- apiVersion: batch/v1
+  kind: Job
+  metadata:
+    name: orcs-migrate
+  spec:
+    backoffLimit: 2
+    template:
+      spec:
+        restartPolicy: Never
+        containers:
+        - name: migrate
+          image: ocopea/orcs-migrate
+---
+---
+{"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "orcs"}}
+`
+
+func TestDecodeManifests(t *testing.T) {
+	objects, err := DecodeManifests(strings.NewReader(testManifests))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objects) != 4 {
+		t.Fatalf("expected 4 objects, got %d", len(objects))
+	}
+
+	svc, ok := objects[0].(*v1.Service)
+	if !ok || svc.Name != "orcs" || svc.Spec.Ports[0].Port != 80 || svc.Spec.Ports[0].TargetPort.IntVal != 8080 {
+		t.Errorf("unexpected service %+v", objects[0])
+	}
+	configMap, ok := objects[1].(Unstructured)
+	if !ok || configMap.GetName() != "orcs-config" || NestedString(configMap, "data", "verbose") != "true" {
+		t.Errorf("unexpected config map %+v", objects[1])
+	}
+	job, ok := objects[2].(*batchv1.Job)
+	if !ok || *job.Spec.BackoffLimit != 2 || job.Spec.Template.Spec.Containers[0].Image != "ocopea/orcs-migrate" {
+		t.Errorf("unexpected job %+v", objects[2])
+	}
+	if ns, ok := objects[3].(*v1.Namespace); !ok || ns.Name != "orcs" {
+		t.Errorf("unexpected namespace %+v", objects[3])
+	}
+
+	if _, err = DecodeManifests(strings.NewReader("metadata:\n  name: orcs\n")); err == nil {
+		t.Errorf("expected an object without kind to fail decoding")
+	}
+	if _, err = DecodeManifests(strings.NewReader("kind: [Service\n")); err == nil {
+		t.Errorf("expected invalid yaml to fail decoding")
+	}
+}
+
+func TestSortManifestObjects(t *testing.T) {
+	objects, err := DecodeUnstructuredManifests(strings.NewReader(testManifests + `
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: orcs
+---
+apiVersion: example.com/v1
+kind: Orc
+metadata:
+  name: azog
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var kinds []string
+	for _, obj := range sortManifestObjects(objects) {
+		kinds = append(kinds, obj.kind.resource.String())
+	}
+	expected := "v1/namespaces v1/configmaps batch/v1/jobs apps/v1/deployments v1/services example.com/v1/orcs"
+	if strings.Join(kinds, " ") != expected {
+		t.Errorf("expected creation order %s, got %s", expected, strings.Join(kinds, " "))
+	}
+}
+
+func TestPluralResourceName(t *testing.T) {
+	for kind, expected := range map[string]string{
+		"Deployment":    "deployments",
+		"Ingress":       "ingresses",
+		"NetworkPolicy": "networkpolicies",
+		"Gateway":       "gateways",
+	} {
+		if plural := pluralResourceName(kind); plural != expected {
+			t.Errorf("expected %s resource of kind %s, got %s", expected, kind, plural)
+		}
+	}
+}
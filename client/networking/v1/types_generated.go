@@ -0,0 +1,177 @@
+// Code generated by typegen from swagger.json. DO NOT EDIT.
+
+// Package v1 holds the types of the networking.k8s.io/v1 api group, Ingresses
+package v1
+
+import (
+	"ocopea/kubernetes/client/unversioned"
+	"ocopea/kubernetes/client/v1"
+)
+
+// Ingress is a collection of rules that allow inbound connections to reach the endpoints defined by a
+// backend. An Ingress can be configured to give services externally-reachable urls, load balance
+// traffic, terminate SSL, offer name based virtual hosting etc.
+type Ingress struct {
+	unversioned.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	v1.ObjectMeta `json:"metadata,omitempty"`
+
+	// spec is the desired state of the Ingress.
+	Spec IngressSpec `json:"spec,omitempty"`
+
+	// status is the current state of the Ingress.
+	Status IngressStatus `json:"status,omitempty"`
+}
+
+// IngressList is a collection of Ingress.
+type IngressList struct {
+	unversioned.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	unversioned.ListMeta `json:"metadata,omitempty"`
+
+	// items is the list of Ingress.
+	Items []Ingress `json:"items"`
+}
+
+// IngressSpec describes the Ingress the user wishes to exist.
+type IngressSpec struct {
+	// defaultBackend is the backend that should handle requests that don't match any rule. If Rules are
+	// not specified, DefaultBackend must be specified. If DefaultBackend is not set, the handling of
+	// requests that do not match any of the rules will be up to the Ingress controller.
+	DefaultBackend *IngressBackend `json:"defaultBackend,omitempty"`
+
+	// ingressClassName is the name of an IngressClass cluster resource. Ingress controller implementations
+	// use this field to know whether they should be serving this Ingress resource, by a transitive
+	// connection (controller -> IngressClass -> Ingress resource).
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+
+	// rules is a list of host rules used to configure the Ingress. If unspecified, or no rule matches, all
+	// traffic is sent to the default backend.
+	Rules []IngressRule `json:"rules,omitempty"`
+
+	// tls represents the TLS configuration. Currently the Ingress only supports a single TLS port, 443. If
+	// multiple members of this list specify different hosts, they will be multiplexed on the same port
+	// according to the hostname specified through the SNI TLS extension, if the ingress controller
+	// fulfilling the ingress supports SNI.
+	TLS []IngressTLS `json:"tls,omitempty"`
+}
+
+// IngressBackend describes all endpoints for a given service and port.
+type IngressBackend struct {
+	// resource is an ObjectRef to another Kubernetes resource in the namespace of the Ingress object. If
+	// resource is specified, a service.Name and service.Port must not be specified. This is a mutually
+	// exclusive setting with "Service".
+	Resource *v1.TypedLocalObjectReference `json:"resource,omitempty"`
+
+	// service references a service as a backend. This is a mutually exclusive setting with "Resource".
+	Service *IngressServiceBackend `json:"service,omitempty"`
+}
+
+// IngressServiceBackend references a Kubernetes Service as a Backend.
+type IngressServiceBackend struct {
+	// name is the referenced service. The service must exist in the same namespace as the Ingress object.
+	Name string `json:"name"`
+
+	// port of the referenced service. A port name or port number is required for a IngressServiceBackend.
+	Port ServiceBackendPort `json:"port,omitempty"`
+}
+
+// ServiceBackendPort is the service port being referenced.
+type ServiceBackendPort struct {
+	// name is the name of the port on the Service. This is a mutually exclusive setting with "Number".
+	Name string `json:"name,omitempty"`
+
+	// number is the numerical port number (e.g. 80) on the Service. This is a mutually exclusive setting
+	// with "Name".
+	Number int `json:"number,omitempty"`
+}
+
+// IngressRule represents the rules mapping the paths under a specified host to the related backend
+// services. Incoming requests are first evaluated for a host match, then routed to the backend
+// associated with the matching IngressRuleValue.
+type IngressRule struct {
+	// host is the fully qualified domain name of a network host, as defined by RFC 3986. Incoming requests
+	// are matched against the host before the IngressRuleValue. If the host is unspecified, the Ingress
+	// routes all traffic based on the specified IngressRuleValue. host can be "precise" which is a domain
+	// name without the terminating dot of a network host (e.g. "foo.bar.com") or "wildcard", which is a
+	// domain name prefixed with a single wildcard label (e.g. "*.foo.com").
+	Host string `json:"host,omitempty"`
+
+	HTTP *HTTPIngressRuleValue `json:"http,omitempty"`
+}
+
+// HTTPIngressRuleValue is a list of http selectors pointing to backends. In the example:
+// http://<host>/<path>?<searchpart> -> backend where where parts of the url correspond to RFC 3986,
+// this resource will be used to match against everything after the last '/' and before the first '?'
+// or '#'.
+type HTTPIngressRuleValue struct {
+	// paths is a collection of paths that map requests to backends.
+	Paths []HTTPIngressPath `json:"paths"`
+}
+
+// HTTPIngressPath associates a path with a backend. Incoming urls matching the path are forwarded to
+// the backend.
+type HTTPIngressPath struct {
+	// Backend defines the referenced service endpoint to which the traffic will be forwarded to.
+	Backend IngressBackend `json:"backend"`
+
+	// Path is matched against the path of an incoming request. Currently it can contain characters
+	// disallowed from the conventional "path" part of a URL as defined by RFC 3986. Paths must begin with
+	// a '/' and must be present when using PathType with value "Exact" or "Prefix".
+	Path string `json:"path,omitempty"`
+
+	// PathType determines the interpretation of the path matching. PathType can be one of Exact, Prefix or
+	// ImplementationSpecific.
+	PathType *string `json:"pathType"`
+}
+
+// IngressTLS describes the transport layer security associated with an ingress.
+type IngressTLS struct {
+	// hosts is a list of hosts included in the TLS certificate. The values in this list must match the
+	// name/s used in the tlsSecret. Defaults to the wildcard host setting for the loadbalancer controller
+	// fulfilling this Ingress, if left unspecified.
+	Hosts []string `json:"hosts,omitempty"`
+
+	// secretName is the name of the secret used to terminate TLS traffic on port 443. Field is left
+	// optional to allow TLS routing based on SNI hostname alone.
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// IngressStatus describe the current state of the Ingress.
+type IngressStatus struct {
+	// loadBalancer contains the current status of the load-balancer.
+	LoadBalancer IngressLoadBalancerStatus `json:"loadBalancer,omitempty"`
+}
+
+// IngressLoadBalancerStatus represents the status of a load-balancer.
+type IngressLoadBalancerStatus struct {
+	// ingress is a list containing ingress points for the load-balancer.
+	Ingress []IngressLoadBalancerIngress `json:"ingress,omitempty"`
+}
+
+// IngressLoadBalancerIngress represents the status of a load-balancer ingress point.
+type IngressLoadBalancerIngress struct {
+	// hostname is set for load-balancer ingress points that are DNS based.
+	Hostname string `json:"hostname,omitempty"`
+
+	// ip is set for load-balancer ingress points that are IP based.
+	IP string `json:"ip,omitempty"`
+
+	// ports provides information about the ports exposed by this LoadBalancer.
+	Ports []IngressPortStatus `json:"ports,omitempty"`
+}
+
+// IngressPortStatus represents the error condition of a service port
+type IngressPortStatus struct {
+	// error is to record the problem with the service port. The format of the error shall comply with the
+	// following rules: - built-in error values shall be specified in this file and those shall use
+	// CamelCase names - cloud provider specific error values must have names that comply with the format
+	// foo.example.com/CamelCase.
+	Error *string `json:"error,omitempty"`
+
+	// port is the port number of the ingress port.
+	Port int `json:"port"`
+
+	// protocol is the protocol of the ingress port. The supported values are: "TCP", "UDP", "SCTP"
+	Protocol string `json:"protocol"`
+}
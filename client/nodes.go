@@ -0,0 +1,106 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"ocopea/kubernetes/client/v1"
+)
+
+// Node address types in the order we prefer them when building urls reachable from outside the cluster
+var reachableNodeAddressTypes = []v1.NodeAddressType{
+	v1.NodeExternalIP,
+	v1.NodeInternalIP,
+	v1.NodeLegacyHostIP,
+	v1.NodeHostName,
+}
+
+func (c *Client) ListNodes(labelFilters map[string]string) ([]*v1.Node, error) {
+	respNodeList := &v1.NodeList{}
+	// Listing all nodes in the cluster
+	err := c.getEntityInfo("nodes", "", respNodeList)
+	if err != nil {
+		return nil, fmt.Errorf("Failed listing k8s nodes - %s", err.Error())
+	}
+
+	// Filtering
+	nodeList := make([]*v1.Node, 0)
+	if respNodeList.Items != nil {
+		for i := range respNodeList.Items {
+			if doesObjectHaveAllLabels(&respNodeList.Items[i].ObjectMeta, labelFilters) {
+				nodeList = append(nodeList, &respNodeList.Items[i])
+			}
+		}
+	}
+
+	return nodeList, nil
+}
+
+func (c *Client) GetNode(nodeName string) (*v1.Node, error) {
+	node := &v1.Node{}
+	err := c.getEntityInfo("nodes", nodeName, node)
+	return node, err
+}
+
+// Returns the most reachable address of the node, preferring ExternalIP, then InternalIP, LegacyHostIP and then Hostname.
+// Returns an empty string in case the node does not report any of those
+func GetNodeReachableAddress(node *v1.Node) string {
+	for _, addressType := range reachableNodeAddressTypes {
+		for _, nodeAddress := range node.Status.Addresses {
+			if nodeAddress.Type == addressType && nodeAddress.Address != "" {
+				return nodeAddress.Address
+			}
+		}
+	}
+	return ""
+}
+
+func isNodeReady(node *v1.Node) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// Discovers an address that can be used for building NodePort urls of the cluster.
+// Ready nodes are preferred, in case none of the nodes report being ready, any node with an address is used
+func (c *Client) FindClusterAddress() (string, error) {
+	nodes, err := c.ListNodes(nil)
+	if err != nil {
+		return "", err
+	}
+	return findClusterAddress(nodes)
+}
+
+func findClusterAddress(nodes []*v1.Node) (string, error) {
+	if len(nodes) == 0 {
+		return "", errors.New("Failed discovering cluster address, no nodes found")
+	}
+
+	fallbackAddress := ""
+	for _, node := range nodes {
+		address := GetNodeReachableAddress(node)
+		if address == "" {
+			continue
+		}
+		if isNodeReady(node) {
+			log.Printf("discovered cluster address %s using node %s\n", address, node.Name)
+			return address, nil
+		}
+		if fallbackAddress == "" {
+			fallbackAddress = address
+		}
+	}
+
+	if fallbackAddress == "" {
+		return "", fmt.Errorf("Failed discovering cluster address, none of the %d nodes report an address", len(nodes))
+	}
+	log.Printf("none of the nodes is ready, using cluster address %s\n", fallbackAddress)
+	return fallbackAddress, nil
+}
@@ -0,0 +1,118 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"ocopea/kubernetes/client/v1"
+	"testing"
+)
+
+func newNode(name string, ready bool, addresses ...v1.NodeAddress) *v1.Node {
+	node := &v1.Node{}
+	node.Name = name
+	node.Status.Addresses = addresses
+	status := v1.ConditionFalse
+	if ready {
+		status = v1.ConditionTrue
+	}
+	node.Status.Conditions = []v1.NodeCondition{{Type: v1.NodeReady, Status: status}}
+	return node
+}
+
+func TestGetNodeReachableAddress(t *testing.T) {
+	external := v1.NodeAddress{Type: v1.NodeExternalIP, Address: "35.1.1.1"}
+	internal := v1.NodeAddress{Type: v1.NodeInternalIP, Address: "10.0.0.1"}
+	legacy := v1.NodeAddress{Type: v1.NodeLegacyHostIP, Address: "192.168.99.100"}
+	hostName := v1.NodeAddress{Type: v1.NodeHostName, Address: "minikube"}
+
+	for _, test := range []struct {
+		addresses []v1.NodeAddress
+		expected  string
+	}{
+		{[]v1.NodeAddress{hostName, legacy, internal, external}, "35.1.1.1"},
+		{[]v1.NodeAddress{hostName, legacy, internal}, "10.0.0.1"},
+		{[]v1.NodeAddress{hostName, legacy}, "192.168.99.100"},
+		{[]v1.NodeAddress{hostName}, "minikube"},
+		{[]v1.NodeAddress{{Type: v1.NodeExternalIP}, internal}, "10.0.0.1"},
+		{nil, ""},
+	} {
+		if address := GetNodeReachableAddress(newNode("node", true, test.addresses...)); address != test.expected {
+			t.Errorf("expected address %q of %v, got %q", test.expected, test.addresses, address)
+		}
+	}
+}
+
+func TestFindClusterAddress(t *testing.T) {
+	unschedulable := newNode("unschedulable", true, v1.NodeAddress{Type: v1.NodeExternalIP, Address: "35.1.1.4"})
+	unschedulable.Spec.Unschedulable = true
+	for _, test := range []struct {
+		description string
+		nodes       []*v1.Node
+		expected    string
+	}{
+		{
+			"ready nodes are preferred",
+			[]*v1.Node{
+				newNode("not-ready", false, v1.NodeAddress{Type: v1.NodeExternalIP, Address: "35.1.1.1"}),
+				unschedulable,
+				newNode("ready", true, v1.NodeAddress{Type: v1.NodeInternalIP, Address: "10.0.0.2"}),
+			},
+			"10.0.0.2",
+		},
+		{
+			"ready nodes without an address are skipped",
+			[]*v1.Node{
+				newNode("ready-without-address", true),
+				newNode("ready", true, v1.NodeAddress{Type: v1.NodeInternalIP, Address: "10.0.0.2"}),
+			},
+			"10.0.0.2",
+		},
+		{
+			"falls back to any node with an address",
+			[]*v1.Node{
+				newNode("without-address", false),
+				newNode("not-ready", false, v1.NodeAddress{Type: v1.NodeLegacyHostIP, Address: "192.168.99.100"}),
+				newNode("ready-without-address", true),
+			},
+			"192.168.99.100",
+		},
+	} {
+		address, err := findClusterAddress(test.nodes)
+		if err != nil {
+			t.Errorf("%s - unexpected error %s", test.description, err.Error())
+		} else if address != test.expected {
+			t.Errorf("%s - expected address %s, got %s", test.description, test.expected, address)
+		}
+	}
+
+	if _, err := findClusterAddress(nil); err == nil {
+		t.Error("expected discovery to fail without nodes")
+	}
+	if _, err := findClusterAddress([]*v1.Node{newNode("without-address", true)}); err == nil {
+		t.Error("expected discovery to fail when no node reports an address")
+	}
+}
+
+func TestFindClusterAddressListsNodes(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/nodes" {
+			t.Errorf("expected nodes to be listed, got %s", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(v1.NodeList{Items: []v1.Node{
+			*newNode("not-ready", false, v1.NodeAddress{Type: v1.NodeExternalIP, Address: "35.1.1.1"}),
+			*newNode("ready", true, v1.NodeAddress{Type: v1.NodeExternalIP, Address: "35.1.1.2"}),
+		}})
+	}))
+	defer ts.Close()
+
+	c := &Client{Url: ts.URL, Namespace: "ocopea"}
+	address, err := c.FindClusterAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if address != "35.1.1.2" {
+		t.Errorf("expected the address of the ready node, got %s", address)
+	}
+}
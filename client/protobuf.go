@@ -0,0 +1,62 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"ocopea/kubernetes/client/protobuf"
+	"ocopea/kubernetes/client/unversioned"
+)
+
+// Media types accepted when protobuf is enabled, the api server falls back to json for kinds it doesn't encode as
+// protobuf
+const protobufAccept = protobuf.ContentType + ", application/json"
+
+// Returns the media types accepted by list and watch requests of the kinds supporting protobuf
+func (c *Client) accept() string {
+	if c.Protobuf {
+		return protobufAccept
+	}
+	return ""
+}
+
+// Returns true in case the response is encoded in the protobuf wire format, including watch streams
+// (application/vnd.kubernetes.protobuf;stream=watch)
+func isProtobuf(resp *http.Response) bool {
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return err == nil && mediaType == protobuf.ContentType
+}
+
+// Decodes an object encoded either as json or in the protobuf wire format. The type meta of protobuf encoded objects
+// is carried by their envelope and set on typeMeta
+func decodeObject(data []byte, isProtobuf bool, obj protobuf.Unmarshaler, typeMeta *unversioned.TypeMeta) error {
+	if !isProtobuf {
+		return json.Unmarshal(data, obj)
+	}
+	envelope, err := protobuf.Unmarshal(data, obj)
+	if err != nil {
+		return err
+	}
+	typeMeta.APIVersion = envelope.APIVersion
+	typeMeta.Kind = envelope.Kind
+	return nil
+}
+
+// Lists the collection at the api path into list, in the protobuf wire format when enabled
+func (c *Client) list(path string, list protobuf.Unmarshaler, typeMeta *unversioned.TypeMeta) error {
+	resp, err := c.sendHttpAccepting("GET", path, nil, "application/json", c.accept())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return newStatusError("GET", path, resp)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return decodeObject(body, isProtobuf(resp), list, typeMeta)
+}
@@ -0,0 +1,471 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+// Package protobuf implements the kubernetes protobuf wire format (application/vnd.kubernetes.protobuf).
+// Objects are encoded as protocol buffers messages wrapped by an envelope identifying their kind, prefixed by a
+// magic number. The encoding of the api types is generated by client/protogen, this package provides the
+// primitives the generated code is built of along with the envelope and the framing of watch streams
+package protobuf
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+)
+
+// ContentType is the media type of objects encoded in the protobuf wire format
+const ContentType = "application/vnd.kubernetes.protobuf"
+
+// magic prefixes every object encoded by the api server
+var magic = []byte{0x6b, 0x38, 0x73, 0x00}
+
+// WireType is the way a field value is encoded
+type WireType int
+
+const (
+	WireVarint  WireType = 0
+	WireFixed64 WireType = 1
+	WireBytes   WireType = 2
+	WireFixed32 WireType = 5
+)
+
+// Marshaler is implemented by the api types encoded as protocol buffers messages
+type Marshaler interface {
+	MarshalProtobuf(e *Encoder)
+}
+
+// Unmarshaler is implemented by the api types decoded from protocol buffers messages
+type Unmarshaler interface {
+	UnmarshalProtobuf(data []byte) error
+}
+
+// Decoder reads the fields of a message one by one. The first error is kept and ends the iteration, reading a
+// value after an error returns its zero value, so callers only check Err once done
+type Decoder struct {
+	data     []byte
+	pos      int
+	field    int
+	wireType WireType
+	err      error
+}
+
+func NewDecoder(data []byte) Decoder {
+	return Decoder{data: data}
+}
+
+// Advances to the next field of the message, returns false at the end of the message or on error
+func (d *Decoder) Next() bool {
+	if d.err != nil || d.pos >= len(d.data) {
+		return false
+	}
+	tag := d.varint()
+	if d.err != nil {
+		return false
+	}
+	d.field = int(tag >> 3)
+	d.wireType = WireType(tag & 7)
+	if d.field <= 0 {
+		d.fail(fmt.Errorf("invalid field number %d at offset %d", d.field, d.pos))
+		return false
+	}
+	return true
+}
+
+// Returns the number of the current field
+func (d *Decoder) Field() int {
+	return d.field
+}
+
+// Returns the first error the decoder encountered
+func (d *Decoder) Err() error {
+	return d.err
+}
+
+func (d *Decoder) fail(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+func (d *Decoder) expect(wireType WireType) bool {
+	if d.err != nil {
+		return false
+	}
+	if d.wireType != wireType {
+		d.fail(fmt.Errorf("field %d has wire type %d, expected %d", d.field, d.wireType, wireType))
+		return false
+	}
+	return true
+}
+
+func (d *Decoder) varint() uint64 {
+	var value uint64
+	for shift := uint(0); shift < 64; shift += 7 {
+		if d.pos >= len(d.data) {
+			d.fail(fmt.Errorf("unexpected end of message reading field %d", d.field))
+			return 0
+		}
+		b := d.data[d.pos]
+		d.pos++
+		value |= uint64(b&0x7f) << shift
+		if b < 0x80 {
+			return value
+		}
+	}
+	d.fail(fmt.Errorf("varint overflow reading field %d", d.field))
+	return 0
+}
+
+// Returns the length delimited value of the current field without copying it
+func (d *Decoder) raw() []byte {
+	if !d.expect(WireBytes) {
+		return nil
+	}
+	length := d.varint()
+	if d.err != nil {
+		return nil
+	}
+	if length > uint64(len(d.data)-d.pos) {
+		d.fail(fmt.Errorf("field %d of length %d exceeds the message", d.field, length))
+		return nil
+	}
+	value := d.data[d.pos : d.pos+int(length)]
+	d.pos += int(length)
+	return value
+}
+
+// Skips the value of the current field, used for fields the api types don't have
+func (d *Decoder) Skip() {
+	switch d.wireType {
+	case WireVarint:
+		d.varint()
+	case WireBytes:
+		d.raw()
+	case WireFixed64:
+		d.skipBytes(8)
+	case WireFixed32:
+		d.skipBytes(4)
+	default:
+		d.fail(fmt.Errorf("unsupported wire type %d of field %d", d.wireType, d.field))
+	}
+}
+
+func (d *Decoder) skipBytes(n int) {
+	if n > len(d.data)-d.pos {
+		d.fail(fmt.Errorf("unexpected end of message reading field %d", d.field))
+		return
+	}
+	d.pos += n
+}
+
+func (d *Decoder) Int() int64 {
+	if !d.expect(WireVarint) {
+		return 0
+	}
+	return int64(d.varint())
+}
+
+func (d *Decoder) Bool() bool {
+	return d.Int() != 0
+}
+
+func (d *Decoder) Double() float64 {
+	if !d.expect(WireFixed64) || d.pos+8 > len(d.data) {
+		d.fail(fmt.Errorf("unexpected end of message reading field %d", d.field))
+		return 0
+	}
+	var bits uint64
+	for i := 7; i >= 0; i-- {
+		bits = bits<<8 | uint64(d.data[d.pos+i])
+	}
+	d.pos += 8
+	return math.Float64frombits(bits)
+}
+
+func (d *Decoder) String() string {
+	return string(d.raw())
+}
+
+// Returns a copy of the bytes value of the current field
+func (d *Decoder) Bytes() []byte {
+	value := d.raw()
+	if value == nil {
+		return nil
+	}
+	return append([]byte{}, value...)
+}
+
+// Returns the values of a repeated integer field, which are either packed in a single field or a field each
+func (d *Decoder) Ints() []int64 {
+	if d.err != nil {
+		return nil
+	}
+	if d.wireType != WireBytes {
+		return []int64{d.Int()}
+	}
+	packed := Decoder{data: d.raw(), field: d.field}
+	var values []int64
+	for packed.err == nil && packed.pos < len(packed.data) {
+		values = append(values, int64(packed.varint()))
+	}
+	d.fail(packed.err)
+	return values
+}
+
+// Decodes the message value of the current field into m
+func (d *Decoder) Message(m Unmarshaler) {
+	value := d.raw()
+	if d.err != nil {
+		return
+	}
+	if err := m.UnmarshalProtobuf(value); err != nil {
+		d.fail(fmt.Errorf("field %d - %s", d.field, err.Error()))
+	}
+}
+
+// Decodes a map entry with a string key and a string value
+func (d *Decoder) StringEntry() (string, string) {
+	entry := NewDecoder(d.raw())
+	var key, value string
+	for entry.Next() {
+		switch entry.Field() {
+		case 1:
+			key = entry.String()
+		case 2:
+			value = entry.String()
+		default:
+			entry.Skip()
+		}
+	}
+	d.fail(entry.err)
+	return key, value
+}
+
+// Decodes a map entry with a string key and a bytes value
+func (d *Decoder) BytesEntry() (string, []byte) {
+	entry := NewDecoder(d.raw())
+	var key string
+	var value []byte
+	for entry.Next() {
+		switch entry.Field() {
+		case 1:
+			key = entry.String()
+		case 2:
+			value = entry.Bytes()
+		default:
+			entry.Skip()
+		}
+	}
+	d.fail(entry.err)
+	return key, value
+}
+
+// Decodes a map entry with a string key and a message value into value, returns the key
+func (d *Decoder) MessageEntry(value Unmarshaler) string {
+	entry := NewDecoder(d.raw())
+	var key string
+	for entry.Next() {
+		switch entry.Field() {
+		case 1:
+			key = entry.String()
+		case 2:
+			entry.Message(value)
+		default:
+			entry.Skip()
+		}
+	}
+	d.fail(entry.err)
+	return key
+}
+
+// Encoder appends the fields of messages to a buffer
+type Encoder struct {
+	buf []byte
+}
+
+func (e *Encoder) varint(value uint64) {
+	for value >= 0x80 {
+		e.buf = append(e.buf, byte(value)|0x80)
+		value >>= 7
+	}
+	e.buf = append(e.buf, byte(value))
+}
+
+func (e *Encoder) tag(field int, wireType WireType) {
+	e.varint(uint64(field)<<3 | uint64(wireType))
+}
+
+func (e *Encoder) Int(field int, value int64) {
+	e.tag(field, WireVarint)
+	e.varint(uint64(value))
+}
+
+func (e *Encoder) Bool(field int, value bool) {
+	e.tag(field, WireVarint)
+	if value {
+		e.buf = append(e.buf, 1)
+	} else {
+		e.buf = append(e.buf, 0)
+	}
+}
+
+func (e *Encoder) Double(field int, value float64) {
+	e.tag(field, WireFixed64)
+	bits := math.Float64bits(value)
+	for i := 0; i < 8; i++ {
+		e.buf = append(e.buf, byte(bits>>(8*uint(i))))
+	}
+}
+
+func (e *Encoder) String(field int, value string) {
+	e.tag(field, WireBytes)
+	e.varint(uint64(len(value)))
+	e.buf = append(e.buf, value...)
+}
+
+func (e *Encoder) Bytes(field int, value []byte) {
+	e.tag(field, WireBytes)
+	e.varint(uint64(len(value)))
+	e.buf = append(e.buf, value...)
+}
+
+// Encodes m as the value of the field
+func (e *Encoder) Message(field int, m Marshaler) {
+	e.tag(field, WireBytes)
+	start := e.reserveLength()
+	m.MarshalProtobuf(e)
+	e.writeLength(start)
+}
+
+// Reserves a single byte for the length of a value whose length is not known yet, see writeLength
+func (e *Encoder) reserveLength() int {
+	e.buf = append(e.buf, 0)
+	return len(e.buf) - 1
+}
+
+// Writes the length of the value encoded after the reserved byte, moving the value when the length takes more
+func (e *Encoder) writeLength(start int) {
+	length := len(e.buf) - start - 1
+	if length < 0x80 {
+		e.buf[start] = byte(length)
+		return
+	}
+	var prefix Encoder
+	prefix.varint(uint64(length))
+	e.buf = append(e.buf, prefix.buf[1:]...)
+	copy(e.buf[start+len(prefix.buf):], e.buf[start+1:start+1+length])
+	copy(e.buf[start:], prefix.buf)
+}
+
+// Encodes a map entry with a string key and a string value
+func (e *Encoder) StringEntry(field int, key string, value string) {
+	e.tag(field, WireBytes)
+	start := e.reserveLength()
+	e.String(1, key)
+	e.String(2, value)
+	e.writeLength(start)
+}
+
+// Encodes a map entry with a string key and a bytes value
+func (e *Encoder) BytesEntry(field int, key string, value []byte) {
+	e.tag(field, WireBytes)
+	start := e.reserveLength()
+	e.String(1, key)
+	e.Bytes(2, value)
+	e.writeLength(start)
+}
+
+// Encodes a map entry with a string key and a message value
+func (e *Encoder) MessageEntry(field int, key string, value Marshaler) {
+	e.tag(field, WireBytes)
+	start := e.reserveLength()
+	e.String(1, key)
+	e.Message(2, value)
+	e.writeLength(start)
+}
+
+// TypeMeta identifies the kind of an encoded object, it is carried by the envelope rather than by the object
+type TypeMeta struct {
+	APIVersion string
+	Kind       string
+}
+
+func (m *TypeMeta) MarshalProtobuf(e *Encoder) {
+	e.String(1, m.APIVersion)
+	e.String(2, m.Kind)
+}
+
+func (m *TypeMeta) UnmarshalProtobuf(data []byte) error {
+	d := NewDecoder(data)
+	for d.Next() {
+		switch d.Field() {
+		case 1:
+			m.APIVersion = d.String()
+		case 2:
+			m.Kind = d.String()
+		default:
+			d.Skip()
+		}
+	}
+	return d.Err()
+}
+
+// The envelope wrapping encoded objects (runtime.Unknown)
+type envelope struct {
+	TypeMeta
+	Raw             []byte
+	ContentEncoding string
+	ContentType     string
+}
+
+func (m *envelope) MarshalProtobuf(e *Encoder) {
+	e.Message(1, &m.TypeMeta)
+	e.Bytes(2, m.Raw)
+	e.String(3, m.ContentEncoding)
+	e.String(4, m.ContentType)
+}
+
+func (m *envelope) UnmarshalProtobuf(data []byte) error {
+	d := NewDecoder(data)
+	for d.Next() {
+		switch d.Field() {
+		case 1:
+			d.Message(&m.TypeMeta)
+		case 2:
+			m.Raw = d.raw()
+		case 3:
+			m.ContentEncoding = d.String()
+		case 4:
+			m.ContentType = d.String()
+		default:
+			d.Skip()
+		}
+	}
+	return d.Err()
+}
+
+// Decodes an object encoded by the api server into obj, returns the kind of the object found in the envelope
+func Unmarshal(data []byte, obj Unmarshaler) (TypeMeta, error) {
+	if !bytes.HasPrefix(data, magic) {
+		return TypeMeta{}, fmt.Errorf("missing the protobuf magic number")
+	}
+	var env envelope
+	if err := env.UnmarshalProtobuf(data[len(magic):]); err != nil {
+		return TypeMeta{}, fmt.Errorf("failed decoding protobuf envelope - %s", err.Error())
+	}
+	if env.ContentEncoding != "" {
+		return env.TypeMeta, fmt.Errorf("unsupported content encoding %s of %s", env.ContentEncoding, env.Kind)
+	}
+	if err := obj.UnmarshalProtobuf(env.Raw); err != nil {
+		return env.TypeMeta, fmt.Errorf("failed decoding %s - %s", env.Kind, err.Error())
+	}
+	return env.TypeMeta, nil
+}
+
+// Encodes the object the way the api server does, wrapped by an envelope of the given kind
+func Marshal(typeMeta TypeMeta, obj Marshaler) []byte {
+	var raw Encoder
+	obj.MarshalProtobuf(&raw)
+	env := envelope{TypeMeta: typeMeta, Raw: raw.buf}
+	e := Encoder{buf: append([]byte{}, magic...)}
+	env.MarshalProtobuf(&e)
+	return e.buf
+}
@@ -0,0 +1,232 @@
+package protobuf
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testMessage struct {
+	Count    int64
+	Enabled  bool
+	Ratio    float64
+	Name     string
+	Data     []byte
+	Groups   []int64
+	Labels   map[string]string
+	Children map[string]*testMessage
+	Next     *testMessage
+}
+
+func (m *testMessage) MarshalProtobuf(e *Encoder) {
+	e.Int(1, m.Count)
+	e.Bool(2, m.Enabled)
+	e.Double(3, m.Ratio)
+	e.String(4, m.Name)
+	if m.Data != nil {
+		e.Bytes(5, m.Data)
+	}
+	for _, group := range m.Groups {
+		e.Int(6, group)
+	}
+	for key, value := range m.Labels {
+		e.StringEntry(7, key, value)
+	}
+	for key, value := range m.Children {
+		e.MessageEntry(8, key, value)
+	}
+	if m.Next != nil {
+		e.Message(9, m.Next)
+	}
+}
+
+func (m *testMessage) UnmarshalProtobuf(data []byte) error {
+	d := NewDecoder(data)
+	for d.Next() {
+		switch d.Field() {
+		case 1:
+			m.Count = d.Int()
+		case 2:
+			m.Enabled = d.Bool()
+		case 3:
+			m.Ratio = d.Double()
+		case 4:
+			m.Name = d.String()
+		case 5:
+			m.Data = d.Bytes()
+		case 6:
+			m.Groups = append(m.Groups, d.Ints()...)
+		case 7:
+			if m.Labels == nil {
+				m.Labels = make(map[string]string)
+			}
+			key, value := d.StringEntry()
+			m.Labels[key] = value
+		case 8:
+			if m.Children == nil {
+				m.Children = make(map[string]*testMessage)
+			}
+			value := &testMessage{}
+			m.Children[d.MessageEntry(value)] = value
+		case 9:
+			m.Next = &testMessage{}
+			d.Message(m.Next)
+		default:
+			d.Skip()
+		}
+	}
+	return d.Err()
+}
+
+// Decodes only the name, skipping the other fields
+type nameOnly struct {
+	Name string
+}
+
+func (m *nameOnly) UnmarshalProtobuf(data []byte) error {
+	d := NewDecoder(data)
+	for d.Next() {
+		if d.Field() == 4 {
+			m.Name = d.String()
+		} else {
+			d.Skip()
+		}
+	}
+	return d.Err()
+}
+
+func encode(m Marshaler) []byte {
+	var e Encoder
+	m.MarshalProtobuf(&e)
+	return e.buf
+}
+
+func TestRoundTrip(t *testing.T) {
+	messages := []*testMessage{
+		{},
+		{
+			Count: -42, Enabled: true, Ratio: 0.25, Name: "orcs", Data: []byte{0, 1, 2},
+			Groups:   []int64{2000, 3000},
+			Labels:   map[string]string{"app": "orcs"},
+			Children: map[string]*testMessage{"web": {Name: "web"}},
+			Next:     &testMessage{Count: 1 << 40},
+		},
+		// Lengths taking two and three bytes move the nested messages
+		{Name: "orcs", Next: &testMessage{Name: strings.Repeat("x", 200), Next: &testMessage{Name: strings.Repeat("y", 20000)}}},
+	}
+	for i, m := range messages {
+		decoded := &testMessage{}
+		if err := decoded.UnmarshalProtobuf(encode(m)); err != nil {
+			t.Fatalf("failed decoding message %d - %s", i, err.Error())
+		}
+		if !reflect.DeepEqual(m, decoded) {
+			t.Errorf("expected message %d to round trip, found %+v", i, decoded)
+		}
+	}
+}
+
+func TestPackedInts(t *testing.T) {
+	// Field 6 holding 3, 270 and 86942 packed, the way proto3 and newer api servers encode repeated numbers
+	data := []byte{0x32, 0x06, 0x03, 0x8e, 0x02, 0x9e, 0xa7, 0x05}
+	m := &testMessage{}
+	if err := m.UnmarshalProtobuf(data); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(m.Groups, []int64{3, 270, 86942}) {
+		t.Errorf("expected the packed numbers to be decoded, found %v", m.Groups)
+	}
+}
+
+func TestSkip(t *testing.T) {
+	data := encode(&testMessage{
+		Count: 7, Enabled: true, Ratio: 1.5, Name: "orcs", Data: []byte("data"), Groups: []int64{1},
+		Labels: map[string]string{"app": "orcs"}, Next: &testMessage{Name: "next"},
+	})
+	// A fixed32 field, which the encoder doesn't write
+	data = append(data, 0x55, 1, 2, 3, 4)
+	m := &nameOnly{}
+	if err := m.UnmarshalProtobuf(data); err != nil {
+		t.Fatal(err)
+	}
+	if m.Name != "orcs" {
+		t.Errorf("expected the name orcs, found %q", m.Name)
+	}
+}
+
+func TestTruncated(t *testing.T) {
+	for _, data := range [][]byte{
+		// A tag without a value
+		{0x08},
+		// An unterminated varint
+		{0x08, 0x80},
+		// A string of 4 bytes holding 3
+		{0x22, 0x04, 'o', 'r', 'c'},
+		// A double missing its last byte
+		{0x19, 0, 0, 0, 0, 0, 0, 0},
+		// A nested message whose string exceeds the message
+		{0x4a, 0x02, 0x22, 0x04, 'o', 'r', 'c', 's'},
+	} {
+		if err := (&testMessage{}).UnmarshalProtobuf(data); err == nil {
+			t.Errorf("expected an error decoding % x", data)
+		}
+	}
+}
+
+func TestEnvelope(t *testing.T) {
+	data := Marshal(TypeMeta{APIVersion: "v1", Kind: "Test"}, &testMessage{Name: "orcs"})
+	m := &testMessage{}
+	typeMeta, err := Unmarshal(data, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if typeMeta != (TypeMeta{APIVersion: "v1", Kind: "Test"}) {
+		t.Errorf("expected v1 Test, found %+v", typeMeta)
+	}
+	if m.Name != "orcs" {
+		t.Errorf("expected the name orcs, found %q", m.Name)
+	}
+
+	if _, err = Unmarshal([]byte(`{"kind":"Test"}`), m); err == nil {
+		t.Errorf("expected an error decoding json")
+	}
+	env := envelope{TypeMeta: TypeMeta{Kind: "Test"}, Raw: []byte{1}, ContentEncoding: "gzip"}
+	data = append(append([]byte{}, magic...), encode(&env)...)
+	if _, err = Unmarshal(data, m); err == nil {
+		t.Errorf("expected an error decoding a gzip encoded object")
+	}
+}
+
+func TestWatchEvents(t *testing.T) {
+	events := []*WatchEvent{
+		{Type: "ADDED", Object: Marshal(TypeMeta{APIVersion: "v1", Kind: "Test"}, &testMessage{Name: "orcs"})},
+		{Type: "DELETED", Object: Marshal(TypeMeta{APIVersion: "v1", Kind: "Test"}, &testMessage{Name: strings.Repeat("x", 300)})},
+	}
+	var stream bytes.Buffer
+	for _, event := range events {
+		if err := WriteWatchEvent(&stream, event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data := stream.Bytes()
+
+	for _, expected := range events {
+		event, err := ReadWatchEvent(&stream)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(expected, event) {
+			t.Errorf("expected %s event to round trip, found %+v", expected.Type, event)
+		}
+	}
+	if _, err := ReadWatchEvent(&stream); err != io.EOF {
+		t.Errorf("expected EOF at the end of the stream, found %v", err)
+	}
+	if _, err := ReadWatchEvent(bytes.NewReader(data[:len(data)-1])); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadWatchEvent(bytes.NewReader(data[:10])); err != io.ErrUnexpectedEOF {
+		t.Errorf("expected an unexpected EOF reading a truncated event, found %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package protobuf
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Frames larger than this are rejected rather than allocated, the api server limits objects to a few megabytes
+const maxFrameSize = 64 << 20
+
+// WatchEvent is a single change reported by a watch stream, Object is an encoded object, see Unmarshal
+type WatchEvent struct {
+	Type   string
+	Object []byte
+}
+
+func (m *WatchEvent) MarshalProtobuf(e *Encoder) {
+	e.String(1, m.Type)
+	// The object is a runtime.RawExtension message holding the encoded object
+	e.tag(2, WireBytes)
+	start := e.reserveLength()
+	e.Bytes(1, m.Object)
+	e.writeLength(start)
+}
+
+func (m *WatchEvent) UnmarshalProtobuf(data []byte) error {
+	d := NewDecoder(data)
+	for d.Next() {
+		switch d.Field() {
+		case 1:
+			m.Type = d.String()
+		case 2:
+			object := NewDecoder(d.raw())
+			for object.Next() {
+				if object.Field() == 1 {
+					m.Object = object.raw()
+				} else {
+					object.Skip()
+				}
+			}
+			d.fail(object.Err())
+		default:
+			d.Skip()
+		}
+	}
+	return d.Err()
+}
+
+// Reads the next event of a watch stream. Events are sent in frames prefixed by their big endian 32 bit length,
+// io.EOF is returned when the stream ends between events
+func ReadWatchEvent(r io.Reader) (*WatchEvent, error) {
+	var length [4]byte
+	if _, err := io.ReadFull(r, length[:]); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(length[:])
+	if size > maxFrameSize {
+		return nil, fmt.Errorf("watch event of %d bytes exceeds the maximal frame size", size)
+	}
+	frame := make([]byte, size)
+	if _, err := io.ReadFull(r, frame); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	event := &WatchEvent{}
+	if err := event.UnmarshalProtobuf(frame); err != nil {
+		return nil, fmt.Errorf("failed decoding watch event - %s", err.Error())
+	}
+	return event, nil
+}
+
+// Writes the event as a frame of a watch stream, see ReadWatchEvent
+func WriteWatchEvent(w io.Writer, event *WatchEvent) error {
+	var e Encoder
+	e.buf = make([]byte, 4, 4+len(event.Object)+32)
+	event.MarshalProtobuf(&e)
+	binary.BigEndian.PutUint32(e.buf, uint32(len(e.buf)-4))
+	_, err := w.Write(e.buf)
+	return err
+}
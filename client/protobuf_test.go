@@ -0,0 +1,159 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"ocopea/kubernetes/client/protobuf"
+	"ocopea/kubernetes/client/unversioned"
+	"ocopea/kubernetes/client/v1"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func readFixture(t testing.TB, name string) []byte {
+	data, err := ioutil.ReadFile(filepath.Join("v1", "testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// The stream was recorded from the api server serializers, a pod added, modified and deleted and an expired watch
+func TestWatchPodsProtobufStream(t *testing.T) {
+	stream := readFixture(t, "pods.watch")
+	var accept string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept = r.Header.Get("Accept")
+		w.Header().Set("Content-Type", "application/vnd.kubernetes.protobuf;stream=watch")
+		w.Write(stream)
+	}))
+	defer ts.Close()
+
+	c := &Client{Url: ts.URL, Namespace: "ocopea", Protobuf: true}
+	events := make(chan PodWatchEvent, 10)
+	closeHandle, err := c.WatchPods(nil, "48213", events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeHandle()
+
+	for _, expected := range []struct {
+		eventType WatchEventType
+		phase     v1.PodPhase
+	}{{WatchAdded, v1.PodPending}, {WatchModified, v1.PodRunning}, {WatchDeleted, v1.PodRunning}} {
+		select {
+		case event := <-events:
+			if event.Type != expected.eventType || event.Object.Name != "orcs-x7k2p" || event.Object.Kind != "Pod" ||
+				event.Object.Status.Phase != expected.phase {
+				t.Errorf("expected %s of pod orcs-x7k2p, got %s %+v", expected.eventType, event.Type, event.Object)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %s watch event", expected.eventType)
+		}
+	}
+	select {
+	case event := <-events:
+		if event.Type != WatchError || event.Object != nil || event.Status == nil || event.Status.Code != http.StatusGone {
+			t.Errorf("expected the expired watch status, got %s %+v", event.Type, event.Status)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the error watch event")
+	}
+	if accept != protobufAccept {
+		t.Errorf("expected protobuf to be accepted, got %s", accept)
+	}
+}
+
+func TestProtobufStatusError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := &unversioned.Status{Status: unversioned.StatusFailure, Reason: unversioned.StatusReasonNotFound, Code: http.StatusNotFound}
+		w.Header().Set("Content-Type", protobuf.ContentType)
+		w.WriteHeader(http.StatusNotFound)
+		w.Write(protobuf.Marshal(protobuf.TypeMeta{APIVersion: "v1", Kind: "Status"}, status))
+	}))
+	defer ts.Close()
+
+	c := &Client{Url: ts.URL, Namespace: "missing", Protobuf: true}
+	_, err := c.ListEvents(EventQuery{})
+	if !IsNotFound(err) {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+	var status unversioned.Status
+	if json.Unmarshal([]byte(err.(*StatusError).Body), &status) != nil || status.Reason != unversioned.StatusReasonNotFound {
+		t.Errorf("expected the status to be converted to json, got %s", err.(*StatusError).Body)
+	}
+}
+
+// Returns a list of the given size encoded as json and as protobuf, its items copied from the list fixture
+func encodedListFixture(b *testing.B, fixture string, list protobuf.Marshaler, size int) ([]byte, []byte) {
+	if err := json.Unmarshal(readFixture(b, fixture), list); err != nil {
+		b.Fatal(err)
+	}
+	switch l := list.(type) {
+	case *v1.PodList:
+		items := l.Items
+		for i := len(items); i < size; i++ {
+			pod := *items[i%len(items)].DeepCopy()
+			pod.Name = fmt.Sprintf("orcs-%05d", i)
+			l.Items = append(l.Items, pod)
+		}
+	case *v1.EventList:
+		items := l.Items
+		for i := len(items); i < size; i++ {
+			event := *items[i%len(items)].DeepCopy()
+			event.Name = fmt.Sprintf("orcs-%05d.14f2a", i)
+			l.Items = append(l.Items, event)
+		}
+	}
+	jsonData, err := json.Marshal(list)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return jsonData, protobuf.Marshal(protobuf.TypeMeta{APIVersion: "v1", Kind: "List"}, list)
+}
+
+func benchmarkDecode(b *testing.B, data []byte, isProtobuf bool, newList func() (protobuf.Unmarshaler, *unversioned.TypeMeta)) {
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		list, typeMeta := newList()
+		if err := decodeObject(data, isProtobuf, list, typeMeta); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func newPodList() (protobuf.Unmarshaler, *unversioned.TypeMeta) {
+	list := &v1.PodList{}
+	return list, &list.TypeMeta
+}
+
+func newEventList() (protobuf.Unmarshaler, *unversioned.TypeMeta) {
+	list := &v1.EventList{}
+	return list, &list.TypeMeta
+}
+
+func BenchmarkDecodePodListJSON(b *testing.B) {
+	jsonData, _ := encodedListFixture(b, "podlist.json", &v1.PodList{}, 500)
+	benchmarkDecode(b, jsonData, false, newPodList)
+}
+
+func BenchmarkDecodePodListProtobuf(b *testing.B) {
+	_, protobufData := encodedListFixture(b, "podlist.json", &v1.PodList{}, 500)
+	benchmarkDecode(b, protobufData, true, newPodList)
+}
+
+func BenchmarkDecodeEventListJSON(b *testing.B) {
+	jsonData, _ := encodedListFixture(b, "eventlist.json", &v1.EventList{}, 500)
+	benchmarkDecode(b, jsonData, false, newEventList)
+}
+
+func BenchmarkDecodeEventListProtobuf(b *testing.B) {
+	_, protobufData := encodedListFixture(b, "eventlist.json", &v1.EventList{}, 500)
+	benchmarkDecode(b, protobufData, true, newEventList)
+}
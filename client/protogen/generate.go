@@ -0,0 +1,706 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const protobufImportPath = "ocopea/kubernetes/client/protobuf"
+
+type config struct {
+	// Proto files declaring the messages, relative to the config file
+	Protos []string `json:"protos"`
+
+	// Go types encoded as the root messages by fully qualified message name, e.g. k8s.io.api.core.v1.PodList to
+	// ocopea/kubernetes/client/v1.PodList. The types of their fields are mapped to the messages of the fields
+	Messages map[string]string `json:"messages"`
+
+	// Directory of the packages relative to the config file and its import path
+	Root           string `json:"root"`
+	BaseImportPath string `json:"baseImportPath"`
+}
+
+// typeContext resolves the names used by the declarations of a source file
+type typeContext struct {
+	pkg *typesPackage
+
+	// Import paths by the names the file refers to them by
+	imports map[string]string
+}
+
+type typeDecl struct {
+	name string
+	expr ast.Expr
+	ctx  *typeContext
+}
+
+type typesPackage struct {
+	importPath string
+	name       string
+	dir        string
+
+	// Declarations by name and in source order
+	types map[string]*typeDecl
+	decls []*typeDecl
+
+	// Types having hand written protobuf methods, e.g. Quantity
+	handWritten map[string]bool
+}
+
+// Returns the name of the receiver type of a method declaration
+func receiverTypeName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+	expr := fn.Recv.List[0].Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// Parses the types declared by the non generated, non test source files of the package directory
+func parsePackage(importPath string, dir string) (*typesPackage, error) {
+	fileNames, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(fileNames)
+	pkg := &typesPackage{
+		importPath:  importPath,
+		dir:         dir,
+		types:       make(map[string]*typeDecl),
+		handWritten: make(map[string]bool),
+	}
+	fset := token.NewFileSet()
+	for _, fileName := range fileNames {
+		if strings.HasSuffix(fileName, "_test.go") || filepath.Base(fileName) == generatedFileName {
+			continue
+		}
+		src, err := ioutil.ReadFile(fileName)
+		if err != nil {
+			return nil, err
+		}
+		file, err := parser.ParseFile(fset, fileName, src, 0)
+		if err != nil {
+			return nil, err
+		}
+		pkg.name = file.Name.Name
+
+		ctx := &typeContext{pkg: pkg, imports: make(map[string]string)}
+		for _, spec := range file.Imports {
+			importPath, _ := strconv.Unquote(spec.Path.Value)
+			name := path.Base(importPath)
+			if spec.Name != nil {
+				name = spec.Name.Name
+			}
+			ctx.imports[name] = importPath
+		}
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Name.Name == "UnmarshalProtobuf" {
+					pkg.handWritten[receiverTypeName(decl)] = true
+				}
+			case *ast.GenDecl:
+				if decl.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range decl.Specs {
+					typeSpec := spec.(*ast.TypeSpec)
+					d := &typeDecl{name: typeSpec.Name.Name, expr: typeSpec.Type, ctx: ctx}
+					pkg.types[d.name] = d
+					pkg.decls = append(pkg.decls, d)
+				}
+			}
+		}
+	}
+	if pkg.name == "" {
+		return nil, fmt.Errorf("no source files in %s", dir)
+	}
+	return pkg, nil
+}
+
+// A field of a Go struct encoded as a field of the message the struct is mapped to
+type fieldBinding struct {
+	name  string
+	expr  ast.Expr
+	field *protoField
+}
+
+// A Go struct encoded as a message
+type binding struct {
+	decl    *typeDecl
+	message *protoMessage
+
+	// Ordered by field number
+	fields []*fieldBinding
+}
+
+// generator maps the Go types to messages and generates their protobuf methods
+type generator struct {
+	cfg      *config
+	messages map[string]*protoMessage
+	packages map[string]*typesPackage
+	bindings map[*typeDecl]*binding
+
+	// Pairs of declarations and messages already checked, so recursive types terminate
+	checked map[string]bool
+
+	// State of the package being generated
+	pkg     *typesPackage
+	imports map[string]string
+	buf     *bytes.Buffer
+}
+
+func newGenerator(cfg *config, baseDir string, messages map[string]*protoMessage) *generator {
+	return &generator{
+		cfg:      &config{Root: filepath.Join(baseDir, cfg.Root), BaseImportPath: cfg.BaseImportPath},
+		messages: messages,
+		packages: make(map[string]*typesPackage),
+		bindings: make(map[*typeDecl]*binding),
+		checked:  make(map[string]bool),
+	}
+}
+
+// Returns the parsed package of the import path, only packages of the client are parsed
+func (g *generator) loadPackage(importPath string) (*typesPackage, error) {
+	if pkg, found := g.packages[importPath]; found {
+		return pkg, nil
+	}
+	if !strings.HasPrefix(importPath+"/", g.cfg.BaseImportPath+"/") {
+		return nil, nil
+	}
+	relative := strings.TrimPrefix(strings.TrimPrefix(importPath, g.cfg.BaseImportPath), "/")
+	pkg, err := parsePackage(importPath, filepath.Join(g.cfg.Root, filepath.FromSlash(relative)))
+	if err != nil {
+		return nil, err
+	}
+	g.packages[importPath] = pkg
+	return pkg, nil
+}
+
+// Resolves a type name, returning its declaration, or its name for predeclared types and types of packages
+// outside of the client
+func (g *generator) resolve(expr ast.Expr, ctx *typeContext) (*typeDecl, string, error) {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if d, found := ctx.pkg.types[e.Name]; found {
+			return d, "", nil
+		}
+		if types.Universe.Lookup(e.Name) != nil {
+			return nil, e.Name, nil
+		}
+		return nil, "", fmt.Errorf("unknown type %s in package %s", e.Name, ctx.pkg.importPath)
+	case *ast.SelectorExpr:
+		x, _ := e.X.(*ast.Ident)
+		if x == nil {
+			return nil, "", fmt.Errorf("unsupported type %s", types.ExprString(expr))
+		}
+		importPath, found := ctx.imports[x.Name]
+		if !found {
+			return nil, "", fmt.Errorf("unknown package %s in package %s", x.Name, ctx.pkg.importPath)
+		}
+		pkg, err := g.loadPackage(importPath)
+		if err != nil || pkg == nil {
+			return nil, importPath + "." + e.Sel.Name, err
+		}
+		if d, found := pkg.types[e.Sel.Name]; found {
+			return d, "", nil
+		}
+		return nil, "", fmt.Errorf("unknown type %s", types.ExprString(expr))
+	}
+	return nil, "", fmt.Errorf("unsupported type %s", types.ExprString(expr))
+}
+
+// goType describes how values of a Go type are encoded
+type goType struct {
+	// string, bool, int, int32, int64, float64 and bytes for scalars, message, pointer, slice or map otherwise
+	kind string
+
+	// The type as written in the source
+	expr ast.Expr
+	ctx  *typeContext
+
+	// Declaration of message types
+	decl *typeDecl
+
+	// Element of pointers and slices, value of maps
+	elem *goType
+	key  *goType
+}
+
+// Returns true in case values of the type are converted to or from the scalar, e.g. Protocol to string
+func (t *goType) isNamed() bool {
+	switch e := t.expr.(type) {
+	case *ast.Ident:
+		return e.Name != t.kind
+	case *ast.ArrayType:
+		return false
+	}
+	return true
+}
+
+func (g *generator) describe(expr ast.Expr, ctx *typeContext) (*goType, error) {
+	t := &goType{expr: expr, ctx: ctx}
+	var err error
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		t.kind = "pointer"
+		t.elem, err = g.describe(e.X, ctx)
+		return t, err
+	case *ast.ArrayType:
+		if e.Len != nil {
+			return nil, fmt.Errorf("unsupported array type %s", types.ExprString(expr))
+		}
+		if ident, ok := e.Elt.(*ast.Ident); ok && ident.Name == "byte" {
+			t.kind = "bytes"
+			return t, nil
+		}
+		t.kind = "slice"
+		t.elem, err = g.describe(e.Elt, ctx)
+		return t, err
+	case *ast.MapType:
+		t.kind = "map"
+		if t.key, err = g.describe(e.Key, ctx); err != nil {
+			return nil, err
+		}
+		if t.key.kind != "string" {
+			return nil, fmt.Errorf("unsupported map key type %s", types.ExprString(e.Key))
+		}
+		t.elem, err = g.describe(e.Value, ctx)
+		return t, err
+	case *ast.Ident, *ast.SelectorExpr:
+		d, name, err := g.resolve(expr, ctx)
+		if err != nil {
+			return nil, err
+		}
+		if d == nil {
+			switch name {
+			case "string", "bool", "int", "int32", "int64", "float64":
+				t.kind = name
+				return t, nil
+			}
+			return nil, fmt.Errorf("unsupported type %s", name)
+		}
+		if _, ok := d.expr.(*ast.StructType); ok {
+			t.kind = "message"
+			t.decl = d
+			return t, nil
+		}
+		// Named types are encoded like their underlying type, keeping their name for conversions
+		underlying, err := g.describe(d.expr, d.ctx)
+		if err != nil {
+			return nil, err
+		}
+		underlying.expr, underlying.ctx = expr, ctx
+		return underlying, nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", types.ExprString(expr))
+}
+
+// Go scalar kinds by the protobuf scalar types they are encoded as
+var scalarKinds = map[string][]string{
+	"string": {"string"},
+	"bool":   {"bool"},
+	"int32":  {"int", "int32", "int64"},
+	"int64":  {"int", "int32", "int64"},
+	"double": {"float64"},
+	"bytes":  {"bytes"},
+}
+
+// Checks the Go type can be encoded as a value of the field, mapping the message types it refers to
+func (g *generator) bindValue(t *goType, field *protoField) error {
+	if field.isScalar() {
+		for _, kind := range scalarKinds[field.typeName] {
+			if t.kind == kind {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s can't be encoded as %s", types.ExprString(t.expr), field.typeName)
+	}
+	if t.kind != "message" {
+		return fmt.Errorf("%s can't be encoded as message %s", types.ExprString(t.expr), field.typeName)
+	}
+	if t.decl.ctx.pkg.handWritten[t.decl.name] {
+		return nil
+	}
+	message, found := g.messages[field.typeName]
+	if !found {
+		return fmt.Errorf("message %s of %s is not declared by the proto files", field.typeName, types.ExprString(t.expr))
+	}
+	return g.bind(t.decl, message)
+}
+
+// Checks the Go type of a struct field can be encoded as the field
+func (g *generator) bindField(t *goType, field *protoField) error {
+	switch field.label {
+	case "repeated":
+		if t.kind != "slice" {
+			return fmt.Errorf("%s can't be encoded as repeated %s", types.ExprString(t.expr), field.typeName)
+		}
+		if t.elem.kind == "pointer" {
+			t = t.elem
+		}
+		return g.bindValue(t.elem, field)
+	case "map":
+		if t.kind != "map" {
+			return fmt.Errorf("%s can't be encoded as a map of %s", types.ExprString(t.expr), field.typeName)
+		}
+		if field.isScalar() && field.typeName != "string" && field.typeName != "bytes" {
+			return fmt.Errorf("unsupported map value type %s", field.typeName)
+		}
+		return g.bindValue(t.elem, field)
+	}
+	if t.kind == "pointer" {
+		t = t.elem
+	}
+	return g.bindValue(t, field)
+}
+
+// Returns the name the field is encoded by, its json name or the lower camel case field name for inlined fields
+func protoFieldName(field *ast.Field, name string) string {
+	if field.Tag != nil {
+		tag, _ := strconv.Unquote(field.Tag.Value)
+		jsonName := strings.Split(reflect.StructTag(tag).Get("json"), ",")[0]
+		if jsonName == "-" {
+			return ""
+		}
+		if jsonName != "" {
+			return jsonName
+		}
+	}
+	first, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToLower(first)) + name[size:]
+}
+
+// Returns the name of a struct field, the type name for embedded fields
+func embeddedFieldName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return embeddedFieldName(e.X)
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	case *ast.Ident:
+		return e.Name
+	}
+	return ""
+}
+
+// Maps the Go struct to the message. Fields of the struct the message doesn't have are not encoded, a struct
+// mapped to several messages must have the same field numbers in all of them
+func (g *generator) bind(d *typeDecl, message *protoMessage) error {
+	key := d.ctx.pkg.importPath + "." + d.name + " " + message.name
+	if g.checked[key] {
+		return nil
+	}
+	g.checked[key] = true
+
+	b := &binding{decl: d, message: message}
+	for _, field := range d.expr.(*ast.StructType).Fields.List {
+		names := []string{embeddedFieldName(field.Type)}
+		if len(field.Names) > 0 {
+			names = names[:0]
+			for _, name := range field.Names {
+				names = append(names, name.Name)
+			}
+		}
+		for _, name := range names {
+			if !ast.IsExported(name) {
+				continue
+			}
+			protoField, found := message.fields[protoFieldName(field, name)]
+			if !found {
+				continue
+			}
+			t, err := g.describe(field.Type, d.ctx)
+			if err == nil {
+				err = g.bindField(t, protoField)
+			}
+			if err != nil {
+				return fmt.Errorf("failed mapping %s.%s.%s to %s.%s - %s",
+					d.ctx.pkg.importPath, d.name, name, message.name, protoField.name, err.Error())
+			}
+			b.fields = append(b.fields, &fieldBinding{name: name, expr: field.Type, field: protoField})
+		}
+	}
+	sort.Slice(b.fields, func(i, j int) bool { return b.fields[i].field.number < b.fields[j].field.number })
+
+	existing, found := g.bindings[d]
+	if !found {
+		g.bindings[d] = b
+		return nil
+	}
+	if len(existing.fields) != len(b.fields) {
+		return fmt.Errorf("%s.%s is mapped to both %s and %s having different fields",
+			d.ctx.pkg.importPath, d.name, existing.message.name, message.name)
+	}
+	for i, f := range existing.fields {
+		if f.name != b.fields[i].name || *f.field != *b.fields[i].field {
+			return fmt.Errorf("%s.%s is mapped to both %s and %s encoding %s differently",
+				d.ctx.pkg.importPath, d.name, existing.message.name, message.name, f.name)
+		}
+	}
+	return nil
+}
+
+// Returns the name the generated file refers to the package by, importing it
+func (g *generator) importName(importPath string) string {
+	if name, found := g.imports[importPath]; found {
+		return name
+	}
+	name := path.Base(importPath)
+	for _, importedName := range g.imports {
+		if importedName == name {
+			name = path.Base(path.Dir(importPath)) + name
+		}
+	}
+	g.imports[importPath] = name
+	return name
+}
+
+// Returns the type expression in the generated file for a type expression of the context
+func (g *generator) typeString(expr ast.Expr, ctx *typeContext) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if ctx.pkg == g.pkg || types.Universe.Lookup(e.Name) != nil {
+			return e.Name
+		}
+		return g.importName(ctx.pkg.importPath) + "." + e.Name
+	case *ast.SelectorExpr:
+		importPath := ctx.imports[e.X.(*ast.Ident).Name]
+		if importPath == g.pkg.importPath {
+			return e.Sel.Name
+		}
+		return g.importName(importPath) + "." + e.Sel.Name
+	case *ast.StarExpr:
+		return "*" + g.typeString(e.X, ctx)
+	case *ast.ArrayType:
+		return "[]" + g.typeString(e.Elt, ctx)
+	case *ast.MapType:
+		return "map[" + g.typeString(e.Key, ctx) + "]" + g.typeString(e.Value, ctx)
+	}
+	return types.ExprString(expr)
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(g.buf, format, args...)
+}
+
+// Decoder and encoder methods of the Go scalar kinds
+var scalarMethods = map[string]string{
+	"string": "String", "bool": "Bool", "int": "Int", "int32": "Int", "int64": "Int", "float64": "Double", "bytes": "Bytes",
+}
+
+// Returns the expression decoding a scalar of the Go type
+func (g *generator) decodeScalar(t *goType, decoder string) string {
+	value := decoder + "." + scalarMethods[t.kind] + "()"
+	if t.isNamed() || t.kind == "int" || t.kind == "int32" {
+		return g.typeString(t.expr, t.ctx) + "(" + value + ")"
+	}
+	return value
+}
+
+// Returns the expression converting a scalar of the Go type to the type the encoder takes
+func encodeScalar(t *goType, value string) string {
+	switch t.kind {
+	case "int", "int32":
+		return "int64(" + value + ")"
+	case "bytes":
+		if t.isNamed() {
+			return "[]byte(" + value + ")"
+		}
+	default:
+		if t.isNamed() {
+			return t.kind + "(" + value + ")"
+		}
+	}
+	return value
+}
+
+func (g *generator) writeDecodeField(f *fieldBinding, ctx *typeContext) {
+	t, _ := g.describe(f.expr, ctx)
+	target := "m." + f.name
+	switch t.kind {
+	case "message":
+		g.printf("d.Message(&%s)\n", target)
+	case "pointer":
+		if t.elem.kind == "message" {
+			g.printf("%s = new(%s)\nd.Message(%s)\n", target, g.typeString(t.elem.expr, ctx), target)
+		} else {
+			g.printf("value := %s\n%s = &value\n", g.decodeScalar(t.elem, "d"), target)
+		}
+	case "slice":
+		elem := t.elem
+		switch {
+		case elem.kind == "message":
+			g.printf("%s = append(%s, %s{})\nd.Message(&%s[len(%s)-1])\n",
+				target, target, g.typeString(elem.expr, ctx), target, target)
+		case elem.kind == "pointer":
+			g.printf("value := new(%s)\nd.Message(value)\n%s = append(%s, value)\n",
+				g.typeString(elem.elem.expr, ctx), target, target)
+		case elem.kind == "string" || elem.kind == "bytes":
+			g.printf("%s = append(%s, %s)\n", target, target, g.decodeScalar(elem, "d"))
+		default:
+			// Repeated numbers may be packed
+			conversion := g.typeString(elem.expr, ctx)
+			if elem.kind == "bool" {
+				g.printf("for _, value := range d.Ints() {\n%s = append(%s, %s(value != 0))\n}\n", target, target, conversion)
+			} else {
+				g.printf("for _, value := range d.Ints() {\n%s = append(%s, %s(value))\n}\n", target, target, conversion)
+			}
+		}
+	case "map":
+		g.printf("if %s == nil {\n%s = make(%s)\n}\n", target, target, g.typeString(f.expr, ctx))
+		key := "key"
+		if t.key.isNamed() {
+			key = g.typeString(t.key.expr, ctx) + "(key)"
+		}
+		switch t.elem.kind {
+		case "message":
+			g.printf("var value %s\nkey := d.MessageEntry(&value)\n%s[%s] = value\n", g.typeString(t.elem.expr, ctx), target, key)
+		case "bytes":
+			g.printf("key, value := d.BytesEntry()\n%s[%s] = %s\n", target, key, g.typeString(t.elem.expr, ctx)+"(value)")
+		default:
+			value := "value"
+			if t.elem.isNamed() {
+				value = g.typeString(t.elem.expr, ctx) + "(value)"
+			}
+			g.printf("key, value := d.StringEntry()\n%s[%s] = %s\n", target, key, value)
+		}
+	default:
+		g.printf("%s = %s\n", target, g.decodeScalar(t, "d"))
+	}
+}
+
+func (g *generator) writeEncodeField(f *fieldBinding, ctx *typeContext) {
+	t, _ := g.describe(f.expr, ctx)
+	number := f.field.number
+	value := "m." + f.name
+	switch t.kind {
+	case "message":
+		g.printf("e.Message(%d, &%s)\n", number, value)
+	case "pointer":
+		if t.elem.kind == "message" {
+			g.printf("if %s != nil {\ne.Message(%d, %s)\n}\n", value, number, value)
+		} else {
+			g.printf("if %s != nil {\ne.%s(%d, %s)\n}\n",
+				value, scalarMethods[t.elem.kind], number, encodeScalar(t.elem, "*"+value))
+		}
+	case "slice":
+		switch t.elem.kind {
+		case "message":
+			g.printf("for i := range %s {\ne.Message(%d, &%s[i])\n}\n", value, number, value)
+		case "pointer":
+			g.printf("for _, value := range %s {\ne.Message(%d, value)\n}\n", value, number)
+		default:
+			g.printf("for _, value := range %s {\ne.%s(%d, %s)\n}\n",
+				value, scalarMethods[t.elem.kind], number, encodeScalar(t.elem, "value"))
+		}
+	case "map":
+		g.importName("sort")
+		key := "key"
+		if t.key.isNamed() {
+			key = g.typeString(t.key.expr, ctx) + "(key)"
+		}
+		g.printf("if len(%s) > 0 {\nkeys := make([]string, 0, len(%s))\n", value, value)
+		g.printf("for key := range %s {\nkeys = append(keys, string(key))\n}\n", value)
+		g.printf("sort.Strings(keys)\nfor _, key := range keys {\nvalue := %s[%s]\n", value, key)
+		switch t.elem.kind {
+		case "message":
+			g.printf("e.MessageEntry(%d, key, &value)\n", number)
+		case "bytes":
+			g.printf("e.BytesEntry(%d, key, %s)\n", number, encodeScalar(t.elem, "value"))
+		default:
+			g.printf("e.StringEntry(%d, key, %s)\n", number, encodeScalar(t.elem, "value"))
+		}
+		g.printf("}\n}\n")
+	case "bytes":
+		g.printf("if %s != nil {\ne.Bytes(%d, %s)\n}\n", value, number, encodeScalar(t, value))
+	default:
+		g.printf("e.%s(%d, %s)\n", scalarMethods[t.kind], number, encodeScalar(t, value))
+	}
+}
+
+func (g *generator) writeMethods(b *binding) {
+	d := b.decl
+	g.printf("// UnmarshalProtobuf decodes the %s message, fields the type doesn't have are skipped\n", shortName(b.message.name))
+	g.printf("func (m *%s) UnmarshalProtobuf(data []byte) error {\nd := protobuf.NewDecoder(data)\nfor d.Next() {\n", d.name)
+	if len(b.fields) == 0 {
+		g.printf("d.Skip()\n")
+	} else {
+		g.printf("switch d.Field() {\n")
+		for _, f := range b.fields {
+			g.printf("case %d:\n", f.field.number)
+			g.writeDecodeField(f, d.ctx)
+		}
+		g.printf("default:\nd.Skip()\n}\n")
+	}
+	g.printf("}\nreturn d.Err()\n}\n\n")
+
+	g.printf("// MarshalProtobuf encodes the %s message\n", shortName(b.message.name))
+	g.printf("func (m *%s) MarshalProtobuf(e *protobuf.Encoder) {\n", d.name)
+	for _, f := range b.fields {
+		g.writeEncodeField(f, d.ctx)
+	}
+	g.printf("}\n\n")
+}
+
+// Returns the message name without its package
+func shortName(messageName string) string {
+	return messageName[strings.LastIndex(messageName, ".")+1:]
+}
+
+// Returns the formatted source of the protobuf methods of the types of the package mapped to messages
+func (g *generator) generate(pkg *typesPackage) ([]byte, error) {
+	g.pkg = pkg
+	g.imports = map[string]string{protobufImportPath: "protobuf"}
+	g.buf = &bytes.Buffer{}
+	for _, d := range pkg.decls {
+		if b, found := g.bindings[d]; found {
+			g.writeMethods(b)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "// Code generated by protogen. DO NOT EDIT.\n\npackage %s\n\n", pkg.name)
+	importPaths := make([]string, 0, len(g.imports))
+	for importPath := range g.imports {
+		importPaths = append(importPaths, importPath)
+	}
+	sort.Strings(importPaths)
+	buf.WriteString("import (\n")
+	for _, importPath := range importPaths {
+		if name := g.imports[importPath]; name != path.Base(importPath) {
+			fmt.Fprintf(buf, "%s %q\n", name, importPath)
+		} else {
+			fmt.Fprintf(buf, "%q\n", importPath)
+		}
+	}
+	buf.WriteString(")\n\n")
+	buf.Write(g.buf.Bytes())
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed formatting protobuf methods of %s - %s", pkg.importPath, err.Error())
+	}
+	return source, nil
+}
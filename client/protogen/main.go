@@ -0,0 +1,103 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+
+// Command protogen generates the MarshalProtobuf and UnmarshalProtobuf methods encoding the api types in the
+// kubernetes protobuf wire format, see the protobuf package. The field numbers are taken from the messages of the
+// generated.proto files of kubernetes, a subset of which is checked in as core.proto and meta.proto.
+// Run go generate in this directory after changing the types or config.json
+package main
+
+//go:generate go run . -config config.json
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Name of the generated file in every package
+const generatedFileName = "protobuf_generated.go"
+
+// Returns the generated source files by file name, relative to the working directory
+func generateAll(configFileName string) (map[string][]byte, error) {
+	b, err := ioutil.ReadFile(configFileName)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &config{}
+	if err = json.Unmarshal(b, cfg); err != nil {
+		return nil, fmt.Errorf("failed parsing %s - %s", configFileName, err.Error())
+	}
+	baseDir := filepath.Dir(configFileName)
+	messages := make(map[string]*protoMessage)
+	for _, protoFileName := range cfg.Protos {
+		if err = parseProtoFile(filepath.Join(baseDir, protoFileName), messages); err != nil {
+			return nil, err
+		}
+	}
+
+	g := newGenerator(cfg, baseDir, messages)
+	messageNames := make([]string, 0, len(cfg.Messages))
+	for messageName := range cfg.Messages {
+		messageNames = append(messageNames, messageName)
+	}
+	sort.Strings(messageNames)
+	for _, messageName := range messageNames {
+		message, found := messages[messageName]
+		if !found {
+			return nil, fmt.Errorf("message %s is not declared by the proto files", messageName)
+		}
+		qualifiedName := cfg.Messages[messageName]
+		dot := strings.LastIndex(qualifiedName, ".")
+		if dot < 0 {
+			return nil, fmt.Errorf("invalid type %s of message %s", qualifiedName, messageName)
+		}
+		pkg, err := g.loadPackage(qualifiedName[:dot])
+		if err != nil {
+			return nil, err
+		}
+		if pkg == nil || pkg.types[qualifiedName[dot+1:]] == nil {
+			return nil, fmt.Errorf("unknown type %s of message %s", qualifiedName, messageName)
+		}
+		if err = g.bind(pkg.types[qualifiedName[dot+1:]], message); err != nil {
+			return nil, err
+		}
+	}
+
+	files := make(map[string][]byte)
+	for _, pkg := range g.packages {
+		hasBindings := false
+		for _, d := range pkg.decls {
+			hasBindings = hasBindings || g.bindings[d] != nil
+		}
+		if !hasBindings {
+			continue
+		}
+		source, err := g.generate(pkg)
+		if err != nil {
+			return nil, err
+		}
+		files[filepath.Join(pkg.dir, generatedFileName)] = source
+	}
+	return files, nil
+}
+
+func main() {
+	configFileName := flag.String("config", "config.json", "generator configuration")
+	flag.Parse()
+
+	files, err := generateAll(*configFileName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for fileName, source := range files {
+		if err = ioutil.WriteFile(fileName, source, 0644); err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("generated %s\n", fileName)
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Scalar value types of protocol buffers fields, all other types are messages
+var scalarTypes = map[string]bool{
+	"string": true, "bytes": true, "bool": true, "double": true, "float": true,
+	"int32": true, "int64": true, "uint32": true, "uint64": true, "sint32": true, "sint64": true,
+}
+
+type protoField struct {
+	name   string
+	number int
+
+	// optional, repeated or map
+	label string
+
+	// Scalar type name or fully qualified message name of the value
+	typeName string
+}
+
+func (f *protoField) isScalar() bool {
+	return scalarTypes[f.typeName]
+}
+
+type protoMessage struct {
+	// Fully qualified name, e.g. k8s.io.api.core.v1.Pod
+	name   string
+	fields map[string]*protoField
+}
+
+// Tokenizes a proto file, dropping comments. Identifiers keep their dots, e.g. .k8s.io.api.core.v1.Pod
+func tokenize(src string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case strings.HasPrefix(src[i:], "//"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated comment")
+			}
+			i += end + 4
+		case unicode.IsSpace(rune(c)):
+			i++
+		case c == '"':
+			end := strings.IndexByte(src[i+1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			tokens = append(tokens, src[i:i+end+2])
+			i += end + 2
+		case c == '.' || c == '_' || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c)):
+			start := i
+			for i < len(src) && (src[i] == '.' || src[i] == '_' || unicode.IsLetter(rune(src[i])) || unicode.IsDigit(rune(src[i]))) {
+				i++
+			}
+			tokens = append(tokens, src[start:i])
+		default:
+			tokens = append(tokens, string(c))
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+type protoParser struct {
+	fileName string
+	tokens   []string
+	pos      int
+	pkg      string
+}
+
+func (p *protoParser) next() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	p.pos++
+	return p.tokens[p.pos-1]
+}
+
+func (p *protoParser) expect(token string) error {
+	if t := p.next(); t != token {
+		return fmt.Errorf("%s: expected %q, found %q", p.fileName, token, t)
+	}
+	return nil
+}
+
+// Skips the tokens up to and including the end of the statement
+func (p *protoParser) skipStatement() {
+	for t := p.next(); t != ";" && t != ""; t = p.next() {
+	}
+}
+
+// Returns the fully qualified name of a type referred to from the package of the file
+func (p *protoParser) qualify(typeName string) string {
+	if scalarTypes[typeName] {
+		return typeName
+	}
+	if strings.HasPrefix(typeName, ".") {
+		return typeName[1:]
+	}
+	return p.pkg + "." + typeName
+}
+
+func (p *protoParser) parseField(label string, message *protoMessage) error {
+	field := &protoField{label: label}
+	if label == "map" {
+		if err := p.expect("<"); err != nil {
+			return err
+		}
+		if key := p.next(); key != "string" {
+			return fmt.Errorf("%s: unsupported map key type %s in %s", p.fileName, key, message.name)
+		}
+		if err := p.expect(","); err != nil {
+			return err
+		}
+		field.typeName = p.qualify(p.next())
+		if err := p.expect(">"); err != nil {
+			return err
+		}
+	} else {
+		field.typeName = p.qualify(p.next())
+	}
+	field.name = p.next()
+	if err := p.expect("="); err != nil {
+		return err
+	}
+	number, err := strconv.Atoi(p.next())
+	if err != nil {
+		return fmt.Errorf("%s: invalid number of field %s.%s", p.fileName, message.name, field.name)
+	}
+	field.number = number
+	// Field options, e.g. [packed = true], don't change the encoding the decoder accepts
+	p.skipStatement()
+	message.fields[field.name] = field
+	return nil
+}
+
+func (p *protoParser) parseMessage() (*protoMessage, error) {
+	message := &protoMessage{name: p.pkg + "." + p.next(), fields: make(map[string]*protoField)}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	for {
+		switch t := p.next(); t {
+		case "}":
+			return message, nil
+		case "optional", "required", "repeated":
+			if t == "required" {
+				t = "optional"
+			}
+			if err := p.parseField(t, message); err != nil {
+				return nil, err
+			}
+		case "map":
+			if err := p.parseField("map", message); err != nil {
+				return nil, err
+			}
+		case "option", "reserved":
+			p.skipStatement()
+		default:
+			return nil, fmt.Errorf("%s: unsupported %q in message %s", p.fileName, t, message.name)
+		}
+	}
+}
+
+// Parses the messages of a proto file, nested declarations, enums and services are not supported
+func parseProtoFile(fileName string, messages map[string]*protoMessage) error {
+	src, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return err
+	}
+	tokens, err := tokenize(string(src))
+	if err != nil {
+		return fmt.Errorf("%s: %s", fileName, err.Error())
+	}
+	p := &protoParser{fileName: fileName, tokens: tokens}
+	for p.pos < len(p.tokens) {
+		switch t := p.next(); t {
+		case "package":
+			p.pkg = p.next()
+			p.skipStatement()
+		case "syntax", "import", "option":
+			p.skipStatement()
+		case "message":
+			if p.pkg == "" {
+				return fmt.Errorf("%s: message declared before the package", fileName)
+			}
+			message, err := p.parseMessage()
+			if err != nil {
+				return err
+			}
+			messages[message.name] = message
+		default:
+			return fmt.Errorf("%s: unsupported %q", fileName, t)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testProto = `
+syntax = 'proto2';
+
+// Comments are skipped
+package example.widgets;
+
+message Widget {
+  optional Meta metadata = 1;
+  repeated Part parts = 2;
+  map<string, string> labels = 3;
+  optional int32 replicas = 4;
+  optional Stamp created = 5;
+  repeated int64 groups = 6 [packed = true];
+  optional string phase = 7;
+  /* Fields the type doesn't have are skipped */
+  optional string unknown = 8;
+  map<string, .example.widgets.Part> spares = 9;
+}
+
+message Meta {
+  optional string name = 1;
+}
+
+message Part {
+  optional string name = 1;
+  optional bool ready = 2;
+}
+
+message Stamp {
+  optional int64 seconds = 1;
+}
+`
+
+const testTypes = `package widgets
+
+import "ocopea/kubernetes/client/protobuf"
+
+type Phase string
+
+type Stamp struct {
+	Seconds int64
+}
+
+func (s *Stamp) UnmarshalProtobuf(data []byte) error {
+	return nil
+}
+
+func (s *Stamp) MarshalProtobuf(e *protobuf.Encoder) {
+}
+
+type Meta struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+
+type Widget struct {
+	Meta     ` + "`json:\"metadata\"`" + `
+	Parts    []Part           ` + "`json:\"parts\"`" + `
+	Labels   map[string]string ` + "`json:\"labels,omitempty\"`" + `
+	Replicas *int              ` + "`json:\"replicas\"`" + `
+	Created  Stamp             ` + "`json:\"created\"`" + `
+	Groups   []int64           ` + "`json:\"groups\"`" + `
+	Phase    Phase             ` + "`json:\"phase\"`" + `
+	Spares   map[string]Part   ` + "`json:\"spares\"`" + `
+}
+
+type Part struct {
+	Name  string ` + "`json:\"name\"`" + `
+	Ready bool   ` + "`json:\"ready\"`" + `
+}
+`
+
+// Writes the test proto, types and the configuration mapping the given messages to a temporary directory
+func writeTestPackage(t *testing.T, messages string) string {
+	dir, err := ioutil.TempDir("", "protogen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Mkdir(filepath.Join(dir, "widgets"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := `{"protos": ["widgets.proto"], "messages": {` + messages + `}, "root": ".", "baseImportPath": "example"}`
+	for fileName, content := range map[string]string{
+		"config.json":      config,
+		"widgets.proto":    testProto,
+		"widgets/types.go": testTypes,
+	} {
+		if err = ioutil.WriteFile(filepath.Join(dir, fileName), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestGenerate(t *testing.T) {
+	dir := writeTestPackage(t, `"example.widgets.Widget": "example/widgets.Widget"`)
+	defer os.RemoveAll(dir)
+
+	files, err := generateAll(filepath.Join(dir, "config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	generated := string(files[filepath.Join(dir, "widgets", generatedFileName)])
+	expected := []string{
+		"case 1:\n\t\t\td.Message(&m.Meta)",
+		"m.Parts = append(m.Parts, Part{})\n\t\t\td.Message(&m.Parts[len(m.Parts)-1])",
+		"key, value := d.StringEntry()\n\t\t\tm.Labels[key] = value",
+		"value := int(d.Int())\n\t\t\tm.Replicas = &value",
+		"for _, value := range d.Ints() {\n\t\t\t\tm.Groups = append(m.Groups, int64(value))",
+		"m.Phase = Phase(d.String())",
+		"key := d.MessageEntry(&value)\n\t\t\tm.Spares[key] = value",
+		"sort.Strings(keys)\n\t\tfor _, key := range keys {\n\t\t\tvalue := m.Labels[key]\n\t\t\te.StringEntry(3, key, value)",
+		"if m.Replicas != nil {\n\t\te.Int(4, int64(*m.Replicas))",
+		"e.String(7, string(m.Phase))",
+		"func (m *Part) MarshalProtobuf(e *protobuf.Encoder) {\n\te.String(1, m.Name)\n\te.Bool(2, m.Ready)\n}",
+	}
+	for _, e := range expected {
+		if !strings.Contains(generated, e) {
+			t.Errorf("expected generated source to contain %q, got\n%s", e, generated)
+		}
+	}
+	// Hand written methods are kept and unknown fields are left to Skip
+	for _, unexpected := range []string{"func (m *Stamp)", "case 8:", "e.String(8"} {
+		if strings.Contains(generated, unexpected) {
+			t.Errorf("unexpected %s in generated source\n%s", unexpected, generated)
+		}
+	}
+}
+
+func TestGenerateMismatchingType(t *testing.T) {
+	dir := writeTestPackage(t, `"example.widgets.Part": "example/widgets.Meta"`)
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "widgets", "types.go"),
+		[]byte(strings.Replace(testTypes, "Name string", "Name int", 1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := generateAll(filepath.Join(dir, "config.json"))
+	if err == nil || !strings.Contains(err.Error(), "int can't be encoded as string") {
+		t.Errorf("expected generating the methods of a type not matching its message to fail, got %v", err)
+	}
+}
+
+func TestGenerateUnknownMessage(t *testing.T) {
+	dir := writeTestPackage(t, `"example.widgets.Gadget": "example/widgets.Widget"`)
+	defer os.RemoveAll(dir)
+
+	_, err := generateAll(filepath.Join(dir, "config.json"))
+	if err == nil || !strings.Contains(err.Error(), "example.widgets.Gadget") {
+		t.Errorf("expected generating the methods of an undeclared message to fail, got %v", err)
+	}
+}
+
+// The checked in protobuf methods must match the checked in types, run go generate after changing them
+func TestGeneratedMethodsUpToDate(t *testing.T) {
+	files, err := generateAll("config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for fileName, source := range files {
+		existing, err := ioutil.ReadFile(fileName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(existing, source) {
+			t.Errorf("%s is out of date, run go generate in client/protogen", fileName)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"ocopea/kubernetes/client/v1"
+	"strings"
+)
+
+// Returns the api server path proxying to the named service or pod of the client namespace,
+// e.g. /api/v1/namespaces/ocopea/services/orcs:8080/proxy. The port is optional, it may be a port name or number
+func (c *Client) proxyPath(resource string, name string, port string) string {
+	if port != "" {
+		name = name + ":" + port
+	}
+	return "/api/v1/namespaces/" + c.Namespace + "/" + resource + "/" + name + "/proxy"
+}
+
+// Returns the url of the service through the api server proxy, e.g. for printing an address of services that are
+// not exposed outside the cluster. Requests to the url must carry the credentials of the api server
+func (c *Client) ServiceProxyURL(serviceName string, port string) string {
+	return strings.TrimSuffix(c.Url, "/") + c.proxyPath("services", serviceName, port)
+}
+
+// proxyTransport sends requests through the proxy subresource of a service or pod
+type proxyTransport struct {
+	client    *Client
+	proxyPath string
+}
+
+func (t *proxyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	apiServer, err := url.Parse(t.client.Url)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing api server url %s - %s", t.client.Url, err.Error())
+	}
+
+	// Round trippers should not modify the request, so sending a copy addressed to the api server
+	proxied := copyRequest(req)
+	proxied.URL.Scheme = apiServer.Scheme
+	proxied.URL.Host = apiServer.Host
+	proxied.URL.Path = strings.TrimSuffix(apiServer.Path, "/") + t.proxyPath + "/" + strings.TrimPrefix(req.URL.Path, "/")
+	proxied.URL.RawPath = ""
+	proxied.Host = ""
+	if err = t.client.authenticate(proxied); err != nil {
+		return nil, err
+	}
+
+	transport := t.client.httpClient.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return transport.RoundTrip(proxied)
+}
+
+// Returns a shallow copy of the request with its own url and headers, which can be changed without affecting the request
+func copyRequest(req *http.Request) *http.Request {
+	proxied := new(http.Request)
+	*proxied = *req
+	proxiedUrl := *req.URL
+	proxied.URL = &proxiedUrl
+	proxied.Header = make(http.Header, len(req.Header))
+	for name, values := range req.Header {
+		proxied.Header[name] = append([]string(nil), values...)
+	}
+	return proxied
+}
+
+// Returns a round tripper sending requests to the service through the api server proxy, so services that are not
+// exposed outside the cluster (e.g. ClusterIP services) can be reached. The scheme and host of the requests are
+// ignored, e.g. GET http://orcs/hub-web-api/site is sent to the hub-web-api/site path of the service.
+// The api server consumes the Authorization header rather than forwarding it, so services authenticating requests
+// with it (e.g. basic auth) can't be reached this way. When the client has credentials those replace any
+// Authorization header of the request
+func (c *Client) ServiceProxyTransport(serviceName string, port string) http.RoundTripper {
+	return &proxyTransport{client: c, proxyPath: c.proxyPath("services", serviceName, port)}
+}
+
+// Returns a round tripper sending requests to the pod through the api server proxy, see ServiceProxyTransport
+func (c *Client) PodProxyTransport(podName string, port string) http.RoundTripper {
+	return &proxyTransport{client: c, proxyPath: c.proxyPath("pods", podName, port)}
+}
+
+// Sends a request to the given path of the service through the api server proxy
+func (c *Client) ProxyService(method string, serviceName string, port string, path string, body io.Reader) (*http.Response, error) {
+	resp, err := c.doHttpPath(
+		method, c.proxyPath("services", serviceName, port)+"/"+strings.TrimPrefix(path, "/"), body, "application/json")
+	if err != nil {
+		return nil, fmt.Errorf("Failed proxying %s request to service %s - %s", method, serviceName, err.Error())
+	}
+	return resp, nil
+}
+
+// Sends a request to the pod through the api server proxy, the path of the request is taken from the options
+func (c *Client) ProxyPod(method string, podName string, port string, options *v1.PodProxyOptions, body io.Reader) (*http.Response, error) {
+	path := ""
+	if options != nil {
+		path = options.Path
+	}
+	resp, err := c.doHttpPath(
+		method, c.proxyPath("pods", podName, port)+"/"+strings.TrimPrefix(path, "/"), body, "application/json")
+	if err != nil {
+		return nil, fmt.Errorf("Failed proxying %s request to pod %s - %s", method, podName, err.Error())
+	}
+	return resp, nil
+}
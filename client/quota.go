@@ -0,0 +1,151 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"fmt"
+	"ocopea/kubernetes/client/inf"
+	"ocopea/kubernetes/client/resource"
+	"ocopea/kubernetes/client/v1"
+	"sort"
+)
+
+// ResourceUsage summarizes the usage of a single resource tracked by a resource quota
+type ResourceUsage struct {
+	Name      v1.ResourceName
+	Used      resource.Quantity
+	Hard      resource.Quantity
+	Remaining resource.Quantity
+
+	// Percentage of the hard limit already used, may exceed 100 in case quota was lowered after usage
+	UsedPercentage int64
+}
+
+// Returns true in case usage reached the hard limit of the resource
+func (u *ResourceUsage) Exhausted() bool {
+	return u.Used.Cmp(u.Hard) >= 0
+}
+
+func (c *Client) CreateResourceQuota(quota *v1.ResourceQuota, force bool) (*v1.ResourceQuota, error) {
+	respQuota := &v1.ResourceQuota{}
+	err := c.createEntity("resourcequotas", quota.Name, quota, respQuota, force)
+	return respQuota, err
+}
+
+func (c *Client) GetResourceQuota(quotaName string) (*v1.ResourceQuota, error) {
+	quota := &v1.ResourceQuota{}
+	err := c.getEntityInfo("resourcequotas", quotaName, quota)
+	return quota, err
+}
+
+func (c *Client) ListResourceQuotas(labelFilters map[string]string) ([]*v1.ResourceQuota, error) {
+	respQuotaList := &v1.ResourceQuotaList{}
+	err := c.getEntityInfo("resourcequotas"+buildLabelsQueryString(labelFilters), "", respQuotaList)
+	if err != nil {
+		return nil, fmt.Errorf("Failed listing k8s resource quotas - %s", err.Error())
+	}
+	quotaList := make([]*v1.ResourceQuota, 0)
+	if respQuotaList.Items != nil {
+		for i := range respQuotaList.Items {
+			quotaList = append(quotaList, &respQuotaList.Items[i])
+		}
+	}
+	return quotaList, nil
+}
+
+func (c *Client) UpdateResourceQuota(quota *v1.ResourceQuota) (*v1.ResourceQuota, error) {
+	respQuota := &v1.ResourceQuota{}
+	err := c.updateEntity("resourcequotas", quota.Name, quota, respQuota)
+	return respQuota, err
+}
+
+func (c *Client) DeleteResourceQuota(quotaName string) error {
+	return c.deleteEntity("namespaces/" + c.Namespace + "/" + "resourcequotas/" + quotaName)
+}
+
+func (c *Client) CreateLimitRange(limitRange *v1.LimitRange, force bool) (*v1.LimitRange, error) {
+	respLimitRange := &v1.LimitRange{}
+	err := c.createEntity("limitranges", limitRange.Name, limitRange, respLimitRange, force)
+	return respLimitRange, err
+}
+
+func (c *Client) GetLimitRange(limitRangeName string) (*v1.LimitRange, error) {
+	limitRange := &v1.LimitRange{}
+	err := c.getEntityInfo("limitranges", limitRangeName, limitRange)
+	return limitRange, err
+}
+
+func (c *Client) ListLimitRanges(labelFilters map[string]string) ([]*v1.LimitRange, error) {
+	respLimitRangeList := &v1.LimitRangeList{}
+	err := c.getEntityInfo("limitranges"+buildLabelsQueryString(labelFilters), "", respLimitRangeList)
+	if err != nil {
+		return nil, fmt.Errorf("Failed listing k8s limit ranges - %s", err.Error())
+	}
+	limitRangeList := make([]*v1.LimitRange, 0)
+	if respLimitRangeList.Items != nil {
+		for i := range respLimitRangeList.Items {
+			limitRangeList = append(limitRangeList, &respLimitRangeList.Items[i])
+		}
+	}
+	return limitRangeList, nil
+}
+
+func (c *Client) UpdateLimitRange(limitRange *v1.LimitRange) (*v1.LimitRange, error) {
+	respLimitRange := &v1.LimitRange{}
+	err := c.updateEntity("limitranges", limitRange.Name, limitRange, respLimitRange)
+	return respLimitRange, err
+}
+
+func (c *Client) DeleteLimitRange(limitRangeName string) error {
+	return c.deleteEntity("namespaces/" + c.Namespace + "/" + "limitranges/" + limitRangeName)
+}
+
+// Summarizes the usage versus the hard limit of every resource tracked by the quota, sorted by resource name.
+// The enforced hard limits reported in the quota status are used, falling back to the spec when the quota
+// was not yet observed by the server
+func SummarizeResourceQuotaUsage(quota *v1.ResourceQuota) []ResourceUsage {
+	hardLimits := quota.Status.Hard
+	if len(hardLimits) == 0 {
+		hardLimits = quota.Spec.Hard
+	}
+
+	resourceNames := make([]string, 0, len(hardLimits))
+	for name := range hardLimits {
+		resourceNames = append(resourceNames, string(name))
+	}
+	sort.Strings(resourceNames)
+
+	summary := make([]ResourceUsage, 0, len(resourceNames))
+	for _, name := range resourceNames {
+		hard := hardLimits[v1.ResourceName(name)]
+		hardCopy := hard.Copy()
+
+		used := resource.NewQuantity(0, hardCopy.Format)
+		if usedQuantity, ok := quota.Status.Used[v1.ResourceName(name)]; ok {
+			used = usedQuantity.Copy()
+		}
+
+		// Using the amounts directly since Sub fails on quantities of different formats
+		remaining := hard.Copy()
+		remaining.Amount.Sub(remaining.Amount, used.Amount)
+
+		summary = append(summary, ResourceUsage{
+			Name:           v1.ResourceName(name),
+			Used:           *used,
+			Hard:           *hardCopy,
+			Remaining:      *remaining,
+			UsedPercentage: usedPercentage(used, hardCopy),
+		})
+	}
+	return summary
+}
+
+// Computes the used percentage of the hard limit, rounded down.
+// Using the decimal amounts since the milli values of large quantities overflow int64 when multiplied by 100
+func usedPercentage(used *resource.Quantity, hard *resource.Quantity) int64 {
+	if hard.Amount.Sign() <= 0 {
+		return 0
+	}
+	hundredTimesUsed := new(inf.Dec).Mul(used.Amount, inf.NewDec(100, 0))
+	percentage := new(inf.Dec).QuoRound(hundredTimesUsed, hard.Amount, 0, inf.RoundDown)
+	return percentage.UnscaledBig().Int64()
+}
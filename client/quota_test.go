@@ -0,0 +1,84 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"ocopea/kubernetes/client/resource"
+	"ocopea/kubernetes/client/v1"
+	"testing"
+)
+
+func TestSummarizeResourceQuotaUsage(t *testing.T) {
+	quota := &v1.ResourceQuota{
+		Status: v1.ResourceQuotaStatus{
+			Hard: v1.ResourceList{
+				v1.ResourceMemory: resource.MustParse("2Gi"),
+				v1.ResourcePods:   resource.MustParse("10"),
+				v1.ResourceCPU:    resource.MustParse("2"),
+			},
+			Used: v1.ResourceList{
+				v1.ResourceMemory: resource.MustParse("512Mi"),
+				v1.ResourcePods:   resource.MustParse("10"),
+			},
+		},
+	}
+
+	summary := SummarizeResourceQuotaUsage(quota)
+	if len(summary) != 3 {
+		t.Fatalf("expected 3 resources in summary, got %d", len(summary))
+	}
+
+	expectedNames := []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory, v1.ResourcePods}
+	for i, usage := range summary {
+		if usage.Name != expectedNames[i] {
+			t.Errorf("expected resource %s at position %d, got %s", expectedNames[i], i, usage.Name)
+		}
+	}
+
+	cpu := summary[0]
+	if cpu.UsedPercentage != 0 || cpu.Exhausted() || cpu.Remaining.String() != "2" {
+		t.Errorf("unexpected cpu usage %d%%, remaining %s", cpu.UsedPercentage, cpu.Remaining.String())
+	}
+
+	memory := summary[1]
+	if memory.UsedPercentage != 25 || memory.Exhausted() || memory.Remaining.String() != "1536Mi" {
+		t.Errorf("unexpected memory usage %d%%, remaining %s", memory.UsedPercentage, memory.Remaining.String())
+	}
+
+	pods := summary[2]
+	if pods.UsedPercentage != 100 || !pods.Exhausted() {
+		t.Errorf("expected pods quota to be exhausted, got %d%%", pods.UsedPercentage)
+	}
+}
+
+func TestSummarizeResourceQuotaUsageFallsBackToSpec(t *testing.T) {
+	quota := &v1.ResourceQuota{
+		Spec: v1.ResourceQuotaSpec{
+			Hard: v1.ResourceList{
+				v1.ResourcePods: resource.MustParse("4"),
+			},
+		},
+	}
+
+	summary := SummarizeResourceQuotaUsage(quota)
+	if len(summary) != 1 || summary[0].Hard.String() != "4" || summary[0].Used.String() != "0" {
+		t.Errorf("unexpected summary for unobserved quota %v", summary)
+	}
+}
+
+func TestSummarizeResourceQuotaUsageOfLargeQuantities(t *testing.T) {
+	quota := &v1.ResourceQuota{
+		Status: v1.ResourceQuotaStatus{
+			Hard: v1.ResourceList{
+				v1.ResourceStorage: resource.MustParse("4Ei"),
+			},
+			Used: v1.ResourceList{
+				v1.ResourceStorage: resource.MustParse("3Ei"),
+			},
+		},
+	}
+
+	summary := SummarizeResourceQuotaUsage(quota)
+	if len(summary) != 1 || summary[0].UsedPercentage != 75 {
+		t.Errorf("expected 75%% of storage used, got %v", summary)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"math/big"
 	"ocopea/kubernetes/client/inf"
+	"ocopea/kubernetes/client/protobuf"
 	"regexp"
 	"strings"
 )
@@ -350,6 +351,33 @@ func (q *Quantity) UnmarshalJSON(value []byte) error {
 	return nil
 }
 
+// UnmarshalProtobuf decodes the Quantity message holding the quantity as a string, see UnmarshalJSON
+func (q *Quantity) UnmarshalProtobuf(data []byte) error {
+	var str string
+	d := protobuf.NewDecoder(data)
+	for d.Next() {
+		if d.Field() == 1 {
+			str = d.String()
+		} else {
+			d.Skip()
+		}
+	}
+	if err := d.Err(); err != nil {
+		return err
+	}
+	parsed, err := ParseQuantity(str)
+	if err != nil {
+		return err
+	}
+	*q = *parsed
+	return nil
+}
+
+// MarshalProtobuf encodes the quantity as a string, see MarshalJSON
+func (q *Quantity) MarshalProtobuf(e *protobuf.Encoder) {
+	e.String(1, q.String())
+}
+
 // NewQuantity returns a new Quantity representing the given
 // value in the given format.
 func NewQuantity(value int64, format Format) *Quantity {
@@ -419,3 +447,21 @@ func (q *Quantity) Copy() *Quantity {
 		Format: q.Format,
 	}
 }
+
+// DeepCopyInto copies q into out, sharing no pointers with it. Unlike Copy, a nil amount is kept nil
+func (q *Quantity) DeepCopyInto(out *Quantity) {
+	*out = *q
+	if q.Amount != nil {
+		out.Amount = (&inf.Dec{}).Set(q.Amount)
+	}
+}
+
+// DeepCopy is used by the generated deep copy methods of the api types holding quantities
+func (q *Quantity) DeepCopy() *Quantity {
+	if q == nil {
+		return nil
+	}
+	out := &Quantity{}
+	q.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,160 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"ocopea/kubernetes/client/inf"
+	"ocopea/kubernetes/client/resource"
+	"ocopea/kubernetes/client/v1"
+	"sort"
+)
+
+// Prefix of quota resources limiting the sum of requests, e.g. requests.cpu, which is the same as cpu
+const quotaRequestsPrefix = "requests."
+
+// Returns the sum of the resource lists, resources missing from a list are considered zero.
+// Quantities are summed using their amounts directly, keeping the format of the first list a resource is found in
+func AddResourceLists(lists ...v1.ResourceList) v1.ResourceList {
+	sum := v1.ResourceList{}
+	for _, list := range lists {
+		for name, quantity := range list {
+			total, found := sum[name]
+			if !found {
+				sum[name] = *quantity.Copy()
+				continue
+			}
+			result := total.Copy()
+			result.Amount.Add(result.Amount, quantity.Copy().Amount)
+			sum[name] = *result
+		}
+	}
+	return sum
+}
+
+// Returns a minus b for every resource of a, the result may be negative.
+// Using the amounts directly since Sub fails on quantities of different formats
+func SubtractResourceLists(a v1.ResourceList, b v1.ResourceList) v1.ResourceList {
+	difference := v1.ResourceList{}
+	for name, quantity := range a {
+		result := quantity.Copy()
+		if subtracted, found := b[name]; found {
+			result.Amount.Sub(result.Amount, subtracted.Copy().Amount)
+		}
+		difference[name] = *result
+	}
+	return difference
+}
+
+// Returns the smaller quantity of every resource. A resource missing from one of the lists is not limited by
+// that list, so the quantity of the other list is used
+func MinResourceLists(a v1.ResourceList, b v1.ResourceList) v1.ResourceList {
+	return mergeResourceLists(a, b, func(x, y resource.Quantity) bool { return x.Cmp(y) <= 0 })
+}
+
+// Returns the larger quantity of every resource in any of the lists
+func MaxResourceLists(a v1.ResourceList, b v1.ResourceList) v1.ResourceList {
+	return mergeResourceLists(a, b, func(x, y resource.Quantity) bool { return x.Cmp(y) >= 0 })
+}
+
+// Merges both lists, picking the quantity of a whenever prefer returns true for resources found in both
+func mergeResourceLists(a v1.ResourceList, b v1.ResourceList, prefer func(x, y resource.Quantity) bool) v1.ResourceList {
+	merged := v1.ResourceList{}
+	for name, quantity := range b {
+		merged[name] = *quantity.Copy()
+	}
+	for name, quantity := range a {
+		if other, found := b[name]; !found || prefer(quantity, other) {
+			merged[name] = *quantity.Copy()
+		}
+	}
+	return merged
+}
+
+// Returns the list with every quantity multiplied by n, e.g. the requests of n replicas of a pod
+func MultiplyResourceList(list v1.ResourceList, n int) v1.ResourceList {
+	product := v1.ResourceList{}
+	for name, quantity := range list {
+		result := quantity.Copy()
+		result.Amount.Mul(result.Amount, inf.NewDec(int64(n), 0))
+		product[name] = *result
+	}
+	return product
+}
+
+// Returns true in case the resource list has no quantity other than zero
+func IsZeroResourceList(list v1.ResourceList) bool {
+	for _, quantity := range list {
+		if quantity.Amount != nil && quantity.Amount.Sign() != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the sum of the requests of the containers. As in kubernetes, the limit of a resource
+// is used as its request when only the limit is set
+func ContainerRequests(containers []v1.Container) v1.ResourceList {
+	requests := make([]v1.ResourceList, 0, len(containers))
+	for _, container := range containers {
+		containerRequests := v1.ResourceList{}
+		for name, limit := range container.Resources.Limits {
+			containerRequests[name] = limit
+		}
+		for name, request := range container.Resources.Requests {
+			containerRequests[name] = request
+		}
+		requests = append(requests, containerRequests)
+	}
+	return AddResourceLists(requests...)
+}
+
+// Returns the names of the resources that are requested beyond the available quantity, sorted by name.
+// Resources missing from the available list are not limited
+func InsufficientResources(requests v1.ResourceList, available v1.ResourceList) []v1.ResourceName {
+	insufficient := make([]v1.ResourceName, 0)
+	for name, requested := range requests {
+		if quantity, found := available[name]; found && requested.Cmp(quantity) > 0 {
+			insufficient = append(insufficient, name)
+		}
+	}
+	sort.Slice(insufficient, func(i, j int) bool { return insufficient[i] < insufficient[j] })
+	return insufficient
+}
+
+// Returns true in case the requests fit the allocatable resources of the node, along with the resources that
+// don't fit otherwise. Nodes that do not report allocatable resources are checked against their capacity
+func FitsNode(requests v1.ResourceList, node *v1.Node) (bool, []v1.ResourceName) {
+	available := node.Status.Allocatable
+	if len(available) == 0 {
+		available = node.Status.Capacity
+	}
+	insufficient := InsufficientResources(requests, available)
+	return len(insufficient) == 0, insufficient
+}
+
+// Returns true in case the requests fit what remains of the quota hard limits, along with the resources that
+// don't fit otherwise. Both plain resources and their requests. prefixed form are checked
+func FitsQuota(requests v1.ResourceList, quota *v1.ResourceQuota) (bool, []v1.ResourceName) {
+	remaining := v1.ResourceList{}
+	for _, usage := range SummarizeResourceQuotaUsage(quota) {
+		remaining[usage.Name] = usage.Remaining
+	}
+
+	insufficient := make(map[v1.ResourceName]bool)
+	prefixedRequests := v1.ResourceList{}
+	for name, quantity := range requests {
+		prefixedRequests[quotaRequestsPrefix+name] = quantity
+	}
+	for _, name := range InsufficientResources(requests, remaining) {
+		insufficient[name] = true
+	}
+	for _, name := range InsufficientResources(prefixedRequests, remaining) {
+		insufficient[name[len(quotaRequestsPrefix):]] = true
+	}
+
+	names := make([]v1.ResourceName, 0, len(insufficient))
+	for name := range insufficient {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return len(names) == 0, names
+}
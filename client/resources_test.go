@@ -0,0 +1,123 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"ocopea/kubernetes/client/resource"
+	"ocopea/kubernetes/client/v1"
+	"reflect"
+	"testing"
+)
+
+func quantityOf(list v1.ResourceList, name v1.ResourceName) string {
+	quantity := list[name]
+	return quantity.String()
+}
+
+func TestResourceListArithmetic(t *testing.T) {
+	a := v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("500m"),
+		v1.ResourceMemory: resource.MustParse("1Gi"),
+	}
+	b := v1.ResourceList{
+		v1.ResourceCPU:  resource.MustParse("1"),
+		v1.ResourcePods: resource.MustParse("2"),
+	}
+
+	sum := AddResourceLists(a, b)
+	if quantityOf(sum, v1.ResourceCPU) != "1500m" || quantityOf(sum, v1.ResourceMemory) != "1Gi" || quantityOf(sum, v1.ResourcePods) != "2" {
+		t.Errorf("unexpected sum %v", sum)
+	}
+	if quantityOf(a, v1.ResourceCPU) != "500m" {
+		t.Errorf("expected the summed lists to remain untouched, got %v", a)
+	}
+
+	difference := SubtractResourceLists(a, b)
+	if quantityOf(difference, v1.ResourceCPU) != "-500m" || quantityOf(difference, v1.ResourceMemory) != "1Gi" || len(difference) != 2 {
+		t.Errorf("unexpected difference %v", difference)
+	}
+
+	min := MinResourceLists(a, b)
+	max := MaxResourceLists(a, b)
+	if quantityOf(min, v1.ResourceCPU) != "500m" || quantityOf(max, v1.ResourceCPU) != "1" || len(min) != 3 || len(max) != 3 {
+		t.Errorf("unexpected min %v and max %v", min, max)
+	}
+
+	product := MultiplyResourceList(a, 3)
+	if quantityOf(product, v1.ResourceCPU) != "1500m" || quantityOf(product, v1.ResourceMemory) != "3Gi" {
+		t.Errorf("unexpected product %v", product)
+	}
+	if !IsZeroResourceList(MultiplyResourceList(a, 0)) || IsZeroResourceList(a) {
+		t.Errorf("unexpected zero checks")
+	}
+}
+
+func TestContainerRequests(t *testing.T) {
+	requests := ContainerRequests([]v1.Container{
+		{Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("250m")},
+			Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("1"), v1.ResourceMemory: resource.MustParse("256Mi")},
+		}},
+		{Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("250m"), v1.ResourceMemory: resource.MustParse("256Mi")},
+		}},
+		{},
+	})
+	if quantityOf(requests, v1.ResourceCPU) != "500m" || quantityOf(requests, v1.ResourceMemory) != "512Mi" {
+		t.Errorf("unexpected requests %v", requests)
+	}
+}
+
+func TestFitsNode(t *testing.T) {
+	node := &v1.Node{Status: v1.NodeStatus{
+		Capacity:    v1.ResourceList{v1.ResourceCPU: resource.MustParse("4"), v1.ResourceMemory: resource.MustParse("8Gi")},
+		Allocatable: v1.ResourceList{v1.ResourceCPU: resource.MustParse("3800m"), v1.ResourceMemory: resource.MustParse("7Gi")},
+	}}
+
+	requests := v1.ResourceList{v1.ResourceCPU: resource.MustParse("2"), v1.ResourceMemory: resource.MustParse("7Gi")}
+	if fits, insufficient := FitsNode(requests, node); !fits || len(insufficient) != 0 {
+		t.Errorf("expected requests to fit, got %v", insufficient)
+	}
+
+	requests = MultiplyResourceList(requests, 2)
+	fits, insufficient := FitsNode(requests, node)
+	if fits || !reflect.DeepEqual(insufficient, []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory}) {
+		t.Errorf("expected cpu and memory to be insufficient, got %v", insufficient)
+	}
+
+	node.Status.Allocatable = nil
+	if fits, _ = FitsNode(v1.ResourceList{v1.ResourceCPU: resource.MustParse("4")}, node); !fits {
+		t.Errorf("expected node capacity to be used when allocatable is missing")
+	}
+}
+
+func TestFitsQuota(t *testing.T) {
+	quota := &v1.ResourceQuota{
+		Status: v1.ResourceQuotaStatus{
+			Hard: v1.ResourceList{
+				"requests.cpu":    resource.MustParse("2"),
+				v1.ResourceMemory: resource.MustParse("2Gi"),
+				v1.ResourcePods:   resource.MustParse("4"),
+			},
+			Used: v1.ResourceList{
+				"requests.cpu":    resource.MustParse("1500m"),
+				v1.ResourceMemory: resource.MustParse("1Gi"),
+				v1.ResourcePods:   resource.MustParse("3"),
+			},
+		},
+	}
+
+	requests := v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("500m"),
+		v1.ResourceMemory: resource.MustParse("1Gi"),
+		v1.ResourcePods:   resource.MustParse("1"),
+	}
+	if fits, insufficient := FitsQuota(requests, quota); !fits {
+		t.Errorf("expected requests to fit the remaining quota, got %v", insufficient)
+	}
+
+	requests = MultiplyResourceList(requests, 2)
+	fits, insufficient := FitsQuota(requests, quota)
+	if fits || !reflect.DeepEqual(insufficient, []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory, v1.ResourcePods}) {
+		t.Errorf("expected cpu, memory and pods to exceed the quota, got %v", insufficient)
+	}
+}
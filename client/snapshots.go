@@ -0,0 +1,183 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"ocopea/kubernetes/client/resource"
+	"ocopea/kubernetes/client/v1"
+	"sort"
+	"time"
+)
+
+// CSI volume snapshots are custom resources installed along with the snapshot controller of the cluster
+var VolumeSnapshotResource = GroupVersionResource{
+	Group:    "snapshot.storage.k8s.io",
+	Version:  "v1",
+	Resource: "volumesnapshots",
+}
+
+// Builds a volume snapshot of the persistent volume claim. The snapshot class may be empty for the default class
+func NewVolumeSnapshot(name string, pvcName string, snapshotClassName string) Unstructured {
+	spec := map[string]interface{}{
+		"source": map[string]interface{}{
+			"persistentVolumeClaimName": pvcName,
+		},
+	}
+	if snapshotClassName != "" {
+		spec["volumeSnapshotClassName"] = snapshotClassName
+	}
+	return Unstructured{
+		"apiVersion": VolumeSnapshotResource.Group + "/" + VolumeSnapshotResource.Version,
+		"kind":       "VolumeSnapshot",
+		"metadata": map[string]interface{}{
+			"name": name,
+		},
+		"spec": spec,
+	}
+}
+
+func (c *Client) volumeSnapshots() *DynamicResource {
+	return c.Resource(VolumeSnapshotResource, true)
+}
+
+// Creates the volume snapshot, in force mode an already existing snapshot is returned as is
+func (c *Client) CreateVolumeSnapshot(snapshot Unstructured, force bool) (Unstructured, error) {
+	created, err := c.volumeSnapshots().Create(snapshot)
+	if err != nil {
+		if IsConflict(err) && force {
+			log.Printf("conflict creating volume snapshot %s, force mode, getting info only", snapshot.GetName())
+			return c.GetVolumeSnapshot(snapshot.GetName())
+		}
+		return nil, fmt.Errorf("Failed creating volume snapshot %s - %s", snapshot.GetName(), err.Error())
+	}
+	return created, nil
+}
+
+// Snapshots the persistent volume claim, see NewVolumeSnapshot
+func (c *Client) SnapshotPersistentVolumeClaim(pvcName string, snapshotName string, snapshotClassName string) (Unstructured, error) {
+	if _, err := c.GetPersistentVolumeClaimInfo(pvcName); err != nil {
+		return nil, fmt.Errorf("Failed snapshotting persistent volume claim %s - %s", pvcName, err.Error())
+	}
+	return c.CreateVolumeSnapshot(NewVolumeSnapshot(snapshotName, pvcName, snapshotClassName), false)
+}
+
+func (c *Client) GetVolumeSnapshot(name string) (Unstructured, error) {
+	return c.volumeSnapshots().Get(name)
+}
+
+// Lists the volume snapshots taken of the persistent volume claim sorted by creation time, or all snapshots of the
+// namespace when the claim name is empty
+func (c *Client) ListVolumeSnapshots(pvcName string) ([]Unstructured, error) {
+	snapshots, err := c.volumeSnapshots().List(nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed listing volume snapshots - %s", err.Error())
+	}
+	filtered := make([]Unstructured, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		if pvcName == "" || NestedString(snapshot, "spec", "source", "persistentVolumeClaimName") == pvcName {
+			filtered = append(filtered, snapshot)
+		}
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return NestedString(filtered[i], "metadata", "creationTimestamp") < NestedString(filtered[j], "metadata", "creationTimestamp")
+	})
+	return filtered, nil
+}
+
+func (c *Client) DeleteVolumeSnapshot(name string) error {
+	if err := c.volumeSnapshots().Delete(name); err != nil {
+		return fmt.Errorf("Failed deleting volume snapshot %s - %s", name, err.Error())
+	}
+	return nil
+}
+
+// Returns true once the snapshot is ready to restore claims from, fails in case the snapshot controller
+// reported an error taking the snapshot
+func VolumeSnapshotReady(obj interface{}) (bool, error) {
+	snapshot := obj.(Unstructured)
+	if message := NestedString(snapshot, "status", "error", "message"); message != "" {
+		return false, fmt.Errorf("volume snapshot %s failed - %s", snapshot.GetName(), message)
+	}
+	ready, _ := NestedField(snapshot, "status", "readyToUse")
+	return ready == true, nil
+}
+
+func (c *Client) WaitForVolumeSnapshotToBeReady(name string, maxRetries int, sleepDuration time.Duration) (Unstructured, error) {
+	getter := func() (interface{}, error) {
+		return c.GetVolumeSnapshot(name)
+	}
+	snapshot, err := WaitFor(context.Background(), getter, VolumeSnapshotReady, WaitOptions{
+		Description: "volume snapshot " + name + " to be ready",
+		Timeout:     time.Duration(maxRetries) * sleepDuration,
+		Interval:    sleepDuration,
+		Watch:       c.WatchTrigger(VolumeSnapshotResource, true, nil),
+		Progress:    logWaitProgress("Waiting for volume snapshot " + name + " to be ready"),
+	})
+	if IsWaitTimeout(err) {
+		return nil, fmt.Errorf("Volume snapshot %s was not ready after %d retries", name, maxRetries)
+	} else if err != nil {
+		return nil, err
+	}
+	return snapshot.(Unstructured), nil
+}
+
+// Creates a persistent volume claim populated with the data of the snapshot. The claim is based on the template,
+// by default the claim the snapshot was taken of, and requests at least the restore size of the snapshot
+func (c *Client) RestorePersistentVolumeClaim(
+	pvcName string,
+	snapshotName string,
+	template *v1.PersistentVolumeClaim) (*v1.PersistentVolumeClaim, error) {
+
+	snapshot, err := c.GetVolumeSnapshot(snapshotName)
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting volume snapshot %s - %s", snapshotName, err.Error())
+	}
+	if ready, err := VolumeSnapshotReady(snapshot); err != nil {
+		return nil, err
+	} else if !ready {
+		return nil, fmt.Errorf("Failed restoring volume snapshot %s, the snapshot is not ready yet", snapshotName)
+	}
+
+	if template == nil {
+		sourceName := NestedString(snapshot, "spec", "source", "persistentVolumeClaimName")
+		if template, err = c.GetPersistentVolumeClaimInfo(sourceName); err != nil {
+			if !IsNotFound(err) {
+				return nil, fmt.Errorf("Failed getting persistent volume claim %s - %s", sourceName, err.Error())
+			}
+			template = &v1.PersistentVolumeClaim{
+				Spec: v1.PersistentVolumeClaimSpec{AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce}},
+			}
+		}
+	}
+
+	apiGroup := VolumeSnapshotResource.Group
+	pvc := &v1.PersistentVolumeClaim{
+		ObjectMeta: v1.ObjectMeta{Name: pvcName, Labels: template.Labels},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes:      template.Spec.AccessModes,
+			StorageClassName: template.Spec.StorageClassName,
+			DataSource:       &v1.TypedLocalObjectReference{APIGroup: &apiGroup, Kind: "VolumeSnapshot", Name: snapshotName},
+		},
+	}
+	requests := v1.ResourceList{}
+	for name, quantity := range template.Spec.Resources.Requests {
+		requests[name] = quantity
+	}
+	if restoreSize := NestedString(snapshot, "status", "restoreSize"); restoreSize != "" {
+		size, err := resource.ParseQuantity(restoreSize)
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing restore size of volume snapshot %s - %s", snapshotName, err.Error())
+		}
+		if requested, found := requests[v1.ResourceStorage]; !found || requested.Cmp(*size) < 0 {
+			requests[v1.ResourceStorage] = *size
+		}
+	}
+	if _, found := requests[v1.ResourceStorage]; !found {
+		return nil, errors.New("Failed restoring volume snapshot " + snapshotName + ", the storage size of the claim is unknown")
+	}
+	pvc.Spec.Resources.Requests = requests
+	return c.CreatePersistentVolumeClaim(pvc, false)
+}
@@ -0,0 +1,267 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"ocopea/kubernetes/client/v1"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Maximal size of the output kept in a task result, older output is dropped
+const maxTaskOutputSize = 1 << 20
+
+// Time to wait for the log stream to end once the task finished
+const taskLogsDrainTimeout = 10 * time.Second
+
+// TaskOptions control how a task pod is run, zero values mean defaults
+type TaskOptions struct {
+	// Maximal time for the task to finish, 3 minutes by default
+	Timeout time.Duration
+
+	// Name of the task container, "task" by default
+	ContainerName string
+
+	// Labels of the task pod, by default labeled with the task name as app and nazKind sys
+	Labels map[string]string
+
+	Ports              []v1.ContainerPort
+	Resources          v1.ResourceRequirements
+	ServiceAccountName string
+
+	// IfNotPresent by default
+	ImagePullPolicy v1.PullPolicy
+
+	// Receives every line of the task logs as soon as it is written
+	Output LogMessageConsumer
+
+	// Leaves the pod of a failed task behind for investigation instead of deleting it
+	KeepPodOnFailure bool
+}
+
+// TaskResult describes how a task ended
+type TaskResult struct {
+	PodName string
+	Phase   v1.PodPhase
+
+	// Exit code, termination reason (e.g. Completed, Error, OOMKilled) and message of the task container
+	ExitCode int
+	Reason   string
+	Message  string
+
+	StartedAt  time.Time
+	FinishedAt time.Time
+
+	// Logs of the task, only the last megabyte of long logs is kept
+	Output string
+}
+
+func (r *TaskResult) Succeeded() bool {
+	return r.Phase == v1.PodSucceeded
+}
+
+// The error returned when a task finished unsuccessfully
+func (r *TaskResult) failure() error {
+	return fmt.Errorf("task pod %s failed with exit code %d - %s %s", r.PodName, r.ExitCode, r.Reason, r.Message)
+}
+
+// Fills the result from the state of the task container
+func (r *TaskResult) setPod(pod *v1.Pod) {
+	r.Phase = pod.Status.Phase
+	if len(pod.Status.ContainerStatuses) == 0 {
+		return
+	}
+	if terminated := pod.Status.ContainerStatuses[0].State.Terminated; terminated != nil {
+		r.ExitCode = terminated.ExitCode
+		r.Reason = terminated.Reason
+		r.Message = terminated.Message
+		r.StartedAt = terminated.StartedAt.Time
+		r.FinishedAt = terminated.FinishedAt.Time
+	} else if running := pod.Status.ContainerStatuses[0].State.Running; running != nil {
+		r.StartedAt = running.StartedAt.Time
+	}
+}
+
+func (o TaskOptions) withDefaults(name string) TaskOptions {
+	if o.Timeout <= 0 {
+		o.Timeout = 3 * time.Minute
+	}
+	if o.ContainerName == "" {
+		o.ContainerName = "task"
+	}
+	if o.Labels == nil {
+		o.Labels = map[string]string{"app": name, "nazKind": "sys"}
+	}
+	if o.ImagePullPolicy == "" {
+		o.ImagePullPolicy = v1.PullIfNotPresent
+	}
+	return o
+}
+
+func newTaskPod(name string, image string, additionalVars []v1.EnvVar, options TaskOptions) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: v1.ObjectMeta{Name: name, Labels: options.Labels},
+		Spec: v1.PodSpec{
+			RestartPolicy:      v1.RestartPolicyNever,
+			ServiceAccountName: options.ServiceAccountName,
+			Containers: []v1.Container{{
+				Name:            options.ContainerName,
+				Image:           image,
+				ImagePullPolicy: options.ImagePullPolicy,
+				Ports:           options.Ports,
+				Env:             additionalVars,
+				Resources:       options.Resources,
+			}},
+		},
+	}
+}
+
+// Collects the task logs, sending every line to the consumer
+type taskOutput struct {
+	lock     sync.Mutex
+	output   []byte
+	consumer LogMessageConsumer
+}
+
+func (o *taskOutput) write(line string) {
+	if o.consumer != nil {
+		o.consumer.Consume(strings.TrimSuffix(line, "\n"))
+	}
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.output = append(o.output, line...)
+	if len(o.output) > maxTaskOutputSize {
+		o.output = o.output[len(o.output)-maxTaskOutputSize:]
+	}
+}
+
+func (o *taskOutput) String() string {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	return string(o.output)
+}
+
+// Runs a task to completion in a pod, streaming its logs to the output consumer of the options.
+// The error is nil only in case the task succeeded, the result is returned whenever the task pod was created
+func (c *Client) RunTask(name string, image string, additionalVars []v1.EnvVar, options TaskOptions) (*TaskResult, error) {
+	options = options.withDefaults(name)
+	createdPod, err := c.CreatePod(newTaskPod(name, image, additionalVars, options), false)
+	if err != nil {
+		return nil, fmt.Errorf("Failed creating task pod %s - %s", name, err.Error())
+	}
+	result := &TaskResult{PodName: createdPod.Name, Phase: createdPod.Status.Phase}
+	output := &taskOutput{consumer: options.Output}
+
+	ctx, cancel := context.WithTimeout(context.Background(), options.Timeout)
+	defer cancel()
+
+	// Logs can only be followed once the container started
+	var logsDone chan struct{}
+	obj, err := WaitFor(ctx, c.PodGetter(createdPod.Name), PodStarted, WaitOptions{
+		Description: "task pod " + name + " to start",
+		Interval:    time.Second,
+		Progress:    logWaitProgress("Waiting task to start..."),
+	})
+	if err == nil {
+		logsDone, err = c.streamPodLogs(ctx, createdPod.Name, output)
+		if err != nil {
+			log.Printf("Failed following task pod %s logs, reading them once finished - %s\n", name, err.Error())
+		}
+		obj, err = WaitFor(ctx, c.PodGetter(createdPod.Name), PodCompleted, WaitOptions{
+			Description: "task pod " + name + " to finish",
+			Interval:    3 * time.Second,
+			Progress:    logWaitProgress("Waiting task to execute..."),
+		})
+	}
+	if pod, ok := obj.(*v1.Pod); ok && pod != nil {
+		result.setPod(pod)
+	}
+
+	if logsDone != nil {
+		select {
+		case <-logsDone:
+		case <-time.After(taskLogsDrainTimeout):
+			log.Printf("Task pod %s logs did not end, some output may be missing\n", name)
+		}
+	} else if podLogs, logsErr := c.GetPodLogs(createdPod.Name); logsErr == nil {
+		for _, line := range strings.SplitAfter(string(podLogs), "\n") {
+			if line != "" {
+				output.write(line)
+			}
+		}
+	} else {
+		log.Printf("Failed retreiving task pod %s logs, %s", createdPod.Name, logsErr.Error())
+	}
+	result.Output = output.String()
+
+	if err == nil && !result.Succeeded() {
+		err = result.failure()
+	}
+	if err == nil || !options.KeepPodOnFailure {
+		// Deleting this pod so it won't stay there forever
+		c.DeletePod(createdPod.Name)
+	} else {
+		log.Printf("Keeping pod %s of the failed task\n", createdPod.Name)
+	}
+	return result, err
+}
+
+// Follows the logs of the pod until the stream ends or the context is done, the returned channel is closed then
+func (c *Client) streamPodLogs(ctx context.Context, podName string, output *taskOutput) (chan struct{}, error) {
+	resp, err := c.doHttp("GET", "pods/"+podName+"/log?follow=true", nil)
+	if err != nil {
+		return nil, err
+	} else if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, newStatusError("GET", "pods/"+podName+"/log", resp)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			resp.Body.Close()
+		case <-done:
+		}
+	}()
+	go func() {
+		defer close(done)
+		defer resp.Body.Close()
+		reader := bufio.NewReader(resp.Body)
+		for {
+			line, err := reader.ReadString('\n')
+			if line != "" {
+				output.write(line)
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return done, nil
+}
+
+// Runs a bootstrap task the way ocopea always did, printing its logs once it is done
+func runOneOffTask(runner interface {
+	RunTask(name string, image string, additionalVars []v1.EnvVar, options TaskOptions) (*TaskResult, error)
+}, name string, containerName string, additionalVars []v1.EnvVar) error {
+	result, err := runner.RunTask(name, containerName, additionalVars, TaskOptions{
+		ContainerName: "bootstrap",
+		Labels:        map[string]string{"app": "bootstrap", "nazKind": "sys"},
+		Ports:         []v1.ContainerPort{{ContainerPort: 8000}},
+	})
+	if result != nil {
+		fmt.Printf("Task Pod Logs\n%s", result.Output)
+	}
+	if IsWaitTimeout(err) {
+		return fmt.Errorf("task pod %s failed to finish in a timely fashion", name)
+	} else if err != nil && result != nil && result.Phase == v1.PodFailed {
+		return fmt.Errorf("task pod %s has miserably failed - %s", name, err.Error())
+	}
+	return err
+}
@@ -0,0 +1,344 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path"
+	"sort"
+	"strings"
+)
+
+// schema is the subset of a swagger 2.0 schema object the generator understands
+type schema struct {
+	Description          string             `json:"description"`
+	Type                 string             `json:"type"`
+	Format               string             `json:"format"`
+	Ref                  string             `json:"$ref"`
+	Items                *schema            `json:"items"`
+	AdditionalProperties *schema            `json:"additionalProperties"`
+	Properties           map[string]*schema `json:"properties"`
+	Required             []string           `json:"required"`
+	GroupVersionKind     []struct {
+		Group   string `json:"group"`
+		Version string `json:"version"`
+		Kind    string `json:"kind"`
+	} `json:"x-kubernetes-group-version-kind"`
+}
+
+type swaggerSpec struct {
+	Definitions map[string]*schema `json:"definitions"`
+}
+
+// config selects the definitions to generate and the package each is generated to
+type config struct {
+	// Swagger document the types are generated from, relative to the config file
+	Spec string `json:"spec"`
+
+	// Hand written types definitions are mapped to instead of being generated, by definition name,
+	// e.g. io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta to ocopea/kubernetes/client/v1.ObjectMeta
+	Existing map[string]string `json:"existing"`
+
+	// Embedded in the types of kinds instead of their kind and apiVersion fields
+	TypeMeta string `json:"typeMeta"`
+
+	// Optional fields generated as pointers, so unset fields can be told from zero values,
+	// e.g. io.k8s.api.apps.v1.DeploymentSpec.replicas
+	PointerFields []string `json:"pointerFields"`
+
+	Packages []packageConfig `json:"packages"`
+}
+
+type packageConfig struct {
+	ImportPath string `json:"importPath"`
+
+	// Directory the package is generated to, relative to the config file
+	Dir string `json:"dir"`
+
+	// Package documentation, for packages that are entirely generated
+	Doc string `json:"doc"`
+
+	Definitions []string `json:"definitions"`
+}
+
+// goType is a Go type definitions are generated to or mapped to
+type goType struct {
+	importPath string
+	name       string
+}
+
+func parseGoType(qualified string) goType {
+	i := strings.LastIndex(qualified, ".")
+	return goType{importPath: qualified[:i], name: qualified[i+1:]}
+}
+
+// generator generates the types of a single package
+type generator struct {
+	spec    *swaggerSpec
+	config  *config
+	types   map[string]goType
+	pointer map[string]bool
+
+	pkg     packageConfig
+	imports map[string]string
+}
+
+func newGenerators(spec *swaggerSpec, cfg *config) ([]*generator, error) {
+	types := make(map[string]goType)
+	for definition, qualified := range cfg.Existing {
+		types[definition] = parseGoType(qualified)
+	}
+	for _, pkg := range cfg.Packages {
+		for _, definition := range pkg.Definitions {
+			if _, found := spec.Definitions[definition]; !found {
+				return nil, fmt.Errorf("definition %s is missing from the spec", definition)
+			}
+			if _, found := types[definition]; found {
+				return nil, fmt.Errorf("definition %s is generated more than once", definition)
+			}
+			types[definition] = goType{importPath: pkg.ImportPath, name: definition[strings.LastIndex(definition, ".")+1:]}
+		}
+	}
+	pointer := make(map[string]bool, len(cfg.PointerFields))
+	for _, field := range cfg.PointerFields {
+		pointer[field] = true
+	}
+
+	generators := make([]*generator, 0, len(cfg.Packages))
+	for _, pkg := range cfg.Packages {
+		generators = append(generators, &generator{spec: spec, config: cfg, types: types, pointer: pointer, pkg: pkg})
+	}
+	return generators, nil
+}
+
+// Returns the name the package is referred to by in the generated code, importing it when needed
+func (g *generator) qualify(t goType) string {
+	if t.importPath == g.pkg.ImportPath {
+		return t.name
+	}
+	name, found := g.imports[t.importPath]
+	if !found {
+		name = path.Base(t.importPath)
+		for _, imported := range g.imports {
+			if imported == name {
+				// e.g. appsv1 in case another v1 package is imported already
+				name = path.Base(path.Dir(t.importPath)) + name
+			}
+		}
+		g.imports[t.importPath] = name
+	}
+	return name + "." + t.name
+}
+
+// Returns the Go type of the schema, used by the field of the definition
+func (g *generator) typeOf(s *schema, field string) (string, error) {
+	if s.Ref != "" {
+		definition := strings.TrimPrefix(s.Ref, "#/definitions/")
+		t, found := g.types[definition]
+		if !found {
+			return "", fmt.Errorf("definition %s referenced by %s is neither generated nor mapped to an existing type", definition, field)
+		}
+		return g.qualify(t), nil
+	}
+
+	switch s.Type {
+	case "string":
+		if s.Format == "byte" {
+			return "[]byte", nil
+		}
+		return "string", nil
+	case "integer":
+		// The hand written types use int for int32 fields
+		if s.Format == "int64" {
+			return "int64", nil
+		}
+		return "int", nil
+	case "number":
+		return "float64", nil
+	case "boolean":
+		return "bool", nil
+	case "array":
+		if s.Items == nil {
+			return "", fmt.Errorf("array %s has no items", field)
+		}
+		item, err := g.typeOf(s.Items, field)
+		return "[]" + item, err
+	case "object", "":
+		if s.AdditionalProperties != nil {
+			value, err := g.typeOf(s.AdditionalProperties, field)
+			return "map[string]" + value, err
+		}
+		return "map[string]interface{}", nil
+	}
+	return "", fmt.Errorf("unsupported type %s of %s", s.Type, field)
+}
+
+// Writes the description as comment lines of up to 100 characters
+func writeComment(buf *bytes.Buffer, indent string, description string) {
+	for _, paragraph := range strings.Split(strings.TrimSpace(description), "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			if line != "" && len(line)+len(word) >= 100 {
+				fmt.Fprintf(buf, "%s// %s\n", indent, line)
+				line = ""
+			}
+			if line != "" {
+				line += " "
+			}
+			line += word
+		}
+		if line != "" {
+			fmt.Fprintf(buf, "%s// %s\n", indent, line)
+		}
+	}
+}
+
+// Words of field names written in upper case, e.g. podIP to PodIP
+var initialisms = map[string]string{
+	"api": "API", "cidr": "CIDR", "dns": "DNS", "http": "HTTP", "https": "HTTPS", "id": "ID", "ip": "IP",
+	"ips": "IPs", "json": "JSON", "tcp": "TCP", "tls": "TLS", "udp": "UDP", "uid": "UID", "uri": "URI", "url": "URL",
+}
+
+// Returns the Go name of a json field name, e.g. ingressClassName to IngressClassName and apiGroup to APIGroup
+func fieldName(jsonName string) string {
+	var words []string
+	start := 0
+	for i := 1; i <= len(jsonName); i++ {
+		if i == len(jsonName) || jsonName[i] >= 'A' && jsonName[i] <= 'Z' {
+			words = append(words, jsonName[start:i])
+			start = i
+		}
+	}
+	name := ""
+	for _, word := range words {
+		if initialism, found := initialisms[strings.ToLower(word)]; found {
+			name += initialism
+		} else {
+			name += strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return name
+}
+
+// Returns the properties of the definition in generation order: metadata first, then the fields in alphabetical
+// order and at last spec and status
+func propertyOrder(s *schema) []string {
+	rank := func(name string) int {
+		switch name {
+		case "metadata":
+			return 0
+		case "spec":
+			return 2
+		case "status":
+			return 3
+		}
+		return 1
+	}
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if rank(names[i]) != rank(names[j]) {
+			return rank(names[i]) < rank(names[j])
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+func (g *generator) writeStruct(buf *bytes.Buffer, definition string) error {
+	s := g.spec.Definitions[definition]
+	name := g.types[definition].name
+	if s.Description != "" {
+		writeComment(buf, "", s.Description)
+	}
+	fmt.Fprintf(buf, "type %s struct {\n", name)
+
+	required := make(map[string]bool, len(s.Required))
+	for _, field := range s.Required {
+		required[field] = true
+	}
+	_, hasKind := s.Properties["kind"]
+	_, hasAPIVersion := s.Properties["apiVersion"]
+	embedTypeMeta := hasKind && hasAPIVersion && (len(s.GroupVersionKind) > 0 || strings.HasSuffix(name, "List"))
+	if embedTypeMeta {
+		fmt.Fprintf(buf, "\t%s `json:\",inline\"`\n", g.qualify(parseGoType(g.config.TypeMeta)))
+	}
+
+	for _, property := range propertyOrder(s) {
+		if embedTypeMeta && (property == "kind" || property == "apiVersion") {
+			continue
+		}
+		field := s.Properties[property]
+		fieldType, err := g.typeOf(field, definition+"."+property)
+		if err != nil {
+			return err
+		}
+		if field.Description != "" {
+			writeComment(buf, "\t", field.Description)
+		}
+
+		// Object and list metadata are embedded, like the hand written types do
+		if property == "metadata" && field.Ref != "" {
+			fmt.Fprintf(buf, "\t%s `json:\"metadata,omitempty\"`\n\n", fieldType)
+			continue
+		}
+		if g.pointer[definition+"."+property] {
+			fieldType = "*" + fieldType
+		}
+		tag := property
+		if !required[property] {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(buf, "\t%s %s `json:\"%s\"`\n\n", fieldName(property), fieldType, tag)
+	}
+	buf.Truncate(buf.Len() - 1)
+	if bytes.HasSuffix(buf.Bytes(), []byte("{")) {
+		buf.WriteString("\n")
+	}
+	buf.WriteString("}\n\n")
+	return nil
+}
+
+// Returns the formatted source of the generated types of the package
+func (g *generator) generate(specName string) ([]byte, error) {
+	g.imports = make(map[string]string)
+	body := &bytes.Buffer{}
+	for _, definition := range g.pkg.Definitions {
+		if err := g.writeStruct(body, definition); err != nil {
+			return nil, err
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "// Code generated by typegen from %s. DO NOT EDIT.\n\n", specName)
+	if g.pkg.Doc != "" {
+		writeComment(buf, "", g.pkg.Doc)
+	}
+	fmt.Fprintf(buf, "package %s\n\n", path.Base(g.pkg.ImportPath))
+	if len(g.imports) > 0 {
+		importPaths := make([]string, 0, len(g.imports))
+		for importPath := range g.imports {
+			importPaths = append(importPaths, importPath)
+		}
+		sort.Strings(importPaths)
+		buf.WriteString("import (\n")
+		for _, importPath := range importPaths {
+			if name := g.imports[importPath]; name != path.Base(importPath) {
+				fmt.Fprintf(buf, "\t%s %q\n", name, importPath)
+			} else {
+				fmt.Fprintf(buf, "\t%q\n", importPath)
+			}
+		}
+		buf.WriteString(")\n\n")
+	}
+	buf.Write(body.Bytes())
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed formatting generated types of %s - %s", g.pkg.ImportPath, err.Error())
+	}
+	return source, nil
+}
@@ -0,0 +1,80 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+
+// Command typegen generates the Go types of Kubernetes api definitions from the swagger document of the api,
+// into the packages of the client. Definitions that already have hand written types, e.g. ObjectMeta, are mapped to
+// them so the generated types fit into the existing packages.
+// Run go generate in this directory after changing config.json or updating swagger.json from the
+// /openapi/v2 endpoint of an api server
+package main
+
+//go:generate go run . -config config.json
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// Name of the generated file in every package
+const generatedFileName = "types_generated.go"
+
+func readJSON(fileName string, v interface{}) error {
+	b, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("failed parsing %s - %s", fileName, err.Error())
+	}
+	return nil
+}
+
+// Returns the generated source files by file name, relative to the working directory
+func generateAll(configFileName string) (map[string][]byte, error) {
+	cfg := &config{}
+	if err := readJSON(configFileName, cfg); err != nil {
+		return nil, err
+	}
+	baseDir := filepath.Dir(configFileName)
+	spec := &swaggerSpec{}
+	if err := readJSON(filepath.Join(baseDir, cfg.Spec), spec); err != nil {
+		return nil, err
+	}
+
+	generators, err := newGenerators(spec, cfg)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string][]byte, len(generators))
+	for _, g := range generators {
+		source, err := g.generate(filepath.Base(cfg.Spec))
+		if err != nil {
+			return nil, err
+		}
+		files[filepath.Join(baseDir, filepath.FromSlash(g.pkg.Dir), generatedFileName)] = source
+	}
+	return files, nil
+}
+
+func main() {
+	configFileName := flag.String("config", "config.json", "generator configuration, see config.json")
+	flag.Parse()
+
+	files, err := generateAll(*configFileName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for fileName, source := range files {
+		if err = os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+			log.Fatal(err)
+		}
+		if err = ioutil.WriteFile(fileName, source, 0644); err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("generated %s\n", fileName)
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+const testSpec = `{
+  "definitions": {
+    "io.k8s.api.example.v1.Widget": {
+      "description": "Widget is an example kind.",
+      "properties": {
+        "apiVersion": {"type": "string"},
+        "kind": {"type": "string"},
+        "metadata": {"$ref": "#/definitions/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"},
+        "podIPs": {"type": "array", "items": {"type": "string"}},
+        "replicas": {"type": "integer", "format": "int32"},
+        "size": {"$ref": "#/definitions/io.k8s.apimachinery.pkg.api.resource.Quantity"},
+        "status": {"$ref": "#/definitions/io.k8s.api.example.v1.WidgetStatus"},
+        "selector": {"type": "object", "additionalProperties": {"type": "string"}}
+      },
+      "required": ["selector"],
+      "x-kubernetes-group-version-kind": [{"group": "example", "kind": "Widget", "version": "v1"}]
+    },
+    "io.k8s.api.example.v1.WidgetStatus": {
+      "properties": {
+        "observedGeneration": {"type": "integer", "format": "int64"},
+        "owner": {"$ref": "#/definitions/io.k8s.api.example.v1.Owner"}
+      }
+    }
+  }
+}`
+
+func newTestGenerator(t *testing.T, existing map[string]string) *generator {
+	spec := &swaggerSpec{}
+	if err := json.Unmarshal([]byte(testSpec), spec); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config{
+		TypeMeta:      "ocopea/kubernetes/client/unversioned.TypeMeta",
+		Existing:      existing,
+		PointerFields: []string{"io.k8s.api.example.v1.Widget.replicas"},
+		Packages: []packageConfig{{
+			ImportPath:  "ocopea/kubernetes/client/example/v1",
+			Definitions: []string{"io.k8s.api.example.v1.Widget", "io.k8s.api.example.v1.WidgetStatus"},
+		}},
+	}
+	generators, err := newGenerators(spec, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return generators[0]
+}
+
+func TestGenerate(t *testing.T) {
+	g := newTestGenerator(t, map[string]string{
+		"io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta": "ocopea/kubernetes/client/v1.ObjectMeta",
+		"io.k8s.apimachinery.pkg.api.resource.Quantity":   "ocopea/kubernetes/client/resource.Quantity",
+		"io.k8s.api.example.v1.Owner":                     "ocopea/kubernetes/client/v1.OwnerReference",
+		"io.k8s.apimachinery.pkg.apis.meta.v1.Unused":     "ocopea/kubernetes/client/unused.Unused",
+	})
+	source, err := g.generate("test.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	generated := string(source)
+
+	expected := []string{
+		"package v1\n",
+		"\"ocopea/kubernetes/client/resource\"\n",
+		"\"ocopea/kubernetes/client/v1\"",
+		"// Widget is an example kind.\ntype Widget struct {\n\tunversioned.TypeMeta `json:\",inline\"`\n\tv1.ObjectMeta ",
+		"PodIPs []string `json:\"podIPs,omitempty\"`",
+		"Replicas *int `json:\"replicas,omitempty\"`",
+		"Size resource.Quantity `json:\"size,omitempty\"`",
+		"Selector map[string]string `json:\"selector\"`",
+		"ObservedGeneration int64 `json:\"observedGeneration,omitempty\"`",
+		"Owner v1.OwnerReference `json:\"owner,omitempty\"`",
+	}
+	for _, e := range expected {
+		if !strings.Contains(generated, e) {
+			t.Errorf("expected generated source to contain %q, got\n%s", e, generated)
+		}
+	}
+	// Status comes last, after the fields in alphabetical order
+	if strings.Index(generated, "Status WidgetStatus") < strings.Index(generated, "Size resource.Quantity") {
+		t.Errorf("expected status to be the last field, got\n%s", generated)
+	}
+	if strings.Contains(generated, "Kind string") || strings.Contains(generated, "unused") {
+		t.Errorf("unexpected fields or imports in generated source\n%s", generated)
+	}
+}
+
+func TestGenerateUnknownReference(t *testing.T) {
+	g := newTestGenerator(t, map[string]string{
+		"io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta": "ocopea/kubernetes/client/v1.ObjectMeta",
+	})
+	_, err := g.generate("test.json")
+	if err == nil || !strings.Contains(err.Error(), "io.k8s.apimachinery.pkg.api.resource.Quantity") {
+		t.Errorf("expected generating a reference to an unmapped definition to fail, got %v", err)
+	}
+}
+
+func TestFieldName(t *testing.T) {
+	for jsonName, expected := range map[string]string{
+		"apiGroup":         "APIGroup",
+		"podIPs":           "PodIPs",
+		"ingressClassName": "IngressClassName",
+		"tls":              "TLS",
+		"hostIP":           "HostIP",
+	} {
+		if name := fieldName(jsonName); name != expected {
+			t.Errorf("expected field name of %s to be %s, got %s", jsonName, expected, name)
+		}
+	}
+}
+
+// The checked in generated types must match the checked in spec and config, run go generate after changing them
+func TestGeneratedTypesUpToDate(t *testing.T) {
+	files, err := generateAll("config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for fileName, source := range files {
+		existing, err := ioutil.ReadFile(fileName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(existing, source) {
+			t.Errorf("%s is out of date, run go generate in client/typegen", fileName)
+		}
+	}
+}
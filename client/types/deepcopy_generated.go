@@ -0,0 +1,38 @@
+// Code generated by deepcopygen. DO NOT EDIT.
+
+package types
+
+// DeepCopyInto copies the receiver into out, in must be non nil
+func (in *IntOrString) DeepCopyInto(out *IntOrString) {
+	*out = *in
+}
+
+// DeepCopy returns a copy of the receiver sharing no pointers, maps or slices with it
+func (in *IntOrString) DeepCopy() *IntOrString {
+	if in == nil {
+		return nil
+	}
+	out := new(IntOrString)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, in must be non nil
+func (in *RawExtension) DeepCopyInto(out *RawExtension) {
+	*out = *in
+	if in.RawJSON != nil {
+		in, out := &in.RawJSON, &out.RawJSON
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy returns a copy of the receiver sharing no pointers, maps or slices with it
+func (in *RawExtension) DeepCopy() *RawExtension {
+	if in == nil {
+		return nil
+	}
+	out := new(RawExtension)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -3,6 +3,7 @@ package types
 import (
 	"encoding/json"
 	"fmt"
+	"ocopea/kubernetes/client/protobuf"
 	"strconv"
 )
 
@@ -63,3 +64,28 @@ func (intstr IntOrString) MarshalJSON() ([]byte, error) {
 		return []byte{}, fmt.Errorf("impossible IntOrString.Kind")
 	}
 }
+
+// UnmarshalProtobuf decodes the IntOrString message holding the kind along with the value
+func (intstr *IntOrString) UnmarshalProtobuf(data []byte) error {
+	d := protobuf.NewDecoder(data)
+	for d.Next() {
+		switch d.Field() {
+		case 1:
+			intstr.Kind = IntstrKind(d.Int())
+		case 2:
+			intstr.IntVal = int(int32(d.Int()))
+		case 3:
+			intstr.StrVal = d.String()
+		default:
+			d.Skip()
+		}
+	}
+	return d.Err()
+}
+
+// MarshalProtobuf encodes the IntOrString message
+func (intstr *IntOrString) MarshalProtobuf(e *protobuf.Encoder) {
+	e.Int(1, int64(intstr.Kind))
+	e.Int(2, int64(intstr.IntVal))
+	e.String(3, intstr.StrVal)
+}
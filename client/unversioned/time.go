@@ -18,6 +18,7 @@ package unversioned
 
 import (
 	"encoding/json"
+	"ocopea/kubernetes/client/protobuf"
 	"time"
 )
 
@@ -102,3 +103,36 @@ func (t Time) MarshalJSON() ([]byte, error) {
 
 	return json.Marshal(t.UTC().Format(time.RFC3339))
 }
+
+// UnmarshalProtobuf decodes the time from a Timestamp message. Like the json
+// encoding, it only keeps the seconds, an empty message is the zero time.
+func (t *Time) UnmarshalProtobuf(data []byte) error {
+	if len(data) == 0 {
+		t.Time = time.Time{}
+		return nil
+	}
+	var seconds int64
+	d := protobuf.NewDecoder(data)
+	for d.Next() {
+		if d.Field() == 1 {
+			seconds = d.Int()
+		} else {
+			d.Skip()
+		}
+	}
+	if err := d.Err(); err != nil {
+		return err
+	}
+	t.Time = time.Unix(seconds, 0).Local()
+	return nil
+}
+
+// MarshalProtobuf encodes the time as a Timestamp message, the zero time is
+// encoded as an empty message.
+func (t *Time) MarshalProtobuf(e *protobuf.Encoder) {
+	if t.IsZero() {
+		return
+	}
+	e.Int(1, t.Unix())
+	e.Int(2, int64(t.Nanosecond()))
+}
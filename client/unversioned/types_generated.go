@@ -0,0 +1,31 @@
+// Code generated by typegen from swagger.json. DO NOT EDIT.
+
+package unversioned
+
+// A label selector is a label query over a set of resources. The result of matchLabels and
+// matchExpressions are ANDed. An empty label selector matches all objects. A null label selector
+// matches no objects.
+type LabelSelector struct {
+	// matchExpressions is a list of label selector requirements. The requirements are ANDed.
+	MatchExpressions []LabelSelectorRequirement `json:"matchExpressions,omitempty"`
+
+	// matchLabels is a map of {key,value} pairs. A single {key,value} in the matchLabels map is equivalent
+	// to an element of matchExpressions, whose key field is "key", the operator is "In", and the values
+	// array contains only "value". The requirements are ANDed.
+	MatchLabels map[string]string `json:"matchLabels,omitempty"`
+}
+
+// A label selector requirement is a selector that contains values, a key, and an operator that relates
+// the key and values.
+type LabelSelectorRequirement struct {
+	// key is the label key that the selector applies to.
+	Key string `json:"key"`
+
+	// operator represents a key's relationship to a set of values. Valid operators are In, NotIn, Exists
+	// and DoesNotExist.
+	Operator string `json:"operator"`
+
+	// values is an array of string values. If the operator is In or NotIn, the values array must be
+	// non-empty. If the operator is Exists or DoesNotExist, the values array must be empty.
+	Values []string `json:"values,omitempty"`
+}
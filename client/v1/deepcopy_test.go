@@ -0,0 +1,168 @@
+package v1
+
+import (
+	"ocopea/kubernetes/client/resource"
+	"ocopea/kubernetes/client/types"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var (
+	quantityType    = reflect.TypeOf(resource.Quantity{})
+	timeType        = reflect.TypeOf(time.Time{})
+	intOrStringType = reflect.TypeOf(types.IntOrString{})
+)
+
+// Sets every exported field reachable from the value, so deep copies sharing anything with it can be detected
+func fillValue(v reflect.Value, depth int) {
+	switch v.Type() {
+	case quantityType:
+		v.Set(reflect.ValueOf(resource.MustParse("1Gi")))
+		return
+	case timeType:
+		v.Set(reflect.ValueOf(time.Unix(1500000000, 0)))
+		return
+	case intOrStringType:
+		v.Set(reflect.ValueOf(types.NewIntOrStringFromString("orcs")))
+		return
+	}
+	if depth > 8 {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath == "" {
+				fillValue(v.Field(i), depth+1)
+			}
+		}
+	case reflect.Ptr:
+		v.Set(reflect.New(v.Type().Elem()))
+		fillValue(v.Elem(), depth+1)
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 2, 2))
+		for i := 0; i < v.Len(); i++ {
+			fillValue(v.Index(i), depth+1)
+		}
+	case reflect.Map:
+		v.Set(reflect.MakeMap(v.Type()))
+		key := reflect.New(v.Type().Key()).Elem()
+		fillValue(key, depth+1)
+		value := reflect.New(v.Type().Elem()).Elem()
+		fillValue(value, depth+1)
+		v.SetMapIndex(key, value)
+	case reflect.String:
+		v.SetString("orcs")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		v.SetInt(7)
+	}
+}
+
+// Fails in case the copy shares pointers, maps or slices with the original
+func checkNoSharing(t *testing.T, path string, original reflect.Value, copied reflect.Value) {
+	if original.Type() == timeType {
+		return
+	}
+	switch original.Kind() {
+	case reflect.Ptr:
+		if original.IsNil() {
+			return
+		}
+		if original.Pointer() == copied.Pointer() {
+			t.Errorf("%s is shared by the copy", path)
+		}
+		checkNoSharing(t, path, original.Elem(), copied.Elem())
+	case reflect.Slice:
+		if original.Len() == 0 {
+			return
+		}
+		if original.Pointer() == copied.Pointer() {
+			t.Errorf("%s is shared by the copy", path)
+		}
+		for i := 0; i < original.Len(); i++ {
+			checkNoSharing(t, path+"[]", original.Index(i), copied.Index(i))
+		}
+	case reflect.Map:
+		if original.Len() == 0 {
+			return
+		}
+		if original.Pointer() == copied.Pointer() {
+			t.Errorf("%s is shared by the copy", path)
+		}
+		for _, key := range original.MapKeys() {
+			checkNoSharing(t, path+"[]", original.MapIndex(key), copied.MapIndex(key))
+		}
+	case reflect.Struct:
+		for i := 0; i < original.NumField(); i++ {
+			checkNoSharing(t, path+"."+original.Type().Field(i).Name, original.Field(i), copied.Field(i))
+		}
+	}
+}
+
+func TestDeepCopy(t *testing.T) {
+	objects := []interface{}{
+		&ComponentStatusList{}, &ConfigMapList{}, &EndpointsList{}, &EventList{}, &LimitRangeList{}, &List{},
+		&NamespaceList{}, &NodeList{}, &PersistentVolumeClaimList{}, &PersistentVolumeList{}, &PodList{},
+		&PodTemplateList{}, &ReplicationControllerList{}, &ResourceQuotaList{}, &SecretList{}, &ServiceAccountList{},
+		&ServiceList{}, &DeleteOptions{}, &ListOptions{}, &PodAttachOptions{}, &PodExecOptions{}, &PodLogOptions{},
+		&PodProxyOptions{},
+	}
+	for _, obj := range objects {
+		original := reflect.ValueOf(obj)
+		name := original.Elem().Type().Name()
+		fillValue(original.Elem(), 0)
+
+		copied := original.MethodByName("DeepCopy").Call(nil)[0]
+		if !reflect.DeepEqual(original.Interface(), copied.Interface()) {
+			t.Errorf("expected the deep copy of %s to equal the original", name)
+		}
+		checkNoSharing(t, name, original, copied)
+	}
+
+	var nilPod *Pod
+	if nilPod.DeepCopy() != nil {
+		t.Errorf("expected the deep copy of a nil pod to be nil")
+	}
+	if ResourceList(nil).DeepCopy() != nil {
+		t.Errorf("expected the deep copy of a nil resource list to be nil")
+	}
+}
+
+func TestDeepCopyReplicationController(t *testing.T) {
+	rc := &ReplicationController{
+		ObjectMeta: ObjectMeta{Name: "orcs", Labels: map[string]string{"app": "orcs"}},
+		Spec: ReplicationControllerSpec{
+			Template: &PodTemplateSpec{
+				Spec: PodSpec{
+					Containers: []Container{{
+						Name: "orcs",
+						Env:  []EnvVar{{Name: "PORT", Value: "8080"}},
+						Resources: ResourceRequirements{
+							Limits: ResourceList{ResourceMemory: resource.MustParse("1Gi")},
+						},
+					}},
+				},
+			},
+		},
+	}
+
+	copied := rc.DeepCopy()
+	copied.Labels["app"] = "hub"
+	container := &copied.Spec.Template.Spec.Containers[0]
+	container.Env[0].Value = "9090"
+	container.Env = append(container.Env, EnvVar{Name: "DEBUG", Value: "true"})
+	limit := container.Resources.Limits[ResourceMemory]
+	limit.Add(resource.MustParse("1Gi"))
+	container.Resources.Limits[ResourceMemory] = limit
+
+	original := rc.Spec.Template.Spec.Containers[0]
+	if rc.Labels["app"] != "orcs" || len(original.Env) != 1 || original.Env[0].Value != "8080" {
+		t.Errorf("modifying the copy modified the original replication controller %+v", rc)
+	}
+	if memory := original.Resources.Limits[ResourceMemory]; memory.String() != "1Gi" {
+		t.Errorf("modifying the copy modified the memory limit of the original to %s", memory.String())
+	}
+}
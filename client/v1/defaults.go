@@ -0,0 +1,188 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package v1
+
+import (
+	"ocopea/kubernetes/client/types"
+	"reflect"
+	"strings"
+)
+
+// Returns the pull policy the server defaults containers of the image to, Always for latest or untagged images
+func defaultPullPolicy(image string) PullPolicy {
+	// Pinned to a digest
+	if strings.Contains(image, "@") {
+		return PullIfNotPresent
+	}
+	tag := "latest"
+	// A colon before the last slash belongs to the registry port, e.g. registry:5000/orcs
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		tag = image[i+1:]
+	}
+	if tag == "latest" {
+		return PullAlways
+	}
+	return PullIfNotPresent
+}
+
+// Returns true in case all the pointer fields of the struct are nil, e.g. volume sources setting no volume
+func allPointerFieldsNil(obj interface{}) bool {
+	v := reflect.ValueOf(obj).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).Kind() == reflect.Ptr && !v.Field(i).IsNil() {
+			return false
+		}
+	}
+	return true
+}
+
+func (h *Handler) setDefaults() {
+	if h.HTTPGet != nil {
+		if h.HTTPGet.Path == "" {
+			h.HTTPGet.Path = "/"
+		}
+		if h.HTTPGet.Scheme == "" {
+			h.HTTPGet.Scheme = URISchemeHTTP
+		}
+	}
+}
+
+func (p *Probe) setDefaults() {
+	p.Handler.setDefaults()
+	if p.TimeoutSeconds == 0 {
+		p.TimeoutSeconds = 1
+	}
+}
+
+func (c *Container) setDefaults() {
+	if c.ImagePullPolicy == "" {
+		c.ImagePullPolicy = defaultPullPolicy(c.Image)
+	}
+	if c.TerminationMessagePath == "" {
+		c.TerminationMessagePath = TerminationMessagePathDefault
+	}
+	for i := range c.Ports {
+		if c.Ports[i].Protocol == "" {
+			c.Ports[i].Protocol = ProtocolTCP
+		}
+	}
+	for i := range c.Env {
+		if c.Env[i].ValueFrom != nil && c.Env[i].ValueFrom.FieldRef != nil && c.Env[i].ValueFrom.FieldRef.APIVersion == "" {
+			c.Env[i].ValueFrom.FieldRef.APIVersion = "v1"
+		}
+	}
+	if c.LivenessProbe != nil {
+		c.LivenessProbe.setDefaults()
+	}
+	if c.ReadinessProbe != nil {
+		c.ReadinessProbe.setDefaults()
+	}
+	if c.Lifecycle != nil {
+		if c.Lifecycle.PostStart != nil {
+			c.Lifecycle.PostStart.setDefaults()
+		}
+		if c.Lifecycle.PreStop != nil {
+			c.Lifecycle.PreStop.setDefaults()
+		}
+	}
+}
+
+// SetDefaults sets the fields of the pod spec the server defaults, see Pod.SetDefaults
+func (s *PodSpec) SetDefaults() {
+	if s.DNSPolicy == "" {
+		s.DNSPolicy = DNSClusterFirst
+	}
+	if s.RestartPolicy == "" {
+		s.RestartPolicy = RestartPolicyAlways
+	}
+	if s.TerminationGracePeriodSeconds == nil {
+		period := int64(DefaultTerminationGracePeriodSeconds)
+		s.TerminationGracePeriodSeconds = &period
+	}
+	for i := range s.Volumes {
+		if allPointerFieldsNil(&s.Volumes[i].VolumeSource) {
+			s.Volumes[i].EmptyDir = &EmptyDirVolumeSource{}
+		}
+		if downwardAPI := s.Volumes[i].DownwardAPI; downwardAPI != nil {
+			for j := range downwardAPI.Items {
+				if downwardAPI.Items[j].FieldRef.APIVersion == "" {
+					downwardAPI.Items[j].FieldRef.APIVersion = "v1"
+				}
+			}
+		}
+	}
+	for i := range s.Containers {
+		s.Containers[i].setDefaults()
+	}
+}
+
+// SetDefaults sets the fields of the pod the server defaults when left unset: restart policy, dns policy, pull
+// policy by image tag, port protocols, probe timeouts etc. Container resource requests default to their limits
+func (p *Pod) SetDefaults() {
+	p.Spec.SetDefaults()
+	for i := range p.Spec.Containers {
+		resources := &p.Spec.Containers[i].Resources
+		for name, limit := range resources.Limits {
+			if resources.Requests == nil {
+				resources.Requests = ResourceList{}
+			}
+			if _, found := resources.Requests[name]; !found {
+				resources.Requests[name] = *limit.DeepCopy()
+			}
+		}
+	}
+}
+
+// SetDefaults sets the fields of the replication controller the server defaults when left unset: a single
+// replica, the selector and labels of the pod template and the defaults of the pod spec, see Pod.SetDefaults
+func (rc *ReplicationController) SetDefaults() {
+	if rc.Spec.Replicas == nil {
+		replicas := 1
+		rc.Spec.Replicas = &replicas
+	}
+	if template := rc.Spec.Template; template != nil {
+		if len(rc.Spec.Selector) == 0 && len(template.Labels) > 0 {
+			rc.Spec.Selector = make(map[string]string, len(template.Labels))
+			for key, value := range template.Labels {
+				rc.Spec.Selector[key] = value
+			}
+		}
+		if len(rc.Labels) == 0 && len(template.Labels) > 0 {
+			rc.Labels = make(map[string]string, len(template.Labels))
+			for key, value := range template.Labels {
+				rc.Labels[key] = value
+			}
+		}
+		template.Spec.SetDefaults()
+	}
+}
+
+// SetDefaults sets the fields of the service the server defaults when left unset: cluster ip type, no session
+// affinity, TCP ports and target ports equal to the ports
+func (s *Service) SetDefaults() {
+	if s.Spec.Type == "" {
+		s.Spec.Type = ServiceTypeClusterIP
+	}
+	if s.Spec.SessionAffinity == "" {
+		s.Spec.SessionAffinity = ServiceAffinityNone
+	}
+	for i := range s.Spec.Ports {
+		port := &s.Spec.Ports[i]
+		if port.Protocol == "" {
+			port.Protocol = ProtocolTCP
+		}
+		if port.TargetPort == (types.IntOrString{}) {
+			port.TargetPort = types.NewIntOrStringFromInt(port.Port)
+		}
+	}
+}
+
+// SetDefaults sets the fields of the persistent volume the server defaults when left unset: the retain
+// reclaim policy and the pending phase
+func (pv *PersistentVolume) SetDefaults() {
+	if pv.Spec.PersistentVolumeReclaimPolicy == "" {
+		pv.Spec.PersistentVolumeReclaimPolicy = PersistentVolumeReclaimRetain
+	}
+	if pv.Status.Phase == "" {
+		pv.Status.Phase = VolumePending
+	}
+}
@@ -0,0 +1,130 @@
+package v1
+
+import (
+	"ocopea/kubernetes/client/resource"
+	"ocopea/kubernetes/client/types"
+	"testing"
+)
+
+func TestDefaultPullPolicy(t *testing.T) {
+	for image, expected := range map[string]PullPolicy{
+		"ocopea/orcs":                     PullAlways,
+		"ocopea/orcs:latest":              PullAlways,
+		"ocopea/orcs:1.0":                 PullIfNotPresent,
+		"registry:5000/ocopea/orcs":       PullAlways,
+		"registry:5000/ocopea/orcs:1.0":   PullIfNotPresent,
+		"ocopea/orcs@sha256:4a1c9f3e8d2b": PullIfNotPresent,
+	} {
+		if policy := defaultPullPolicy(image); policy != expected {
+			t.Errorf("expected pull policy of %s to be %s, got %s", image, expected, policy)
+		}
+	}
+}
+
+func TestSetDefaultsReplicationController(t *testing.T) {
+	rc := &ReplicationController{
+		ObjectMeta: ObjectMeta{Name: "orcs"},
+		Spec: ReplicationControllerSpec{
+			Template: &PodTemplateSpec{
+				ObjectMeta: ObjectMeta{Labels: map[string]string{"app": "orcs"}},
+				Spec: PodSpec{
+					Volumes: []Volume{{Name: "scratch"}},
+					Containers: []Container{{
+						Name:           "orcs",
+						Image:          "ocopea/orcs:1.0",
+						Ports:          []ContainerPort{{ContainerPort: 8080}},
+						ReadinessProbe: &Probe{Handler: Handler{HTTPGet: &HTTPGetAction{Port: types.NewIntOrStringFromInt(8080)}}},
+						Env: []EnvVar{{
+							Name:      "POD_NAME",
+							ValueFrom: &EnvVarSource{FieldRef: &ObjectFieldSelector{FieldPath: "metadata.name"}},
+						}},
+					}},
+				},
+			},
+		},
+	}
+	rc.SetDefaults()
+
+	if *rc.Spec.Replicas != 1 || rc.Spec.Selector["app"] != "orcs" || rc.Labels["app"] != "orcs" {
+		t.Errorf("unexpected replication controller defaults %+v", rc)
+	}
+	spec := rc.Spec.Template.Spec
+	if spec.RestartPolicy != RestartPolicyAlways || spec.DNSPolicy != DNSClusterFirst ||
+		*spec.TerminationGracePeriodSeconds != DefaultTerminationGracePeriodSeconds || spec.Volumes[0].EmptyDir == nil {
+		t.Errorf("unexpected pod spec defaults %+v", spec)
+	}
+	container := spec.Containers[0]
+	if container.ImagePullPolicy != PullIfNotPresent || container.Ports[0].Protocol != ProtocolTCP ||
+		container.TerminationMessagePath != TerminationMessagePathDefault ||
+		container.Env[0].ValueFrom.FieldRef.APIVersion != "v1" {
+		t.Errorf("unexpected container defaults %+v", container)
+	}
+	if probe := container.ReadinessProbe; probe.TimeoutSeconds != 1 || probe.HTTPGet.Path != "/" || probe.HTTPGet.Scheme != URISchemeHTTP {
+		t.Errorf("unexpected probe defaults %+v", probe)
+	}
+
+	// Fields that are set are kept
+	replicas := 0
+	rc = &ReplicationController{
+		ObjectMeta: ObjectMeta{Labels: map[string]string{"tier": "web"}},
+		Spec: ReplicationControllerSpec{
+			Replicas: &replicas,
+			Selector: map[string]string{"app": "orcs", "tier": "web"},
+			Template: &PodTemplateSpec{
+				ObjectMeta: ObjectMeta{Labels: map[string]string{"app": "orcs", "tier": "web"}},
+				Spec:       PodSpec{RestartPolicy: RestartPolicyNever},
+			},
+		},
+	}
+	rc.SetDefaults()
+	if *rc.Spec.Replicas != 0 || len(rc.Labels) != 1 || rc.Spec.Template.Spec.RestartPolicy != RestartPolicyNever {
+		t.Errorf("expected set fields to be kept, got %+v", rc)
+	}
+}
+
+func TestSetDefaultsPod(t *testing.T) {
+	pod := &Pod{Spec: PodSpec{Containers: []Container{{
+		Name:  "orcs",
+		Image: "ocopea/orcs",
+		Resources: ResourceRequirements{
+			Limits:   ResourceList{ResourceMemory: resource.MustParse("1Gi"), ResourceCPU: resource.MustParse("500m")},
+			Requests: ResourceList{ResourceCPU: resource.MustParse("100m")},
+		},
+	}}}}
+	pod.SetDefaults()
+
+	container := pod.Spec.Containers[0]
+	if container.ImagePullPolicy != PullAlways {
+		t.Errorf("expected untagged images to be always pulled, got %s", container.ImagePullPolicy)
+	}
+	memory, cpu := container.Resources.Requests[ResourceMemory], container.Resources.Requests[ResourceCPU]
+	if memory.String() != "1Gi" || cpu.String() != "100m" {
+		t.Errorf("expected missing requests to default to the limits, got %v", container.Resources.Requests)
+	}
+}
+
+func TestSetDefaultsService(t *testing.T) {
+	svc := &Service{Spec: ServiceSpec{Ports: []ServicePort{
+		{Name: "http", Port: 80},
+		{Name: "admin", Port: 8081, TargetPort: types.NewIntOrStringFromString("admin"), Protocol: ProtocolUDP},
+	}}}
+	svc.SetDefaults()
+
+	if svc.Spec.Type != ServiceTypeClusterIP || svc.Spec.SessionAffinity != ServiceAffinityNone {
+		t.Errorf("unexpected service defaults %+v", svc.Spec)
+	}
+	if port := svc.Spec.Ports[0]; port.Protocol != ProtocolTCP || port.TargetPort != types.NewIntOrStringFromInt(80) {
+		t.Errorf("unexpected port defaults %+v", port)
+	}
+	if port := svc.Spec.Ports[1]; port.Protocol != ProtocolUDP || port.TargetPort.StrVal != "admin" {
+		t.Errorf("expected set port fields to be kept, got %+v", port)
+	}
+}
+
+func TestSetDefaultsPersistentVolume(t *testing.T) {
+	pv := &PersistentVolume{}
+	pv.SetDefaults()
+	if pv.Spec.PersistentVolumeReclaimPolicy != PersistentVolumeReclaimRetain || pv.Status.Phase != VolumePending {
+		t.Errorf("unexpected persistent volume defaults %+v", pv)
+	}
+}
@@ -0,0 +1,82 @@
+package v1
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"ocopea/kubernetes/client/protobuf"
+	"ocopea/kubernetes/client/unversioned"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func clearTypeMeta(v reflect.Value) {
+	v.FieldByName("TypeMeta").Set(reflect.ValueOf(unversioned.TypeMeta{}))
+}
+
+func TestProtobufRoundTrip(t *testing.T) {
+	for _, obj := range []interface {
+		protobuf.Marshaler
+		protobuf.Unmarshaler
+	}{&PodList{}, &EventList{}} {
+		original := reflect.ValueOf(obj)
+		name := original.Elem().Type().Name()
+		fillValue(original.Elem(), 0)
+		// The type meta is carried by the envelope rather than the messages
+		clearTypeMeta(original.Elem())
+		items := original.Elem().FieldByName("Items")
+		for i := 0; i < items.Len(); i++ {
+			clearTypeMeta(items.Index(i))
+		}
+
+		data := protobuf.Marshal(protobuf.TypeMeta{APIVersion: "v1", Kind: name}, obj)
+		decoded := reflect.New(original.Elem().Type())
+		typeMeta, err := protobuf.Unmarshal(data, decoded.Interface().(protobuf.Unmarshaler))
+		if err != nil {
+			t.Fatalf("failed decoding %s - %s", name, err.Error())
+		}
+		if typeMeta.Kind != name || typeMeta.APIVersion != "v1" {
+			t.Errorf("expected the envelope to hold v1 %s, found %s %s", name, typeMeta.APIVersion, typeMeta.Kind)
+		}
+		if !reflect.DeepEqual(original.Interface(), decoded.Interface()) {
+			t.Errorf("expected the decoded %s to equal the original", name)
+		}
+	}
+}
+
+// The fixtures were encoded by the api server serializers, from a newer api version with fields this package
+// doesn't have
+func TestProtobufMatchesJSON(t *testing.T) {
+	for _, test := range []struct {
+		fixture  string
+		jsonObj  interface{}
+		protoObj protobuf.Unmarshaler
+	}{
+		{"podlist", &PodList{}, &PodList{}},
+		{"eventlist", &EventList{}, &EventList{}},
+	} {
+		b, err := ioutil.ReadFile(filepath.Join("testdata", test.fixture+".json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = json.Unmarshal(b, test.jsonObj); err != nil {
+			t.Fatal(err)
+		}
+		if b, err = ioutil.ReadFile(filepath.Join("testdata", test.fixture+".pb")); err != nil {
+			t.Fatal(err)
+		}
+		typeMeta, err := protobuf.Unmarshal(b, test.protoObj)
+		if err != nil {
+			t.Fatalf("failed decoding %s - %s", test.fixture, err.Error())
+		}
+		reflect.ValueOf(test.protoObj).Elem().FieldByName("TypeMeta").Set(reflect.ValueOf(unversioned.TypeMeta{
+			APIVersion: typeMeta.APIVersion,
+			Kind:       typeMeta.Kind,
+		}))
+		if !reflect.DeepEqual(test.jsonObj, test.protoObj) {
+			expected, _ := json.MarshalIndent(test.jsonObj, "", "  ")
+			found, _ := json.MarshalIndent(test.protoObj, "", "  ")
+			t.Errorf("expected %s decoded from protobuf to equal the json\n%s\nfound\n%s", test.fixture, expected, found)
+		}
+	}
+}
@@ -175,6 +175,31 @@ type ObjectMeta struct {
 	// queryable and should be preserved when modifying objects.
 	// More info: http://releases.k8s.io/HEAD/docs/user-guide/annotations.md
 	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// List of objects depended by this object. If ALL objects in the list have
+	// been deleted, this object will be garbage collected. If this object is managed by a controller,
+	// then an entry in this list will point to this controller, with the controller field set to true.
+	OwnerReferences []OwnerReference `json:"ownerReferences,omitempty"`
+}
+
+// OwnerReference contains enough information to let you identify an owning
+// object. Currently, an owning object must be in the same namespace, so there
+// is no namespace field.
+type OwnerReference struct {
+	// API version of the referent.
+	APIVersion string `json:"apiVersion"`
+	// Kind of the referent.
+	Kind string `json:"kind"`
+	// Name of the referent.
+	Name string `json:"name"`
+	// UID of the referent.
+	UID types.UID `json:"uid"`
+	// If true, this reference points to the managing controller.
+	Controller *bool `json:"controller,omitempty"`
+	// If true, AND if the owner has the "foregroundDeletion" finalizer, then
+	// the owner cannot be deleted from the key-value store until this
+	// reference is removed.
+	BlockOwnerDeletion *bool `json:"blockOwnerDeletion,omitempty"`
 }
 
 const (
@@ -431,6 +456,22 @@ type PersistentVolumeClaimSpec struct {
 	Resources ResourceRequirements `json:"resources,omitempty"`
 	// VolumeName is the binding reference to the PersistentVolume backing this claim.
 	VolumeName string `json:"volumeName,omitempty"`
+	// Name of the StorageClass required by the claim.
+	StorageClassName *string `json:"storageClassName,omitempty"`
+	// DataSource populates the volume with the data of the referenced object, e.g. a VolumeSnapshot
+	// (snapshot.storage.k8s.io) or an existing PersistentVolumeClaim.
+	DataSource *TypedLocalObjectReference `json:"dataSource,omitempty"`
+}
+
+// TypedLocalObjectReference contains enough information to let you locate the
+// typed referenced object inside the same namespace.
+type TypedLocalObjectReference struct {
+	// APIGroup is the group for the resource being referenced, empty for the core api group.
+	APIGroup *string `json:"apiGroup,omitempty"`
+	// Kind is the type of resource being referenced
+	Kind string `json:"kind"`
+	// Name is the name of resource being referenced
+	Name string `json:"name"`
 }
 
 // PersistentVolumeClaimStatus is the current status of a persistent volume claim.
@@ -1818,6 +1859,9 @@ type NodeStatus struct {
 	// Capacity represents the available resources of a node.
 	// More info: http://releases.k8s.io/HEAD/docs/user-guide/persistent-volumes.md#capacity for more details.
 	Capacity ResourceList `json:"capacity,omitempty"`
+	// Allocatable represents the resources of a node that are available for scheduling.
+	// Defaults to Capacity.
+	Allocatable ResourceList `json:"allocatable,omitempty"`
 	// NodePhase is the recently observed lifecycle phase of the node.
 	// More info: http://releases.k8s.io/HEAD/docs/admin/node.md#node-phase
 	Phase NodePhase `json:"phase,omitempty"`
@@ -1877,9 +1921,10 @@ type NodeAddressType string
 
 // These are valid address type of node.
 const (
-	NodeHostName   NodeAddressType = "Hostname"
-	NodeExternalIP NodeAddressType = "ExternalIP"
-	NodeInternalIP NodeAddressType = "InternalIP"
+	NodeLegacyHostIP NodeAddressType = "LegacyHostIP"
+	NodeHostName     NodeAddressType = "Hostname"
+	NodeExternalIP   NodeAddressType = "ExternalIP"
+	NodeInternalIP   NodeAddressType = "InternalIP"
 )
 
 // NodeAddress contains information for the node's address.
@@ -2016,8 +2061,24 @@ type DeleteOptions struct {
 	// specified type will be used.
 	// Defaults to a per object value if not specified. zero means delete immediately.
 	GracePeriodSeconds *int64 `json:"gracePeriodSeconds"`
+
+	// Whether and how garbage collection will be performed.
+	// Defaults to a per object value if not specified, older api servers ignore it.
+	PropagationPolicy *DeletionPropagation `json:"propagationPolicy,omitempty"`
 }
 
+// DeletionPropagation decides if a deletion will propagate to the dependents of the object, and how
+type DeletionPropagation string
+
+const (
+	// Orphans the dependents
+	DeletePropagationOrphan DeletionPropagation = "Orphan"
+	// Deletes the object immediately and deletes the dependents in the background
+	DeletePropagationBackground DeletionPropagation = "Background"
+	// The object is deleted once all of its dependents were deleted
+	DeletePropagationForeground DeletionPropagation = "Foreground"
+)
+
 // ListOptions is the query options to a standard REST list call.
 type ListOptions struct {
 	unversioned.TypeMeta `json:",inline"`
@@ -2223,8 +2284,19 @@ type Event struct {
 
 	// The number of times this event has occurred.
 	Count int `json:"count,omitempty"`
+
+	// Type of this event (Normal, Warning), new types could be added in the future
+	Type string `json:"type,omitempty"`
 }
 
+// Valid values for event types (new types could be added in future)
+const (
+	// Information only and will not cause any problems
+	EventTypeNormal string = "Normal"
+	// These events are to warn that something might go wrong
+	EventTypeWarning string = "Warning"
+)
+
 // EventList is a list of events.
 type EventList struct {
 	unversioned.TypeMeta `json:",inline"`
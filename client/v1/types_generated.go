@@ -0,0 +1,39 @@
+// Code generated by typegen from swagger.json. DO NOT EDIT.
+
+package v1
+
+import (
+	"ocopea/kubernetes/client/unversioned"
+)
+
+// ConfigMap holds configuration data for pods to consume.
+type ConfigMap struct {
+	unversioned.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	ObjectMeta `json:"metadata,omitempty"`
+
+	// BinaryData contains the binary data. Each key must consist of alphanumeric characters, '-', '_' or
+	// '.'. BinaryData can contain byte sequences that are not in the UTF-8 range. The keys stored in
+	// BinaryData must not overlap with the ones in the Data field.
+	BinaryData map[string][]byte `json:"binaryData,omitempty"`
+
+	// Data contains the configuration data. Each key must consist of alphanumeric characters, '-', '_' or
+	// '.'. Values with non-UTF-8 byte sequences must use the BinaryData field. The keys stored in Data
+	// must not overlap with the keys in the BinaryData field.
+	Data map[string]string `json:"data,omitempty"`
+
+	// Immutable, if set to true, ensures that data stored in the ConfigMap cannot be updated (only object
+	// metadata can be modified). If not set to true, the field can be modified at any time. Defaulted to
+	// nil.
+	Immutable *bool `json:"immutable,omitempty"`
+}
+
+// ConfigMapList is a resource containing a list of ConfigMap objects.
+type ConfigMapList struct {
+	unversioned.TypeMeta `json:",inline"`
+	// Standard list metadata.
+	unversioned.ListMeta `json:"metadata,omitempty"`
+
+	// Items is the list of ConfigMaps.
+	Items []ConfigMap `json:"items"`
+}
@@ -0,0 +1,200 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package validation
+
+import (
+	"fmt"
+	"ocopea/kubernetes/client/types"
+	"ocopea/kubernetes/client/v1"
+)
+
+// Validates the name of an object using the name format required by its kind
+type nameValidator func(name string) []string
+
+// Validates a name that is further limited in length by the caller, e.g. ids used to name several objects
+func ValidateName(name string, maxLength int, validateName nameValidator, field string) ErrorList {
+	if name == "" {
+		return ErrorList{required(field)}
+	}
+	if len(name) > maxLength {
+		return ErrorList{tooLong(field, name, maxLength)}
+	}
+	return reasonsToErrors(field, name, validateName(name))
+}
+
+func ValidateObjectMeta(meta *v1.ObjectMeta, validateName nameValidator, field string) ErrorList {
+	var errs ErrorList
+	if meta.Name == "" {
+		if meta.GenerateName == "" {
+			errs = append(errs, &Error{Type: ErrorTypeRequired, Field: field + ".name", Detail: "name or generateName is required"})
+		}
+	} else {
+		errs = append(errs, reasonsToErrors(field+".name", meta.Name, validateName(meta.Name))...)
+	}
+	if meta.Namespace != "" {
+		errs = append(errs, reasonsToErrors(field+".namespace", meta.Namespace, IsDNS1123Label(meta.Namespace))...)
+	}
+	errs = append(errs, ValidateLabels(meta.Labels, field+".labels")...)
+	return errs
+}
+
+func ValidateLabels(labels map[string]string, field string) ErrorList {
+	var errs ErrorList
+	for k, v := range labels {
+		errs = append(errs, reasonsToErrors(field, k, IsQualifiedName(k))...)
+		errs = append(errs, reasonsToErrors(fmt.Sprintf("%s[%s]", field, k), v, IsValidLabelValue(v))...)
+	}
+	return errs
+}
+
+func validatePort(port int, field string) ErrorList {
+	return reasonsToErrors(field, port, IsValidPortNum(port))
+}
+
+func validateProtocol(protocol v1.Protocol, field string) ErrorList {
+	if protocol != "" && protocol != v1.ProtocolTCP && protocol != v1.ProtocolUDP {
+		return ErrorList{invalid(field, protocol, "must be TCP or UDP")}
+	}
+	return nil
+}
+
+func ValidateContainer(container *v1.Container, field string) ErrorList {
+	var errs ErrorList
+	if container.Name == "" {
+		errs = append(errs, required(field+".name"))
+	} else {
+		errs = append(errs, reasonsToErrors(field+".name", container.Name, IsDNS1123Label(container.Name))...)
+	}
+	if container.Image == "" {
+		errs = append(errs, required(field+".image"))
+	}
+
+	for i, port := range container.Ports {
+		portField := fmt.Sprintf("%s.ports[%d]", field, i)
+		errs = append(errs, validatePort(port.ContainerPort, portField+".containerPort")...)
+		if port.HostPort != 0 {
+			errs = append(errs, validatePort(port.HostPort, portField+".hostPort")...)
+		}
+		errs = append(errs, validateProtocol(port.Protocol, portField+".protocol")...)
+	}
+
+	for i, envVar := range container.Env {
+		envField := fmt.Sprintf("%s.env[%d].name", field, i)
+		if envVar.Name == "" {
+			errs = append(errs, required(envField))
+		} else {
+			errs = append(errs, reasonsToErrors(envField, envVar.Name, IsEnvVarName(envVar.Name))...)
+		}
+	}
+	return errs
+}
+
+func ValidatePodSpec(spec *v1.PodSpec, field string) ErrorList {
+	var errs ErrorList
+	if len(spec.Containers) == 0 {
+		errs = append(errs, required(field+".containers"))
+	}
+
+	containerNames := make(map[string]bool)
+	for i := range spec.Containers {
+		containerField := fmt.Sprintf("%s.containers[%d]", field, i)
+		errs = append(errs, ValidateContainer(&spec.Containers[i], containerField)...)
+
+		name := spec.Containers[i].Name
+		if name != "" {
+			if containerNames[name] {
+				errs = append(errs, duplicate(containerField+".name", name))
+			}
+			containerNames[name] = true
+		}
+	}
+	return errs
+}
+
+func ValidatePod(pod *v1.Pod) ErrorList {
+	errs := ValidateObjectMeta(&pod.ObjectMeta, IsDNS1123Subdomain, "metadata")
+	return append(errs, ValidatePodSpec(&pod.Spec, "spec")...)
+}
+
+func ValidateReplicationController(rc *v1.ReplicationController) ErrorList {
+	errs := ValidateObjectMeta(&rc.ObjectMeta, IsDNS1123Subdomain, "metadata")
+
+	if rc.Spec.Replicas != nil && *rc.Spec.Replicas < 0 {
+		errs = append(errs, invalid("spec.replicas", *rc.Spec.Replicas, "must be greater than or equal to 0"))
+	}
+	errs = append(errs, ValidateLabels(rc.Spec.Selector, "spec.selector")...)
+
+	if rc.Spec.Template == nil {
+		return append(errs, required("spec.template"))
+	}
+
+	// The selector must select the pods created from the template, otherwise the rc keeps creating pods forever
+	templateLabels := rc.Spec.Template.Labels
+	for k, v := range rc.Spec.Selector {
+		if templateLabels[k] != v {
+			errs = append(errs, invalid(
+				"spec.template.metadata.labels",
+				templateLabels,
+				fmt.Sprintf("does not match selector %s=%s", k, v)))
+		}
+	}
+	if len(rc.Spec.Selector) == 0 && len(templateLabels) == 0 {
+		errs = append(errs, required("spec.template.metadata.labels"))
+	}
+	errs = append(errs, ValidateLabels(templateLabels, "spec.template.metadata.labels")...)
+	return append(errs, ValidatePodSpec(&rc.Spec.Template.Spec, "spec.template.spec")...)
+}
+
+func ValidateService(svc *v1.Service) ErrorList {
+	errs := ValidateObjectMeta(&svc.ObjectMeta, IsDNS1035Label, "metadata")
+	errs = append(errs, ValidateLabels(svc.Spec.Selector, "spec.selector")...)
+
+	portNames := make(map[string]bool)
+	for i, port := range svc.Spec.Ports {
+		portField := fmt.Sprintf("spec.ports[%d]", i)
+		if port.Name == "" {
+			if len(svc.Spec.Ports) > 1 {
+				errs = append(errs, &Error{Type: ErrorTypeRequired, Field: portField + ".name", Detail: "required when the service has multiple ports"})
+			}
+		} else {
+			errs = append(errs, reasonsToErrors(portField+".name", port.Name, IsDNS1123Label(port.Name))...)
+			if portNames[port.Name] {
+				errs = append(errs, duplicate(portField+".name", port.Name))
+			}
+			portNames[port.Name] = true
+		}
+
+		errs = append(errs, validatePort(port.Port, portField+".port")...)
+		if port.NodePort != 0 {
+			errs = append(errs, validatePort(port.NodePort, portField+".nodePort")...)
+		}
+		if port.TargetPort.Kind == types.IntstrInt && port.TargetPort.IntVal != 0 {
+			errs = append(errs, validatePort(port.TargetPort.IntVal, portField+".targetPort")...)
+		}
+		errs = append(errs, validateProtocol(port.Protocol, portField+".protocol")...)
+	}
+	return errs
+}
+
+func ValidateNamespace(ns *v1.Namespace) ErrorList {
+	return ValidateObjectMeta(&ns.ObjectMeta, IsDNS1123Label, "metadata")
+}
+
+func ValidatePersistentVolume(pv *v1.PersistentVolume) ErrorList {
+	return ValidateObjectMeta(&pv.ObjectMeta, IsDNS1123Subdomain, "metadata")
+}
+
+func ValidatePersistentVolumeClaim(pvc *v1.PersistentVolumeClaim) ErrorList {
+	errs := ValidateObjectMeta(&pvc.ObjectMeta, IsDNS1123Subdomain, "metadata")
+	if len(pvc.Spec.AccessModes) == 0 {
+		errs = append(errs, required("spec.accessModes"))
+	}
+	if source := pvc.Spec.DataSource; source != nil {
+		if source.Kind == "" {
+			errs = append(errs, required("spec.dataSource.kind"))
+		}
+		if source.Name == "" {
+			errs = append(errs, required("spec.dataSource.name"))
+		}
+	}
+	return errs
+}
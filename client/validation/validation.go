@@ -0,0 +1,198 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+// Package validation validates v1 objects on the client side before they are submitted to the api server
+// Rules follow the common string formats documented in the v1 types (DNS_LABEL, DNS_SUBDOMAIN etc.)
+// so invalid objects fail fast with a readable error instead of a 422 returned by the server
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type ErrorType string
+
+const (
+	ErrorTypeRequired  ErrorType = "Required value"
+	ErrorTypeInvalid   ErrorType = "Invalid value"
+	ErrorTypeDuplicate ErrorType = "Duplicate value"
+	ErrorTypeTooLong   ErrorType = "Too long"
+)
+
+// Error describes a single invalid field of an object
+type Error struct {
+	Type     ErrorType
+	Field    string
+	BadValue interface{}
+	Detail   string
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("%s: %s", e.Field, e.Type)
+	if e.Type != ErrorTypeRequired {
+		msg += fmt.Sprintf(": %#v", e.BadValue)
+	}
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	return msg
+}
+
+// ErrorList holds all the validation errors found on an object
+type ErrorList []*Error
+
+// Returns nil in case the list is empty or an error describing all validation errors otherwise
+func (list ErrorList) ToError() error {
+	if len(list) == 0 {
+		return nil
+	}
+	messages := make([]string, 0, len(list))
+	for _, e := range list {
+		messages = append(messages, e.Error())
+	}
+	return fmt.Errorf("%s", strings.Join(messages, "; "))
+}
+
+func required(field string) *Error {
+	return &Error{Type: ErrorTypeRequired, Field: field}
+}
+
+func invalid(field string, value interface{}, detail string) *Error {
+	return &Error{Type: ErrorTypeInvalid, Field: field, BadValue: value, Detail: detail}
+}
+
+func duplicate(field string, value interface{}) *Error {
+	return &Error{Type: ErrorTypeDuplicate, Field: field, BadValue: value}
+}
+
+func tooLong(field string, value string, maxLength int) *Error {
+	return &Error{
+		Type:     ErrorTypeTooLong,
+		Field:    field,
+		BadValue: value,
+		Detail:   fmt.Sprintf("must be no more than %d characters", maxLength),
+	}
+}
+
+const DNS1123LabelMaxLength = 63
+const DNS1123SubdomainMaxLength = 253
+const LabelValueMaxLength = 63
+const qualifiedNameMaxLength = 63
+
+var dns1123LabelRegexp = regexp.MustCompile("^[a-z0-9]([-a-z0-9]*[a-z0-9])?$")
+var dns1035LabelRegexp = regexp.MustCompile("^[a-z]([-a-z0-9]*[a-z0-9])?$")
+var dns1123SubdomainRegexp = regexp.MustCompile("^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$")
+var envVarNameRegexp = regexp.MustCompile("^[-._a-zA-Z][-._a-zA-Z0-9]*$")
+var qualifiedNameRegexp = regexp.MustCompile("^([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]$")
+var labelValueRegexp = regexp.MustCompile("^(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])?$")
+
+// Returns a list of reasons the value is not a DNS_LABEL (RFC 1123), empty if valid
+func IsDNS1123Label(value string) []string {
+	var reasons []string
+	if len(value) > DNS1123LabelMaxLength {
+		reasons = append(reasons, fmt.Sprintf("must be no more than %d characters", DNS1123LabelMaxLength))
+	}
+	if !dns1123LabelRegexp.MatchString(value) {
+		reasons = append(reasons,
+			"must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character")
+	}
+	return reasons
+}
+
+// Returns a list of reasons the value is not a DNS label starting with a letter (RFC 1035), empty if valid.
+// Service names must conform to this format
+func IsDNS1035Label(value string) []string {
+	var reasons []string
+	if len(value) > DNS1123LabelMaxLength {
+		reasons = append(reasons, fmt.Sprintf("must be no more than %d characters", DNS1123LabelMaxLength))
+	}
+	if !dns1035LabelRegexp.MatchString(value) {
+		reasons = append(reasons,
+			"must consist of lower case alphanumeric characters or '-', start with an alphabetic character, and end with an alphanumeric character")
+	}
+	return reasons
+}
+
+// Returns a list of reasons the value is not a DNS_SUBDOMAIN (RFC 1123), empty if valid
+func IsDNS1123Subdomain(value string) []string {
+	var reasons []string
+	if len(value) > DNS1123SubdomainMaxLength {
+		reasons = append(reasons, fmt.Sprintf("must be no more than %d characters", DNS1123SubdomainMaxLength))
+	}
+	if !dns1123SubdomainRegexp.MatchString(value) {
+		reasons = append(reasons,
+			"must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character")
+	}
+	return reasons
+}
+
+// Returns a list of reasons the value is not a valid environment variable name, empty if valid.
+// The api server accepts more than the C_IDENTIFIER documented on EnvVar, e.g. "server.port"
+func IsEnvVarName(value string) []string {
+	if !envVarNameRegexp.MatchString(value) {
+		return []string{"must consist of alphabetic characters, digits, '_', '-', or '.', and must not start with a digit"}
+	}
+	return nil
+}
+
+// Returns a list of reasons the value is not a valid label key, e.g. "app" or "ocopea.io/kind", empty if valid
+func IsQualifiedName(value string) []string {
+	var reasons []string
+	name := value
+	parts := strings.Split(value, "/")
+	switch len(parts) {
+	case 1:
+	case 2:
+		var prefix string
+		prefix, name = parts[0], parts[1]
+		if len(prefix) == 0 {
+			reasons = append(reasons, "prefix part must be non-empty")
+		} else if prefixReasons := IsDNS1123Subdomain(prefix); len(prefixReasons) > 0 {
+			for _, r := range prefixReasons {
+				reasons = append(reasons, "prefix part "+r)
+			}
+		}
+	default:
+		return []string{"a qualified name must consist of an optional DNS subdomain prefix and a name separated by '/'"}
+	}
+
+	if len(name) == 0 {
+		reasons = append(reasons, "name part must be non-empty")
+	} else if len(name) > qualifiedNameMaxLength {
+		reasons = append(reasons, fmt.Sprintf("name part must be no more than %d characters", qualifiedNameMaxLength))
+	}
+	if len(name) > 0 && !qualifiedNameRegexp.MatchString(name) {
+		reasons = append(reasons,
+			"name part must consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character")
+	}
+	return reasons
+}
+
+// Returns a list of reasons the value is not a valid label value, empty if valid
+func IsValidLabelValue(value string) []string {
+	var reasons []string
+	if len(value) > LabelValueMaxLength {
+		reasons = append(reasons, fmt.Sprintf("must be no more than %d characters", LabelValueMaxLength))
+	}
+	if !labelValueRegexp.MatchString(value) {
+		reasons = append(reasons,
+			"must be empty or consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character")
+	}
+	return reasons
+}
+
+// Returns a list of reasons the value is not a valid port number, empty if valid
+func IsValidPortNum(port int) []string {
+	if port < 1 || port > 65535 {
+		return []string{"must be between 1 and 65535, inclusive"}
+	}
+	return nil
+}
+
+func reasonsToErrors(field string, value interface{}, reasons []string) ErrorList {
+	var errs ErrorList
+	for _, reason := range reasons {
+		errs = append(errs, invalid(field, value, reason))
+	}
+	return errs
+}
@@ -0,0 +1,132 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package validation
+
+import (
+	"ocopea/kubernetes/client/types"
+	"ocopea/kubernetes/client/v1"
+	"strings"
+	"testing"
+)
+
+func TestIsDNS1123Label(t *testing.T) {
+	for _, valid := range []string{"a", "ab", "a-b", "0", "orcs", "app1", strings.Repeat("a", 63)} {
+		if reasons := IsDNS1123Label(valid); len(reasons) > 0 {
+			t.Errorf("expected %s to be a valid DNS label, got %v", valid, reasons)
+		}
+	}
+	for _, invalid := range []string{"", "A", "a_b", "-a", "a-", "a.b", strings.Repeat("a", 64)} {
+		if reasons := IsDNS1123Label(invalid); len(reasons) == 0 {
+			t.Errorf("expected %s to be an invalid DNS label", invalid)
+		}
+	}
+}
+
+func TestIsQualifiedName(t *testing.T) {
+	for _, valid := range []string{"app", "nazKind", "ocopea.io/kind", "a_b.c-d"} {
+		if reasons := IsQualifiedName(valid); len(reasons) > 0 {
+			t.Errorf("expected %s to be a valid label key, got %v", valid, reasons)
+		}
+	}
+	for _, invalid := range []string{"", "/app", "Ocopea.io/kind", "a/b/c", "-app", strings.Repeat("a", 64)} {
+		if reasons := IsQualifiedName(invalid); len(reasons) == 0 {
+			t.Errorf("expected %s to be an invalid label key", invalid)
+		}
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	if errs := ValidateName("app-service-1", 24, IsDNS1035Label, "id"); len(errs) > 0 {
+		t.Errorf("expected valid name, got %v", errs.ToError())
+	}
+	errs := ValidateName("app-service-id-longer-than-24", 24, IsDNS1035Label, "id")
+	if len(errs) != 1 || errs[0].Type != ErrorTypeTooLong {
+		t.Errorf("expected too long error, got %v", errs.ToError())
+	}
+	if errs := ValidateName("1app", 24, IsDNS1035Label, "id"); len(errs) != 1 || errs[0].Type != ErrorTypeInvalid {
+		t.Errorf("expected invalid error, got %v", errs.ToError())
+	}
+}
+
+func validReplicationController() *v1.ReplicationController {
+	replicas := 1
+	return &v1.ReplicationController{
+		ObjectMeta: v1.ObjectMeta{Name: "orcs", Labels: map[string]string{"nazKind": "orcs"}},
+		Spec: v1.ReplicationControllerSpec{
+			Replicas: &replicas,
+			Selector: map[string]string{"app": "orcs"},
+			Template: &v1.PodTemplateSpec{
+				ObjectMeta: v1.ObjectMeta{Labels: map[string]string{"app": "orcs", "nazKind": "orcs"}},
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{{
+						Name:  "orcs",
+						Image: "ocopea/orcs-k8s-runner",
+						Ports: []v1.ContainerPort{{ContainerPort: 8080}},
+						Env:   []v1.EnvVar{{Name: "NAZ_MS_CONF", Value: "{}"}, {Name: "server.port", Value: "8080"}},
+					}},
+				},
+			},
+		},
+	}
+}
+
+func TestValidateReplicationController(t *testing.T) {
+	if errs := ValidateReplicationController(validReplicationController()); len(errs) > 0 {
+		t.Fatalf("expected valid replication controller, got %v", errs.ToError())
+	}
+
+	rc := validReplicationController()
+	rc.Spec.Template.Labels["app"] = "other"
+	assertSingleError(t, ValidateReplicationController(rc), ErrorTypeInvalid, "spec.template.metadata.labels")
+
+	rc = validReplicationController()
+	rc.Spec.Template.Spec.Containers = append(rc.Spec.Template.Spec.Containers, rc.Spec.Template.Spec.Containers[0])
+	assertSingleError(t, ValidateReplicationController(rc), ErrorTypeDuplicate, "spec.template.spec.containers[1].name")
+
+	rc = validReplicationController()
+	rc.Spec.Template.Spec.Containers[0].Ports[0].ContainerPort = 70000
+	assertSingleError(t, ValidateReplicationController(rc), ErrorTypeInvalid, "spec.template.spec.containers[0].ports[0].containerPort")
+
+	rc = validReplicationController()
+	rc.Spec.Template.Spec.Containers[0].Env[0].Name = "1_CONF"
+	assertSingleError(t, ValidateReplicationController(rc), ErrorTypeInvalid, "spec.template.spec.containers[0].env[0].name")
+
+	rc = validReplicationController()
+	rc.Labels["bad key"] = "orcs"
+	assertSingleError(t, ValidateReplicationController(rc), ErrorTypeInvalid, "metadata.labels")
+}
+
+func TestValidateService(t *testing.T) {
+	svc := &v1.Service{
+		ObjectMeta: v1.ObjectMeta{Name: "orcs"},
+		Spec: v1.ServiceSpec{
+			Selector: map[string]string{"app": "orcs"},
+			Ports: []v1.ServicePort{
+				{Name: "service-http", Port: 80, TargetPort: types.NewIntOrStringFromInt(8080), Protocol: v1.ProtocolTCP},
+				{Name: "debug", Port: 5005, TargetPort: types.NewIntOrStringFromString("debug")},
+			},
+		},
+	}
+	if errs := ValidateService(svc); len(errs) > 0 {
+		t.Fatalf("expected valid service, got %v", errs.ToError())
+	}
+
+	svc.Spec.Ports[1].Name = ""
+	assertSingleError(t, ValidateService(svc), ErrorTypeRequired, "spec.ports[1].name")
+
+	svc.Spec.Ports[1].Name = "service-http"
+	assertSingleError(t, ValidateService(svc), ErrorTypeDuplicate, "spec.ports[1].name")
+
+	svc.Spec.Ports = svc.Spec.Ports[:1]
+	svc.Name = "1orcs"
+	assertSingleError(t, ValidateService(svc), ErrorTypeInvalid, "metadata.name")
+}
+
+func assertSingleError(t *testing.T, errs ErrorList, errorType ErrorType, field string) {
+	if len(errs) != 1 {
+		t.Errorf("expected a single %s error on %s, got %v", errorType, field, errs.ToError())
+		return
+	}
+	if errs[0].Type != errorType || errs[0].Field != field {
+		t.Errorf("expected %s error on %s, got %s", errorType, field, errs[0].Error())
+	}
+}
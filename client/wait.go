@@ -0,0 +1,182 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Getter reads the current state of the object waited for
+type Getter func() (interface{}, error)
+
+// Condition returns true once the object returned by the getter reached the desired state.
+// Returning an error stops waiting, e.g. when the object reached a state it will never recover from
+type Condition func(obj interface{}) (bool, error)
+
+// WatchTrigger starts watching changes that may affect the waited object, sending a notification for each change
+type WatchTrigger func(notify chan<- struct{}) (CloseHandle, error)
+
+type WaitOptions struct {
+	// Describes what we're waiting for in logs and errors, e.g. "pod orcs-x7k2p to be running"
+	Description string
+
+	// Maximal time to wait, waiting ends earlier in case the context is done. Zero means no timeout
+	Timeout time.Duration
+
+	// Time between checks, growing by BackoffFactor after every check up to MaxInterval. Defaults to a second
+	Interval      time.Duration
+	BackoffFactor float64
+	MaxInterval   time.Duration
+
+	// Errors returned by the getter are treated as not ready yet instead of failing the wait,
+	// the last error is reported in case of a timeout
+	RetryGetterErrors bool
+
+	// Called after every check that did not satisfy the condition
+	Progress func(obj interface{}, attempt int, elapsed time.Duration)
+
+	// When set, the object is checked as soon as the watch notifies of a change instead of waiting for the next interval.
+	// In case the watch can't be started we fall back to checking every interval
+	Watch WatchTrigger
+}
+
+// WaitTimeoutError is returned when the condition was not met within the timeout
+type WaitTimeoutError struct {
+	Description string
+	Elapsed     time.Duration
+	Attempts    int
+	LastError   error
+}
+
+func (e *WaitTimeoutError) Error() string {
+	msg := fmt.Sprintf("timed out waiting for %s after %s and %d attempts", e.Description, e.Elapsed, e.Attempts)
+	if e.LastError != nil {
+		msg += " - " + e.LastError.Error()
+	}
+	return msg
+}
+
+// Returns true in case the error was returned because a wait timed out
+func IsWaitTimeout(err error) bool {
+	_, ok := err.(*WaitTimeoutError)
+	return ok
+}
+
+// Checks the object returned by the getter until the condition is met, returning the last object read.
+// Fails when the getter or the condition fail, the timeout expires or the context is done
+func WaitFor(ctx context.Context, getter Getter, condition Condition, opts WaitOptions) (interface{}, error) {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+	if opts.Description == "" {
+		opts.Description = "condition"
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	var notifications chan struct{}
+	if opts.Watch != nil {
+		notifications = make(chan struct{}, 1)
+		closeHandle, err := opts.Watch(notifications)
+		if err != nil {
+			log.Printf("Failed watching while waiting for %s, polling instead - %s\n", opts.Description, err.Error())
+			notifications = nil
+		} else {
+			defer closeHandle()
+		}
+	}
+
+	start := time.Now()
+	interval := opts.Interval
+	var lastError error
+	var obj interface{}
+	for attempt := 1; ; attempt++ {
+		var err error
+		obj, err = getter()
+		if err != nil {
+			if !opts.RetryGetterErrors {
+				return obj, fmt.Errorf("Failed waiting for %s - %s", opts.Description, err.Error())
+			}
+			lastError = err
+		} else {
+			done, err := condition(obj)
+			if err != nil {
+				return obj, err
+			}
+			if done {
+				return obj, nil
+			}
+		}
+		if opts.Progress != nil {
+			opts.Progress(obj, attempt, time.Since(start))
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-timer.C:
+		case <-notifications:
+			timer.Stop()
+		case <-ctx.Done():
+			timer.Stop()
+			if ctx.Err() == context.DeadlineExceeded {
+				return obj, &WaitTimeoutError{
+					Description: opts.Description,
+					Elapsed:     time.Since(start),
+					Attempts:    attempt,
+					LastError:   lastError,
+				}
+			}
+			return obj, fmt.Errorf("Stopped waiting for %s - %s", opts.Description, ctx.Err().Error())
+		}
+
+		if opts.BackoffFactor > 1 {
+			interval = time.Duration(float64(interval) * opts.BackoffFactor)
+			if opts.MaxInterval > 0 && interval > opts.MaxInterval {
+				interval = opts.MaxInterval
+			}
+		}
+	}
+}
+
+// Returns a watch trigger notifying of every change to objects of the resource matching the label filters
+func (c *Client) WatchTrigger(resource GroupVersionResource, namespaced bool, labelFilters map[string]string) WatchTrigger {
+	return func(notify chan<- struct{}) (CloseHandle, error) {
+		events := make(chan UnstructuredWatchEvent, 10)
+		closeWatch, err := c.Resource(resource, namespaced).Watch(labelFilters, "", events)
+		if err != nil {
+			return nil, err
+		}
+		done := make(chan struct{})
+		go func() {
+			for {
+				select {
+				case _, ok := <-events:
+					if !ok {
+						// The watch ended, waiting falls back to checking every interval
+						return
+					}
+					select {
+					case notify <- struct{}{}:
+					default:
+						// A notification is already pending
+					}
+				case <-done:
+					return
+				}
+			}
+		}()
+		return func() {
+			close(done)
+			closeWatch()
+		}, nil
+	}
+}
+
+func coreResource(resource string) GroupVersionResource {
+	return GroupVersionResource{Version: "v1", Resource: resource}
+}
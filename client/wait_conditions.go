@@ -0,0 +1,178 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	batchv1 "ocopea/kubernetes/client/batch/v1"
+	"ocopea/kubernetes/client/v1"
+)
+
+// Getters of the objects we usually wait for, to be used with WaitFor along with the matching conditions
+
+func (c *Client) PodGetter(podName string) Getter {
+	return func() (interface{}, error) {
+		return c.GetPodInfo(podName)
+	}
+}
+
+// Returns the pods matching the label filters as []*v1.Pod
+func (c *Client) PodListGetter(labelFilters map[string]string) Getter {
+	return func() (interface{}, error) {
+		return c.ListPodsInfo(labelFilters)
+	}
+}
+
+func (c *Client) ServiceGetter(serviceName string) Getter {
+	return func() (interface{}, error) {
+		return c.GetServiceInfo(serviceName)
+	}
+}
+
+func (c *Client) ReplicationControllerGetter(rcName string) Getter {
+	return func() (interface{}, error) {
+		return c.GetReplicationControllerInfo(rcName)
+	}
+}
+
+func (c *Client) PersistentVolumeGetter(persistentVolumeName string) Getter {
+	return func() (interface{}, error) {
+		return c.GetPersistentVolumeInfo(persistentVolumeName)
+	}
+}
+
+func (c *Client) JobGetter(jobName string) Getter {
+	return func() (interface{}, error) {
+		return c.GetJob(jobName)
+	}
+}
+
+// Returns the namespace, or nil once the namespace does not exist
+func (c *Client) NamespaceGetter(nsName string) Getter {
+	return func() (interface{}, error) {
+		resp, err := c.doHttpNoNS("GET", "namespaces/"+nsName, nil)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, nil
+		} else if resp.StatusCode != http.StatusOK {
+			return nil, newStatusError("GET", "namespaces/"+nsName, resp)
+		}
+		ns := &v1.Namespace{}
+		return ns, json.NewDecoder(resp.Body).Decode(ns)
+	}
+}
+
+// Met once the pod is running, fails in case the pod won't get to run, e.g. when its image can't be pulled
+func PodRunning(obj interface{}) (bool, error) {
+	pod := obj.(*v1.Pod)
+	if pod.Status.Phase == v1.PodRunning {
+		return true, nil
+	}
+	if len(pod.Status.ContainerStatuses) > 0 {
+		state := pod.Status.ContainerStatuses[0].State
+		if state.Waiting != nil && isImagePullFailure(state.Waiting.Reason) {
+			return false, fmt.Errorf(
+				"Pod %s failed to start. failed pulling image %s - %s",
+				pod.Name,
+				pod.Status.ContainerStatuses[0].Image,
+				state.Waiting.Message)
+		} else if state.Terminated != nil {
+			return false, fmt.Errorf("Pod %s failed to start and found in phase %s%s", pod.Name, pod.Status.Phase, describeContainerState(pod))
+		}
+	}
+	return false, nil
+}
+
+// Met once the containers of the pod started, running or already finished.
+// Fails in case the pod won't start since its image can't be pulled
+func PodStarted(obj interface{}) (bool, error) {
+	pod := obj.(*v1.Pod)
+	if pod.Status.Phase != v1.PodPending && pod.Status.Phase != "" {
+		return true, nil
+	}
+	if len(pod.Status.ContainerStatuses) > 0 {
+		state := pod.Status.ContainerStatuses[0].State
+		if state.Waiting != nil && isImagePullFailure(state.Waiting.Reason) {
+			return false, fmt.Errorf(
+				"Pod %s failed to start. failed pulling image %s - %s",
+				pod.Name,
+				pod.Status.ContainerStatuses[0].Image,
+				state.Waiting.Message)
+		}
+	}
+	return false, nil
+}
+
+func isImagePullFailure(reason string) bool {
+	return reason == "ErrImagePull" || reason == "ImagePullBackOff" || reason == "InvalidImageName"
+}
+
+// Describes the state of the first container of the pod, used for enriching error messages
+func describeContainerState(pod *v1.Pod) string {
+	if len(pod.Status.ContainerStatuses) == 0 {
+		return ""
+	}
+	state := pod.Status.ContainerStatuses[0].State
+	if state.Waiting != nil {
+		return fmt.Sprintf(
+			". container still waiting. reason:%s; message:%s",
+			state.Waiting.Reason,
+			state.Waiting.Message)
+	} else if state.Terminated != nil {
+		return fmt.Sprintf(
+			". container terminated. reason:%s; message:%s; exit code:%d",
+			state.Terminated.Reason,
+			state.Terminated.Message,
+			state.Terminated.ExitCode)
+	}
+	return ""
+}
+
+// Met once the pod finished running, successfully or not
+func PodCompleted(obj interface{}) (bool, error) {
+	pod := obj.(*v1.Pod)
+	return pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed, nil
+}
+
+// Met once a pod list getter returns at least one pod
+func PodsScheduled(obj interface{}) (bool, error) {
+	return len(obj.([]*v1.Pod)) > 0, nil
+}
+
+// Met once the service can be reached according to its type
+func ServiceReady(obj interface{}) (bool, error) {
+	return isServiceReady(obj.(*v1.Service))
+}
+
+// Met once the replication controller created replicas
+func ReplicationControllerHasReplicas(obj interface{}) (bool, error) {
+	return obj.(*v1.ReplicationController).Status.Replicas > 0, nil
+}
+
+// Met once the volume can be claimed, fails in case the volume is released or failed
+func VolumeReady(obj interface{}) (bool, error) {
+	return isVolumeReady(obj.(*v1.PersistentVolume))
+}
+
+// Met once a namespace getter does not find the namespace
+func NamespaceDeleted(obj interface{}) (bool, error) {
+	return obj == nil, nil
+}
+
+// Met once a pod list getter returns no pods
+func PodsTerminated(obj interface{}) (bool, error) {
+	return len(obj.([]*v1.Pod)) == 0, nil
+}
+
+// Met once the job completed, fails in case the job failed, e.g. after exceeding its backoff limit
+func JobFinished(obj interface{}) (bool, error) {
+	job := obj.(*batchv1.Job)
+	if failed := jobCondition(job, batchv1.JobFailed); failed != nil {
+		return false, fmt.Errorf("Job %s failed after %d failed pods - %s %s", job.Name, job.Status.Failed, failed.Reason, failed.Message)
+	}
+	return jobCondition(job, batchv1.JobComplete) != nil, nil
+}
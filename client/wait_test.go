@@ -0,0 +1,129 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"context"
+	"errors"
+	"ocopea/kubernetes/client/v1"
+	"testing"
+	"time"
+)
+
+func countingGetter(count *int) Getter {
+	return func() (interface{}, error) {
+		*count++
+		return *count, nil
+	}
+}
+
+func reaches(n int) Condition {
+	return func(obj interface{}) (bool, error) {
+		return obj.(int) >= n, nil
+	}
+}
+
+func TestWaitForConditionMet(t *testing.T) {
+	count := 0
+	progressCalls := 0
+	obj, err := WaitFor(context.Background(), countingGetter(&count), reaches(3), WaitOptions{
+		Interval:      time.Millisecond,
+		BackoffFactor: 2,
+		MaxInterval:   4 * time.Millisecond,
+		Progress: func(obj interface{}, attempt int, elapsed time.Duration) {
+			progressCalls++
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.(int) != 3 || progressCalls != 2 {
+		t.Errorf("expected condition met on 3rd attempt after 2 progress calls, got %v and %d", obj, progressCalls)
+	}
+}
+
+func TestWaitForTimeout(t *testing.T) {
+	count := 0
+	_, err := WaitFor(context.Background(), countingGetter(&count), reaches(1000), WaitOptions{
+		Description: "forever",
+		Timeout:     20 * time.Millisecond,
+		Interval:    time.Millisecond,
+	})
+	if !IsWaitTimeout(err) {
+		t.Errorf("expected timeout, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = WaitFor(ctx, countingGetter(&count), reaches(1000), WaitOptions{Interval: time.Millisecond})
+	if err == nil || IsWaitTimeout(err) {
+		t.Errorf("expected cancellation error, got %v", err)
+	}
+}
+
+func TestWaitForErrors(t *testing.T) {
+	failing := func(obj interface{}) (bool, error) {
+		return false, errors.New("never")
+	}
+	count := 0
+	if _, err := WaitFor(context.Background(), countingGetter(&count), failing, WaitOptions{}); err == nil || count != 1 {
+		t.Errorf("expected condition error to stop waiting, got %v after %d attempts", err, count)
+	}
+
+	attempts := 0
+	flaky := func() (interface{}, error) {
+		attempts++
+		if attempts < 3 {
+			return nil, errors.New("not yet")
+		}
+		return attempts, nil
+	}
+	if _, err := WaitFor(context.Background(), flaky, reaches(0), WaitOptions{Interval: time.Millisecond}); err == nil {
+		t.Errorf("expected getter error to stop waiting")
+	}
+	attempts = 0
+	if _, err := WaitFor(context.Background(), flaky, reaches(0), WaitOptions{Interval: time.Millisecond, RetryGetterErrors: true}); err != nil {
+		t.Errorf("expected getter errors to be retried, got %v", err)
+	}
+}
+
+func TestWaitForWatch(t *testing.T) {
+	count := 0
+	closed := false
+	watch := func(notify chan<- struct{}) (CloseHandle, error) {
+		go func() {
+			for i := 0; i < 2; i++ {
+				notify <- struct{}{}
+			}
+		}()
+		return func() { closed = true }, nil
+	}
+
+	start := time.Now()
+	_, err := WaitFor(context.Background(), countingGetter(&count), reaches(3), WaitOptions{
+		Interval: time.Hour,
+		Watch:    watch,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(start) > 10*time.Second || !closed {
+		t.Errorf("expected watch notifications to trigger checks and the watch to be closed")
+	}
+}
+
+func TestPodConditionsFailOnImagePullFailures(t *testing.T) {
+	for _, reason := range []string{"ErrImagePull", "ImagePullBackOff", "InvalidImageName"} {
+		pod := &v1.Pod{}
+		pod.Name = "orcs"
+		pod.Status.Phase = v1.PodPending
+		pod.Status.ContainerStatuses = []v1.ContainerStatus{
+			{Image: "ocopea/orcs", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: reason}}},
+		}
+		if _, err := PodRunning(pod); err == nil {
+			t.Errorf("expected PodRunning to fail on %s", reason)
+		}
+		if _, err := PodStarted(pod); err == nil {
+			t.Errorf("expected PodStarted to fail on %s", reason)
+		}
+	}
+}
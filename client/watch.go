@@ -0,0 +1,141 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"ocopea/kubernetes/client/protobuf"
+	"ocopea/kubernetes/client/unversioned"
+	"ocopea/kubernetes/client/v1"
+)
+
+// PodWatchEvent is a single change to a pod, Status describes the failure of ERROR events instead of the pod
+type PodWatchEvent struct {
+	Type   WatchEventType
+	Object *v1.Pod
+	Status *unversioned.Status
+}
+
+// rawWatchEvent is an event of a watch stream whose object is not decoded yet
+type rawWatchEvent struct {
+	Type   WatchEventType  `json:"type"`
+	Object json.RawMessage `json:"object"`
+
+	// The object is encoded in the protobuf wire format rather than json
+	protobuf bool
+}
+
+// Decodes the object of the event, returns the status of ERROR events instead
+func (e *rawWatchEvent) decode(obj protobuf.Unmarshaler, typeMeta *unversioned.TypeMeta) (*unversioned.Status, error) {
+	if e.Type == WatchError {
+		status := &unversioned.Status{}
+		return status, decodeObject(e.Object, e.protobuf, status, &status.TypeMeta)
+	}
+	return nil, decodeObject(e.Object, e.protobuf, obj, typeMeta)
+}
+
+// Returns the query string watching from the resource version, added to the given query string
+func watchQuery(query string, resourceVersion string) string {
+	if query == "" {
+		query = "?watch=true"
+	} else {
+		query += "&watch=true"
+	}
+	if resourceVersion != "" {
+		query += "&resourceVersion=" + resourceVersion
+	}
+	return query
+}
+
+// Returns a function reading the events of a watch response, streamed as json objects or as protobuf frames
+func watchReader(resp *http.Response) func() (*rawWatchEvent, error) {
+	if isProtobuf(resp) {
+		return func() (*rawWatchEvent, error) {
+			event, err := protobuf.ReadWatchEvent(resp.Body)
+			if err != nil {
+				return nil, err
+			}
+			return &rawWatchEvent{Type: WatchEventType(event.Type), Object: event.Object, protobuf: true}, nil
+		}
+	}
+	dec := json.NewDecoder(resp.Body)
+	return func() (*rawWatchEvent, error) {
+		event := &rawWatchEvent{}
+		if err := dec.Decode(event); err != nil {
+			return nil, err
+		}
+		return event, nil
+	}
+}
+
+// Watches the collection at the api path, in the protobuf wire format when enabled. Every event is handed to send
+// along with the channel signalled by the close handle, the watch ends when send returns false
+func (c *Client) watchCollection(
+	path string,
+	kind string,
+	send func(event *rawWatchEvent, closeChannel chan bool) bool) (CloseHandle, error) {
+
+	resp, err := c.sendHttpAccepting("GET", path, nil, "application/json", c.accept())
+	if err != nil {
+		return nil, fmt.Errorf("Failed watching k8s %s - %s", kind, err.Error())
+	} else if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, newStatusError("GET", path, resp)
+	}
+
+	closeChannel := make(chan bool, 1)
+	log.Printf("watching %s\n", kind)
+	go func() {
+		defer resp.Body.Close()
+		read := watchReader(resp)
+		for {
+			event, err := read()
+			if err != nil {
+				if err != io.EOF {
+					log.Printf("Error reading watch of %s - %s", kind, err.Error())
+				}
+				return
+			}
+			if !send(event, closeChannel) {
+				log.Printf("stopped watching %s\n", kind)
+				return
+			}
+		}
+	}()
+
+	return func() {
+		log.Printf("done watching %s\n", kind)
+		closeChannel <- true
+		resp.Body.Close()
+	}, nil
+}
+
+// Watches changes to the pods matching the label filters and sends them to the consumer channel until the close
+// handle is called or the server ends the watch. Use an empty resourceVersion to start watching from the current state
+func (c *Client) WatchPods(
+	labelFilters map[string]string,
+	resourceVersion string,
+	consumerChannel chan PodWatchEvent) (CloseHandle, error) {
+
+	path := "/api/v1/namespaces/" + c.Namespace + "/pods" + watchQuery(buildLabelsQueryString(labelFilters), resourceVersion)
+	return c.watchCollection(path, "pods", func(event *rawWatchEvent, closeChannel chan bool) bool {
+		pod := &v1.Pod{}
+		status, err := event.decode(pod, &pod.TypeMeta)
+		if err != nil {
+			log.Printf("Failed decoding pod %s event - %s", event.Type, err.Error())
+			return false
+		}
+		if status != nil {
+			pod = nil
+		}
+		select {
+		case consumerChannel <- PodWatchEvent{Type: event.Type, Object: pod, Status: status}:
+			return true
+		case _ = <-closeChannel:
+			return false
+		}
+	})
+}
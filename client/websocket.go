@@ -0,0 +1,238 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package client
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// A minimal websocket (RFC 6455) implementation for the streaming subresources of the api server, e.g. exec.
+// Keeping it here spares the client the dependency on a websocket library
+
+const (
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xa
+
+	wsAcceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+	// Messages larger than this are rejected, the api server sends stream chunks much smaller than that
+	wsMaxMessageSize = 32 << 20
+)
+
+// wsConn is an open websocket connection
+type wsConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+
+	// Clients mask the frames they send, servers don't
+	mask bool
+
+	writeLock sync.Mutex
+}
+
+// Returns the Sec-WebSocket-Accept value the server answers the key with
+func wsAcceptKey(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + wsAcceptGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// Opens a websocket connection to the url of the request offering the sub protocols, the request is expected
+// to be authenticated. Returns the connection and the sub protocol the server picked
+func (c *Client) dialWebSocket(req *http.Request, protocols []string) (*wsConn, string, error) {
+	host := req.URL.Host
+	if req.URL.Port() == "" {
+		if req.URL.Scheme == "https" {
+			host += ":443"
+		} else {
+			host += ":80"
+		}
+	}
+
+	var conn net.Conn
+	var err error
+	switch req.URL.Scheme {
+	case "https":
+		tlsConfig := &tls.Config{}
+		if transport, ok := c.httpClient.Transport.(*http.Transport); ok && transport.TLSClientConfig != nil {
+			tlsConfig = transport.TLSClientConfig.Clone()
+		}
+		tlsConfig.ServerName = req.URL.Hostname()
+		// The upgrade is an http/1.1 request, so not negotiating http2
+		tlsConfig.NextProtos = nil
+		conn, err = tls.Dial("tcp", host, tlsConfig)
+	case "http":
+		conn, err = net.Dial("tcp", host)
+	default:
+		return nil, "", fmt.Errorf("unsupported scheme %s", req.URL.Scheme)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("Failed connecting to %s - %s", host, err.Error())
+	}
+
+	nonce := make([]byte, 16)
+	if _, err = rand.Read(nonce); err != nil {
+		conn.Close()
+		return nil, "", err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Protocol", strings.Join(protocols, ", "))
+	if err = req.Write(conn); err != nil {
+		conn.Close()
+		return nil, "", fmt.Errorf("Failed sending websocket upgrade request - %s", err.Error())
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		conn.Close()
+		return nil, "", fmt.Errorf("Failed reading websocket upgrade response - %s", err.Error())
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		defer conn.Close()
+		if resp.StatusCode == http.StatusUnauthorized && c.Credentials != nil {
+			c.Credentials.Invalidate()
+		}
+		return nil, "", newStatusError(req.Method, req.URL.Path, resp)
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != wsAcceptKey(key) {
+		conn.Close()
+		return nil, "", errors.New("websocket upgrade response does not match the request key")
+	}
+	return &wsConn{conn: conn, reader: reader, mask: true}, resp.Header.Get("Sec-WebSocket-Protocol"), nil
+}
+
+// Writes a single unfragmented frame
+func (ws *wsConn) writeFrame(opcode byte, payload []byte) error {
+	header := make([]byte, 2, 14)
+	header[0] = 0x80 | opcode
+	length := len(payload)
+	switch {
+	case length < 126:
+		header[1] = byte(length)
+	case length <= 0xffff:
+		header[1] = 126
+		header = append(header, 0, 0)
+		binary.BigEndian.PutUint16(header[2:], uint16(length))
+	default:
+		header[1] = 127
+		header = append(header, make([]byte, 8)...)
+		binary.BigEndian.PutUint64(header[2:], uint64(length))
+	}
+
+	if ws.mask {
+		header[1] |= 0x80
+		maskKey := make([]byte, 4)
+		if _, err := rand.Read(maskKey); err != nil {
+			return err
+		}
+		header = append(header, maskKey...)
+		masked := make([]byte, length)
+		for i := range payload {
+			masked[i] = payload[i] ^ maskKey[i%4]
+		}
+		payload = masked
+	}
+
+	ws.writeLock.Lock()
+	defer ws.writeLock.Unlock()
+	if _, err := ws.conn.Write(header); err != nil {
+		return err
+	}
+	_, err := ws.conn.Write(payload)
+	return err
+}
+
+// Reads the next data message, answering pings on the way. Returns io.EOF once the peer closes the connection
+func (ws *wsConn) readMessage() (byte, []byte, error) {
+	var messageOpcode byte
+	var message []byte
+	for {
+		header := make([]byte, 2)
+		if _, err := io.ReadFull(ws.reader, header); err != nil {
+			return 0, nil, err
+		}
+		final := header[0]&0x80 != 0
+		opcode := header[0] & 0x0f
+		masked := header[1]&0x80 != 0
+		length := uint64(header[1] & 0x7f)
+		switch length {
+		case 126:
+			extended := make([]byte, 2)
+			if _, err := io.ReadFull(ws.reader, extended); err != nil {
+				return 0, nil, err
+			}
+			length = uint64(binary.BigEndian.Uint16(extended))
+		case 127:
+			extended := make([]byte, 8)
+			if _, err := io.ReadFull(ws.reader, extended); err != nil {
+				return 0, nil, err
+			}
+			length = binary.BigEndian.Uint64(extended)
+		}
+		if length+uint64(len(message)) > wsMaxMessageSize {
+			return 0, nil, fmt.Errorf("websocket message exceeds %d bytes", wsMaxMessageSize)
+		}
+
+		maskKey := make([]byte, 4)
+		if masked {
+			if _, err := io.ReadFull(ws.reader, maskKey); err != nil {
+				return 0, nil, err
+			}
+		}
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(ws.reader, payload); err != nil {
+			return 0, nil, err
+		}
+		if masked {
+			for i := range payload {
+				payload[i] ^= maskKey[i%4]
+			}
+		}
+
+		switch opcode {
+		case wsOpClose:
+			ws.writeFrame(wsOpClose, nil)
+			return 0, nil, io.EOF
+		case wsOpPing:
+			if err := ws.writeFrame(wsOpPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case wsOpPong:
+			continue
+		case wsOpContinuation:
+		default:
+			messageOpcode = opcode
+		}
+		message = append(message, payload...)
+		if final {
+			return messageOpcode, message, nil
+		}
+	}
+}
+
+// Closes the connection, letting the peer know when possible
+func (ws *wsConn) Close() error {
+	ws.writeFrame(wsOpClose, nil)
+	return ws.conn.Close()
+}
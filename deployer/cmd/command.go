@@ -37,7 +37,7 @@ func addGlobalFlagsToFlagSet(flagSet *flag.FlagSet) globalArgsBag {
 		k8sURL:         flagSet.String("url", "http://localhost:8080", "K8S remote api url"),
 		k8sNamespace:   flagSet.String("namespace", "ocopea", "K8S namespace to use"),
 		deploymentType: flagSet.String("deployment-type", "local", "Deployment type"),
-		localClusterIp: flagSet.String("local-cluster-ip", "", "Local cluster ip - only relevant on local deployments"),
+		localClusterIp: flagSet.String("local-cluster-ip", "", "Local cluster ip - only relevant on local deployments, discovered from the cluster nodes when omitted"),
 		userName:       flagSet.String("user", "", ""),
 		password:       flagSet.String("password", "", "Password"),
 	}
@@ -104,18 +104,25 @@ func initializeDeployerContext(globalArgs globalArgsBag) (error, *DeployerContex
 	fmt.Printf("k8s url: %s\nnamespace: %s\ndeployment: %s\nuser: %s\n",
 		*globalArgs.k8sURL, *globalArgs.k8sNamespace, *globalArgs.deploymentType, *globalArgs.userName)
 
-	// input validation
-	if strings.Compare(*globalArgs.deploymentType, "local") == 0 &&
-		len(*globalArgs.localClusterIp) == 0 {
-		return errors.New("on local deployment, you must provide local-cluster-ip flag, bye.."), nil
-	}
-
 	// Building "secure" http client for communicating with the target kubernetes cluster
 	client, err := k8sClient.NewClient(*globalArgs.k8sURL, *globalArgs.k8sNamespace, *globalArgs.userName, *globalArgs.password, "")
 	if err != nil {
 		return errors.New("Failed creating connection with kubernetes cluster " + err.Error()), nil
 	}
 
+	// On local deployments, when no cluster ip is provided we discover it using the cluster nodes
+	if strings.Compare(*globalArgs.deploymentType, "local") == 0 &&
+		len(*globalArgs.localClusterIp) == 0 {
+		clusterIp, err := client.FindClusterAddress()
+		if err != nil {
+			return fmt.Errorf(
+				"on local deployment, local-cluster-ip flag was not provided and discovery failed - %s",
+				err.Error()), nil
+		}
+		fmt.Printf("local-cluster-ip flag not provided, using discovered cluster ip %s\n", clusterIp)
+		*globalArgs.localClusterIp = clusterIp
+	}
+
 	// Instantiating deployer context struct
 	return nil,
 		&DeployerContext{
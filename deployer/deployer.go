@@ -3,6 +3,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"flag"
@@ -41,6 +42,8 @@ type deploySiteArgsBag struct {
 	cleanup                   *bool
 	verboseSiteLogging        *bool
 	customPostgresServiceName *string
+	journal                   *string
+	undoOnFailure             *bool
 }
 
 type deployServiceArgsBag struct {
@@ -51,6 +54,34 @@ var deploySiteArgs *deploySiteArgsBag
 var deployMongoDsbArgs *deployServiceArgsBag
 var deployK8sPsbArgs *deployServiceArgsBag
 
+type applyManifestsArgsBag struct {
+	filename *string
+}
+
+var applyManifestsArgs *applyManifestsArgsBag
+
+type undoArgsBag struct {
+	journal *string
+}
+
+var undoArgs *undoArgsBag
+
+type snapshotVolumeArgsBag struct {
+	pvc           *string
+	snapshot      *string
+	snapshotClass *string
+	wait          *bool
+}
+
+var snapshotVolumeArgs *snapshotVolumeArgsBag
+
+type restoreVolumeArgsBag struct {
+	snapshot *string
+	pvc      *string
+}
+
+var restoreVolumeArgs *restoreVolumeArgsBag
+
 type UICommandAddDockerArtifactRegistry struct {
 	SiteId   string `json:"siteId"`
 	Name     string `json:"name"`
@@ -368,6 +399,76 @@ func deployK8sPsbCommandExecutor(ctx *cmd.DeployerContext) error {
 	return nil
 }
 
+func applyManifestsCommandExecutor(ctx *cmd.DeployerContext) error {
+	if *applyManifestsArgs.filename == "" {
+		return errors.New("manifests file name is missing, use -f")
+	}
+
+	reader := os.Stdin
+	if *applyManifestsArgs.filename != "-" {
+		f, err := os.Open(*applyManifestsArgs.filename)
+		if err != nil {
+			return fmt.Errorf("Failed opening manifests file %s - %s", *applyManifestsArgs.filename, err.Error())
+		}
+		defer f.Close()
+		reader = f
+	}
+
+	results, err := ctx.Client.ApplyManifests(reader)
+	for _, result := range results {
+		fmt.Printf("%s %s %s\n", result.Object.GetKind(), result.Object.GetName(), result.Operation)
+	}
+	return err
+}
+
+func undoCommandExecutor(ctx *cmd.DeployerContext) error {
+	if *undoArgs.journal == "" {
+		return errors.New("journal file name is missing, use -journal")
+	}
+	if _, err := os.Stat(*undoArgs.journal); err != nil {
+		return fmt.Errorf("Failed reading journal %s - %s", *undoArgs.journal, err.Error())
+	}
+	if err := ctx.Client.Undo(k8sClient.NewFileJournal(*undoArgs.journal)); err != nil {
+		return err
+	}
+	fmt.Printf("Changes recorded in %s have been undone\n", *undoArgs.journal)
+	return nil
+}
+
+func snapshotVolumeCommandExecutor(ctx *cmd.DeployerContext) error {
+	if *snapshotVolumeArgs.pvc == "" {
+		return errors.New("persistent volume claim name is missing, use -pvc")
+	}
+	snapshotName := *snapshotVolumeArgs.snapshot
+	if snapshotName == "" {
+		snapshotName = *snapshotVolumeArgs.pvc + "-" + time.Now().UTC().Format("20060102150405")
+	}
+	_, err := ctx.Client.SnapshotPersistentVolumeClaim(*snapshotVolumeArgs.pvc, snapshotName, *snapshotVolumeArgs.snapshotClass)
+	if err != nil {
+		return err
+	}
+	if *snapshotVolumeArgs.wait {
+		if _, err = ctx.Client.WaitForVolumeSnapshotToBeReady(snapshotName, 60, 5*time.Second); err != nil {
+			return err
+		}
+		fmt.Printf("Volume snapshot %s of %s is ready\n", snapshotName, *snapshotVolumeArgs.pvc)
+		return nil
+	}
+	fmt.Printf("Volume snapshot %s of %s was created\n", snapshotName, *snapshotVolumeArgs.pvc)
+	return nil
+}
+
+func restoreVolumeCommandExecutor(ctx *cmd.DeployerContext) error {
+	if *restoreVolumeArgs.snapshot == "" || *restoreVolumeArgs.pvc == "" {
+		return errors.New("snapshot or persistent volume claim name is missing, use -snapshot and -pvc")
+	}
+	if _, err := ctx.Client.RestorePersistentVolumeClaim(*restoreVolumeArgs.pvc, *restoreVolumeArgs.snapshot, nil); err != nil {
+		return err
+	}
+	fmt.Printf("Persistent volume claim %s was restored from volume snapshot %s\n", *restoreVolumeArgs.pvc, *restoreVolumeArgs.snapshot)
+	return nil
+}
+
 func deploySiteCommandExecutor(ctx *cmd.DeployerContext) error {
 
 	// Validating command arguments
@@ -390,6 +491,36 @@ func deploySiteCommandExecutor(ctx *cmd.DeployerContext) error {
 		}
 	}
 
+	// Journaling the changes made to the cluster, so a deployment failing half way can be undone
+	journal, err := newDeployJournal(*deploySiteArgs.journal)
+	if err != nil {
+		return err
+	}
+	ctx.Client.Journal = journal
+	err = deploySite(ctx)
+	ctx.Client.Journal = nil
+	if err != nil && *deploySiteArgs.undoOnFailure {
+		fmt.Printf("Deploying site %s failed, undoing the changes made to the cluster\n", *deploySiteArgs.siteName)
+		if undoErr := ctx.Client.Undo(journal); undoErr != nil {
+			return fmt.Errorf("%s, undoing the changes failed as well - %s", err.Error(), undoErr.Error())
+		}
+	}
+	return err
+}
+
+// Returns the journal of a deployment, kept in memory unless a journal file is given. An existing journal file
+// is replaced
+func newDeployJournal(journalPath string) (k8sClient.JournalSink, error) {
+	if journalPath == "" {
+		return k8sClient.NewMemoryJournal(), nil
+	}
+	if err := os.Remove(journalPath); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("Failed replacing journal %s - %s", journalPath, err.Error())
+	}
+	return k8sClient.NewFileJournal(journalPath), nil
+}
+
+func deploySite(ctx *cmd.DeployerContext) error {
 	fmt.Printf("Creating namespace %s for site %s\n", ctx.Namespace, *deploySiteArgs.siteName)
 	err := createNamespace(ctx, ctx.Namespace, *deploySiteArgs.cleanup)
 	if err != nil {
@@ -497,11 +628,17 @@ func deploySiteCommandExecutor(ctx *cmd.DeployerContext) error {
 	return nil
 }
 
+// Returns the url the service is reachable at from outside the cluster. ClusterIP services are only reachable through
+// the api server proxy, using the cluster credentials, so those have no public root url
 func buildServiceRootUrl(svc *v1.Service, ctx *cmd.DeployerContext) (string, error) {
 	if svc.Spec.Type == v1.ServiceTypeLoadBalancer {
 		return "http://" + extractLoadBalancerAddress(svc.Status.LoadBalancer), nil
 	} else if svc.Spec.Type == v1.ServiceTypeNodePort {
 		return "http://" + ctx.ClusterIp + ":" + strconv.Itoa(svc.Spec.Ports[0].NodePort), nil
+	} else if svc.Spec.Type == v1.ServiceTypeClusterIP || svc.Spec.Type == "" {
+		return "", fmt.Errorf(
+			"Service %s is of type ClusterIP and is not reachable from outside the cluster, expose it as a NodePort or LoadBalancer service",
+			svc.Name)
 	} else {
 		return "", fmt.Errorf("Unsupported service type returned for orcs service %s\n", svc.Spec.Type)
 	}
@@ -606,6 +743,8 @@ func createOrcsServicesConfiguration(
 
 }
 
+// Orcs commands are authenticated with the admin basic auth, which the api server proxy consumes rather than forwards
+// to the service, so orcs is reached through its NodePort or LoadBalancer address rather than the proxy
 func getOrcsServiceUrl(ctx *cmd.DeployerContext) (string, error) {
 	orcsService, err := ctx.Client.GetServiceInfo("orcs")
 	if err != nil {
@@ -856,8 +995,55 @@ func buildSiteServiceConfiguration(
 	}
 }
 
+// Name of the cluster role allowing k8spsb to read the cluster nodes
+const k8sPsbClusterRoleName = "ocopea-k8spsb"
+
+// Allows the default service account of the namespace, which k8spsb runs with, to list the cluster nodes.
+// k8spsb discovers the cluster address and checks app services fit a node using them, nodes are cluster scoped
+// so a cluster role is needed on clusters enforcing RBAC
+func grantK8sPsbNodeAccess(ctx *cmd.DeployerContext) error {
+	clusterRole := k8sClient.Unstructured{
+		"apiVersion": "rbac.authorization.k8s.io/v1",
+		"kind":       "ClusterRole",
+		"metadata":   map[string]interface{}{"name": k8sPsbClusterRoleName},
+		"rules": []interface{}{map[string]interface{}{
+			"apiGroups": []interface{}{""},
+			"resources": []interface{}{"nodes"},
+			"verbs":     []interface{}{"get", "list"},
+		}},
+	}
+	clusterRoleBinding := k8sClient.Unstructured{
+		"apiVersion": "rbac.authorization.k8s.io/v1",
+		"kind":       "ClusterRoleBinding",
+		"metadata":   map[string]interface{}{"name": k8sPsbClusterRoleName + "-" + ctx.Namespace},
+		"roleRef": map[string]interface{}{
+			"apiGroup": "rbac.authorization.k8s.io",
+			"kind":     "ClusterRole",
+			"name":     k8sPsbClusterRoleName,
+		},
+		"subjects": []interface{}{map[string]interface{}{
+			"kind":      "ServiceAccount",
+			"name":      "default",
+			"namespace": ctx.Namespace,
+		}},
+	}
+	for _, obj := range []k8sClient.Unstructured{clusterRole, clusterRoleBinding} {
+		result, err := ctx.Client.Apply(obj)
+		if err != nil {
+			return fmt.Errorf("Failed applying %s %s - %s", obj.GetKind(), obj.GetName(), err.Error())
+		}
+		log.Printf("%s %s %s\n", obj.GetKind(), obj.GetName(), result.Operation)
+	}
+	return nil
+}
+
 func deployK8sPsbOrcs(ctx *cmd.DeployerContext, exposePublic bool) (*v1.Service, error) {
 	fmt.Println("Deploying k8s-psb")
+	// Not being allowed to grant the access (e.g. on clusters without RBAC) does not fail the deployment, k8spsb
+	// uses the LOCAL_CLUSTER_IP it is given and skips checking nodes it can't list
+	if err := grantK8sPsbNodeAccess(ctx); err != nil {
+		log.Printf("k8spsb may not be able to list the cluster nodes - %s\n", err.Error())
+	}
 	// Verifying site is registered
 	svc, err := deployService(
 		ctx.Client,
@@ -1155,6 +1341,8 @@ func defineDeploySiteCommand() *cmd.DeployerCommand {
 		cleanup:                   cmd.FlagSet.Bool("cleanup", false, "Cleanup Namespace before deploying"),
 		verboseSiteLogging:        cmd.FlagSet.Bool("verbose-site-logging", false, "Make the site logging verbose"),
 		customPostgresServiceName: cmd.FlagSet.String("custom-pg-service", "", "Customer provided PG service"),
+		journal:                   cmd.FlagSet.String("journal", "", "File recording the changes made to the cluster, for undoing them later"),
+		undoOnFailure:             cmd.FlagSet.Bool("undo-on-failure", false, "Undo the changes made to the cluster when the deployment fails"),
 	}
 	return cmd
 }
@@ -1186,6 +1374,66 @@ func defineDeployK8sPsbCommand() *cmd.DeployerCommand {
 	return cmd
 }
 
+func defineApplyManifestsCommand() *cmd.DeployerCommand {
+	cmd := &cmd.DeployerCommand{
+		Name:     "apply",
+		Executor: applyManifestsCommandExecutor,
+	}
+
+	cmd.FlagSet = flag.NewFlagSet(cmd.Name, flag.ExitOnError)
+
+	applyManifestsArgs = &applyManifestsArgsBag{
+		filename: cmd.FlagSet.String("f", "", "Yaml or json manifests file to apply, - for stdin"),
+	}
+	return cmd
+}
+
+func defineUndoCommand() *cmd.DeployerCommand {
+	cmd := &cmd.DeployerCommand{
+		Name:     "undo",
+		Executor: undoCommandExecutor,
+	}
+
+	cmd.FlagSet = flag.NewFlagSet(cmd.Name, flag.ExitOnError)
+
+	undoArgs = &undoArgsBag{
+		journal: cmd.FlagSet.String("journal", "", "Journal file of the changes to undo, see the journal flag of deploy-site"),
+	}
+	return cmd
+}
+
+func defineSnapshotVolumeCommand() *cmd.DeployerCommand {
+	cmd := &cmd.DeployerCommand{
+		Name:     "snapshot-volume",
+		Executor: snapshotVolumeCommandExecutor,
+	}
+
+	cmd.FlagSet = flag.NewFlagSet(cmd.Name, flag.ExitOnError)
+
+	snapshotVolumeArgs = &snapshotVolumeArgsBag{
+		pvc:           cmd.FlagSet.String("pvc", "", "Persistent volume claim to snapshot"),
+		snapshot:      cmd.FlagSet.String("snapshot", "", "Name of the volume snapshot, the claim name and time by default"),
+		snapshotClass: cmd.FlagSet.String("snapshot-class", "", "Volume snapshot class, the default class of the cluster by default"),
+		wait:          cmd.FlagSet.Bool("wait", true, "Wait for the snapshot to be ready"),
+	}
+	return cmd
+}
+
+func defineRestoreVolumeCommand() *cmd.DeployerCommand {
+	cmd := &cmd.DeployerCommand{
+		Name:     "restore-volume",
+		Executor: restoreVolumeCommandExecutor,
+	}
+
+	cmd.FlagSet = flag.NewFlagSet(cmd.Name, flag.ExitOnError)
+
+	restoreVolumeArgs = &restoreVolumeArgsBag{
+		snapshot: cmd.FlagSet.String("snapshot", "", "Volume snapshot to restore"),
+		pvc:      cmd.FlagSet.String("pvc", "", "Name of the persistent volume claim to create from the snapshot"),
+	}
+	return cmd
+}
+
 func main() {
 
 	f, err := os.OpenFile("deployer.log", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
@@ -1201,6 +1449,10 @@ func main() {
 		defineDeploySiteCommand(),
 		defineDeployK8sPsbCommand(),
 		defineDeployMongoDsbCommand(),
+		defineApplyManifestsCommand(),
+		defineUndoCommand(),
+		defineSnapshotVolumeCommand(),
+		defineRestoreVolumeCommand(),
 	}
 
 	// Executing the command selected by the user or show prompt
@@ -1428,18 +1680,126 @@ func deployService(
 
 	}
 
-	_, err = client.DeployReplicationController(serviceName, rcRequest, force)
+	// In force mode the existing replication controller is updated, deploying waits for its pods then
+	if force {
+		if err = applyReplicationController(client, rcRequest); err != nil {
+			return nil, fmt.Errorf("Failed applying replication controller for %s - %s", serviceName, err.Error())
+		}
+	}
+	rc, err := client.DeployReplicationController(serviceName, rcRequest, force)
 	if err != nil {
 		return nil, fmt.Errorf("Failed creating replication controller for %s - %s", serviceName, err.Error())
 	}
 
+	// Failing to record the event does not fail the deployment
+	_, err = k8sClient.NewEventRecorder(client, "ocopea-deployer").Eventf(
+		k8sClient.ObjectReferenceTo("ReplicationController", &rc.ObjectMeta),
+		v1.EventTypeNormal,
+		"ServiceDeployed",
+		"Service %s deployed with image %s",
+		serviceName,
+		imageName)
+	if err != nil {
+		log.Printf("Failed recording deployment of %s - %s\n", serviceName, err.Error())
+	}
+
 	return svc, nil
 
 }
 
-func deployK8SService(client *k8sClient.Client, svc *v1.Service, force bool) (*v1.Service, error) {
-	svc, err := client.CreateService(svc, force)
+// Maximal time to wait for a replaced pod of a redeployed service to be ready
+const podReplacementTimeout = 5 * time.Minute
+
+// Applies the replication controller of a redeployed service. Replication controllers don't update the pods
+// they already created, so the pods are replaced when their template changed
+func applyReplicationController(client *k8sClient.Client, rc *v1.ReplicationController) error {
+	result, err := client.Apply(rc)
+	if err != nil {
+		return err
+	}
+	log.Printf("replication controller %s %s\n", rc.Name, result.Operation)
+	if _, templateChanged := k8sClient.NestedField(result.Patch, "spec", "template"); templateChanged {
+		fmt.Printf("Configuration of %s changed, replacing its pods\n", rc.Name)
+		return replacePods(client, rc)
+	}
+	return nil
+}
+
+// Replaces the pods of the replication controller one at a time, the next pod is deleted only once the replication
+// controller created a ready replacement for the previous one, so the service keeps serving while redeployed
+func replacePods(client *k8sClient.Client, rc *v1.ReplicationController) error {
+	previousPods, err := client.ListPodsInfo(rc.Spec.Selector)
 	if err != nil {
+		return err
+	}
+	previousPodNames := make(map[string]bool, len(previousPods))
+	for _, pod := range previousPods {
+		previousPodNames[pod.Name] = true
+	}
+
+	podsResource := k8sClient.GroupVersionResource{Version: "v1", Resource: "pods"}
+	for i, pod := range previousPods {
+		fmt.Printf("Replacing pod %s of %s (%d/%d)\n", pod.Name, rc.Name, i+1, len(previousPods))
+		if _, err = client.DeletePod(pod.Name); err != nil {
+			return err
+		}
+		_, err = k8sClient.WaitFor(
+			context.Background(),
+			client.PodListGetter(rc.Spec.Selector),
+			replacementPodsReady(previousPodNames, i+1),
+			k8sClient.WaitOptions{
+				Description: fmt.Sprintf("replacement of pod %s of %s to be ready", pod.Name, rc.Name),
+				Timeout:     podReplacementTimeout,
+				Interval:    2 * time.Second,
+				Watch:       client.WatchTrigger(podsResource, true, rc.Spec.Selector),
+			})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Met once the given number of pods not among the previous pods are running and ready.
+// Fails in case a replacement won't run, e.g. when its image can't be pulled
+func replacementPodsReady(previousPodNames map[string]bool, replacements int) k8sClient.Condition {
+	return func(obj interface{}) (bool, error) {
+		ready := 0
+		for _, pod := range obj.([]*v1.Pod) {
+			if previousPodNames[pod.Name] {
+				continue
+			}
+			running, err := k8sClient.PodRunning(pod)
+			if err != nil {
+				return false, err
+			}
+			if running && isPodReady(pod) {
+				ready++
+			}
+		}
+		return ready >= replacements, nil
+	}
+}
+
+func isPodReady(pod *v1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// Creates the service, in force mode an existing service is updated to match the desired one
+func deployK8SService(client *k8sClient.Client, svc *v1.Service, force bool) (*v1.Service, error) {
+	var err error
+	if force {
+		var result *k8sClient.ApplyResult
+		if result, err = client.Apply(svc); err != nil {
+			return nil, err
+		}
+		log.Printf("service %s %s\n", svc.Name, result.Operation)
+	} else if svc, err = client.CreateService(svc, false); err != nil {
 		return nil, err
 	}
 
@@ -1552,7 +1912,7 @@ func getSiteIdOrcs(ctx *cmd.DeployerContext, siteUrn string) (error, string) {
 	if err != nil {
 		log.Println(err)
 	}
-	log.Printf(string(str))
+	log.Println(string(str))
 
 	for _, currSite := range sitesArray {
 		if currSite.Urn == siteUrn {
@@ -1565,18 +1925,28 @@ func getSiteIdOrcs(ctx *cmd.DeployerContext, siteUrn string) (error, string) {
 
 func waitForServiceToBeStarted(ctx *cmd.DeployerContext, serviceEndpoint string) error {
 	fmt.Printf("Waiting for service %s to start\n", serviceEndpoint)
-	var err error = nil
-	for i := 1; i < 100; i++ {
-		err = verifyOrcsServiceHasStarted(ctx, serviceEndpoint)
-		if err == nil {
-			fmt.Printf("Service %s started successfully\n", serviceEndpoint)
-			return nil
-		} else {
-			log.Printf("service %s is not ready yet - %s\n", serviceEndpoint, err.Error())
-		}
-		time.Sleep(5 * time.Second)
+	var lastError error
+	serviceStarted := func() (interface{}, error) {
+		lastError = verifyOrcsServiceHasStarted(ctx, serviceEndpoint)
+		return nil, lastError
+	}
+	started := func(obj interface{}) (bool, error) {
+		return true, nil
+	}
+	_, err := k8sClient.WaitFor(context.Background(), serviceStarted, started, k8sClient.WaitOptions{
+		Description:       "service endpoint " + serviceEndpoint + " to start",
+		Timeout:           500 * time.Second,
+		Interval:          5 * time.Second,
+		RetryGetterErrors: true,
+		Progress: func(obj interface{}, attempt int, elapsed time.Duration) {
+			log.Printf("service %s is not ready yet, attempt %d - %v\n", serviceEndpoint, attempt, lastError)
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("service endpoint %s failed starting in a timely fasion - %s", serviceEndpoint, err.Error())
 	}
-	return fmt.Errorf("service endpoint %s failed starting in a timely fasion - %s", serviceEndpoint, err.Error())
+	fmt.Printf("Service %s started successfully\n", serviceEndpoint)
+	return nil
 }
 
 func verifyOrcsServiceHasStarted(ctx *cmd.DeployerContext, serviceEndpoint string) error {
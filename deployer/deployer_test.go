@@ -0,0 +1,202 @@
+// Copyright (c) [2017] Dell Inc. or its subsidiaries. All Rights Reserved.
+package main
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	k8sClient "ocopea/kubernetes/client"
+	"ocopea/kubernetes/client/clienttest"
+	"ocopea/kubernetes/client/v1"
+	"ocopea/kubernetes/deployer/cmd"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Starts a fake orcs service recording the credentials of the requests reaching it
+func newOrcsServer(t *testing.T, credentials *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userName, password, _ := r.BasicAuth()
+		*credentials = append(*credentials, userName+":"+password)
+		io.WriteString(w, `{"state":"RUNNING"}`)
+	}))
+}
+
+func newDeployerContext(t *testing.T, s *clienttest.Server, orcs *v1.Service) *cmd.DeployerContext {
+	c, err := k8sClient.NewClient(s.URL, "ocopea", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.CreateNamespace(&v1.Namespace{ObjectMeta: v1.ObjectMeta{Name: "ocopea"}}, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.CreateService(orcs, false); err != nil {
+		t.Fatal(err)
+	}
+	return &cmd.DeployerContext{Client: c, DeploymentType: "local", Namespace: "ocopea", ClusterIp: "127.0.0.1"}
+}
+
+func TestOrcsCommandsCarryAdminCredentials(t *testing.T) {
+	var credentials []string
+	orcs := newOrcsServer(t, &credentials)
+	defer orcs.Close()
+	_, port, err := net.SplitHostPort(strings.TrimPrefix(orcs.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodePort, _ := strconv.Atoi(port)
+
+	s := clienttest.NewServer()
+	defer s.Close()
+	ctx := newDeployerContext(t, s, &v1.Service{
+		ObjectMeta: v1.ObjectMeta{Name: "orcs"},
+		Spec: v1.ServiceSpec{
+			Type:  v1.ServiceTypeNodePort,
+			Ports: []v1.ServicePort{{Port: 8080, NodePort: nodePort}},
+		},
+	})
+
+	if err := verifyOrcsServiceHasStarted(ctx, "hub"); err != nil {
+		t.Fatal(err)
+	}
+	if len(credentials) != 1 || credentials[0] != OCOPEA_ADMIN_USERNAME+":"+OCOPEA_ADMIN_PASSWORD {
+		t.Errorf("expected the orcs request to carry the admin credentials, got %v", credentials)
+	}
+}
+
+// The api server proxy does not forward the Authorization header, so ClusterIP orcs services are not reached at all
+// rather than being sent requests without credentials
+func TestClusterIPOrcsIsNotReachedThroughTheProxy(t *testing.T) {
+	var credentials []string
+	orcs := newOrcsServer(t, &credentials)
+	defer orcs.Close()
+
+	s := clienttest.NewServer()
+	defer s.Close()
+	ctx := newDeployerContext(t, s, &v1.Service{
+		ObjectMeta: v1.ObjectMeta{Name: "orcs"},
+		Spec:       v1.ServiceSpec{Type: v1.ServiceTypeClusterIP, Ports: []v1.ServicePort{{Port: 8080}}},
+	})
+	s.Proxy("services", "ocopea", "orcs", orcs.Config.Handler)
+
+	err := verifyOrcsServiceHasStarted(ctx, "hub")
+	if err == nil || !strings.Contains(err.Error(), "ClusterIP") {
+		t.Errorf("expected a ClusterIP orcs service to be rejected, got %v", err)
+	}
+	if len(credentials) != 0 {
+		t.Errorf("expected no request to reach orcs, got %d requests", len(credentials))
+	}
+}
+
+func TestApplyReplicationControllerReplacesPodsOneAtATime(t *testing.T) {
+	s := clienttest.NewServer()
+	defer s.Close()
+	ctx := newDeployerContext(t, s, &v1.Service{
+		ObjectMeta: v1.ObjectMeta{Name: "orcs"},
+		Spec:       v1.ServiceSpec{Type: v1.ServiceTypeNodePort, Ports: []v1.ServicePort{{Port: 8080}}},
+	})
+
+	replicas := 2
+	rc := &v1.ReplicationController{
+		ObjectMeta: v1.ObjectMeta{Name: "orcs"},
+		Spec: v1.ReplicationControllerSpec{
+			Replicas: &replicas,
+			Selector: map[string]string{"app": "orcs"},
+			Template: &v1.PodTemplateSpec{
+				ObjectMeta: v1.ObjectMeta{Labels: map[string]string{"app": "orcs"}},
+				Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "orcs", Image: "ocopea/orcs-k8s-runner:1"}}},
+			},
+		},
+	}
+	if err := applyReplicationController(ctx.Client, rc); err != nil {
+		t.Fatal(err)
+	}
+	previousPods, err := ctx.Client.ListPodsInfo(rc.Spec.Selector)
+	if err != nil || len(previousPods) != 2 {
+		t.Fatalf("expected the rc to create 2 pods, got %d - %v", len(previousPods), err)
+	}
+
+	rc.Spec.Template.Spec.Containers[0].Image = "ocopea/orcs-k8s-runner:2"
+	if err := applyReplicationController(ctx.Client, rc); err != nil {
+		t.Fatal(err)
+	}
+
+	pods, err := ctx.Client.ListPodsInfo(rc.Spec.Selector)
+	if err != nil || len(pods) != 2 {
+		t.Fatalf("expected 2 pods after replacing them, got %d - %v", len(pods), err)
+	}
+	for _, pod := range pods {
+		if pod.Name == previousPods[0].Name || pod.Name == previousPods[1].Name {
+			t.Errorf("expected pod %s to be replaced", pod.Name)
+		}
+	}
+
+	// The replacement of the first pod is waited for before the second pod is deleted
+	var deletes []string
+	listedBetweenDeletes := false
+	for _, request := range s.Requests() {
+		if strings.HasPrefix(request, "DELETE ") {
+			deletes = append(deletes, request)
+		} else if len(deletes) == 1 && request == "GET /api/v1/namespaces/ocopea/pods" {
+			listedBetweenDeletes = true
+		}
+	}
+	if !listedBetweenDeletes {
+		t.Errorf("expected the pods to be listed between the deletes, got %v", s.Requests())
+	}
+	expected := []string{
+		"DELETE /api/v1/namespaces/ocopea/pods/" + previousPods[0].Name,
+		"DELETE /api/v1/namespaces/ocopea/pods/" + previousPods[1].Name,
+	}
+	if !reflect.DeepEqual(deletes, expected) {
+		t.Errorf("expected the pods to be deleted one by one with %v, got %v", expected, deletes)
+	}
+}
+
+func TestGrantK8sPsbNodeAccess(t *testing.T) {
+	s := clienttest.NewServer()
+	defer s.Close()
+	ctx := newDeployerContext(t, s, &v1.Service{
+		ObjectMeta: v1.ObjectMeta{Name: "orcs"},
+		Spec:       v1.ServiceSpec{Type: v1.ServiceTypeNodePort, Ports: []v1.ServicePort{{Port: 8080}}},
+	})
+
+	// Granting again on redeployments leaves the objects as they are
+	for i := 0; i < 2; i++ {
+		if err := grantK8sPsbNodeAccess(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rbacResource := func(resource string) k8sClient.GroupVersionResource {
+		return k8sClient.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: resource}
+	}
+	role, err := ctx.Client.Resource(rbacResource("clusterroles"), false).Get("ocopea-k8spsb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules, _ := k8sClient.NestedField(role, "rules")
+	if !reflect.DeepEqual(rules, []interface{}{map[string]interface{}{
+		"apiGroups": []interface{}{""},
+		"resources": []interface{}{"nodes"},
+		"verbs":     []interface{}{"get", "list"},
+	}}) {
+		t.Errorf("expected the cluster role to allow listing nodes, got %v", rules)
+	}
+
+	binding, err := ctx.Client.Resource(rbacResource("clusterrolebindings"), false).Get("ocopea-k8spsb-ocopea")
+	if err != nil {
+		t.Fatal(err)
+	}
+	subjects, _ := k8sClient.NestedField(binding, "subjects")
+	if !reflect.DeepEqual(subjects, []interface{}{map[string]interface{}{
+		"kind":      "ServiceAccount",
+		"name":      "default",
+		"namespace": "ocopea",
+	}}) {
+		t.Errorf("expected the cluster role to be bound to the default service account of the namespace, got %v", subjects)
+	}
+}
@@ -12,8 +12,10 @@ import (
 	"log"
 	"net/http"
 	kubernetesClient "ocopea/kubernetes/client"
+	"ocopea/kubernetes/client/resource"
 	"ocopea/kubernetes/client/types"
 	"ocopea/kubernetes/client/v1"
+	"ocopea/kubernetes/client/validation"
 	"os"
 	"strconv"
 	"strings"
@@ -115,7 +117,17 @@ var gPsbInfo = psbInfo{
 	Description:           "Ocopea Kubernetes Paas Broker",
 	AppServiceIdMaxLength: 24,
 }
+
+// Headers naming the Ocopea user a request is made for and the groups of the user (comma separated)
+const (
+	ocopeaUserHeader   = "Ocopea-User"
+	ocopeaGroupsHeader = "Ocopea-Groups"
+)
+
+const serviceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
 var kClient kubernetesClient.ClientInterface
+var eventRecorder *kubernetesClient.EventRecorder
 var deploymentType string
 var gLocalClusterIp string
 var gInClusterServiceAddr string
@@ -203,8 +215,12 @@ func handleAppServiceInfo(w http.ResponseWriter, r *http.Request) *deployError {
 			}
 		}
 
+		client, uErr := requestClient(r)
+		if uErr != nil {
+			return uErr
+		}
 		// Returning 404 if none exist
-		exists, _ := kClient.CheckServiceExists(appUniqueName)
+		exists, _ := client.CheckServiceExists(appUniqueName)
 		if !exists {
 			return &deployError{
 				httpStatusCode: http.StatusNotFound,
@@ -213,14 +229,14 @@ func handleAppServiceInfo(w http.ResponseWriter, r *http.Request) *deployError {
 		}
 
 		// In order to delete a service we need to delete both replication controller and service
-		err := kClient.DeleteReplicationController(appUniqueName)
+		err := client.DeleteReplicationController(appUniqueName)
 		if err != nil {
 			return &deployError{
 				httpStatusCode: http.StatusInternalServerError,
 				message:        fmt.Sprintf("Failed deleting replication controller %s", appUniqueName),
 			}
 		}
-		err = kClient.DeleteService(appUniqueName)
+		err = client.DeleteService(appUniqueName)
 		if err != nil {
 			return &deployError{
 				httpStatusCode: http.StatusInternalServerError,
@@ -228,6 +244,13 @@ func handleAppServiceInfo(w http.ResponseWriter, r *http.Request) *deployError {
 			}
 		}
 
+		recordAppServiceEvent(
+			&v1.ObjectReference{Kind: "ReplicationController", Name: appUniqueName},
+			v1.EventTypeNormal,
+			"AppServiceDeleted",
+			"App service %s deleted",
+			appUniqueName)
+
 		w.WriteHeader(200)
 		io.WriteString(w, "{\"status\":0,\"message\":\"Oh Yeah!\"}")
 		fmt.Printf("App Service %s deleted successfully\n", appUniqueName)
@@ -455,6 +478,59 @@ func appServiceInfoHandler(w http.ResponseWriter, r *http.Request) {
 		handleServerError(w, err, r)
 	}
 }
+
+// Compares the replication controller the posted manifest would deploy with the live one of the app service,
+// reporting the differences as json or as text when text/plain is accepted
+func handleAppServiceDiff(w http.ResponseWriter, r *http.Request) *deployError {
+	if r.Method != "POST" {
+		return &deployError{
+			httpStatusCode: http.StatusMethodNotAllowed,
+			message:        "method " + r.Method + " Not allowed",
+		}
+	}
+	appManifest, uErr := decodeAppManifest(r)
+	if uErr != nil {
+		return uErr
+	}
+	if appServiceId := parseRequestVars(r)["appServiceId"]; appServiceId != appManifest.AppServiceId {
+		return &deployError{
+			httpStatusCode: http.StatusBadRequest,
+			message:        fmt.Sprintf("manifest of app service %s posted for %s", appManifest.AppServiceId, appServiceId),
+		}
+	}
+	rc, uErr := buildAppServiceReplicationController(appManifest)
+	if uErr != nil {
+		return uErr
+	}
+
+	client, uErr := requestClient(r)
+	if uErr != nil {
+		return uErr
+	}
+	diff, err := client.Diff(rc)
+	if err != nil {
+		return &deployError{httpStatusCode: http.StatusInternalServerError, message: err.Error()}
+	}
+	if strings.Contains(r.Header.Get("Accept"), "text/plain") {
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, diff.String())
+		return nil
+	}
+	if err = json.NewEncoder(w).Encode(diff); err != nil {
+		return &deployError{httpStatusCode: http.StatusInternalServerError, message: "Failed encoding diff " + err.Error()}
+	}
+	return nil
+}
+
+func appServiceDiffHandler(w http.ResponseWriter, r *http.Request) {
+	printHandler(r)
+	w.Header().Set("Content-Type", "application/json")
+	err := handleAppServiceDiff(w, r)
+	if err != nil {
+		handleServerError(w, err, r)
+	}
+}
+
 func appServiceLogsHandler(w http.ResponseWriter, r *http.Request) {
 	printHandler(r)
 	w.Header().Set("Content-Type", "application/json")
@@ -473,6 +549,72 @@ func logsDataHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// impersonationPolicy decides which users and groups named by requests are impersonated. Nothing authenticates the
+// headers naming them, so impersonation is off unless enabled and only allowlisted users and groups are accepted
+type impersonationPolicy struct {
+	enabled bool
+	users   map[string]bool
+	groups  map[string]bool
+}
+
+var gImpersonation impersonationPolicy
+
+// Builds the policy out of comma separated allowlists of users and groups
+func newImpersonationPolicy(enabled bool, users string, groups string) impersonationPolicy {
+	return impersonationPolicy{enabled: enabled, users: toSet(splitList(users)), groups: toSet(splitList(groups))}
+}
+
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func toSet(items []string) map[string]bool {
+	set := make(map[string]bool)
+	for _, item := range items {
+		set[item] = true
+	}
+	return set
+}
+
+// Returns an error in case the user or one of the groups may not be impersonated. Kubernetes system users and groups
+// (e.g. system:masters) are always rejected, even when allowlisted
+func (p impersonationPolicy) check(userName string, groups []string) error {
+	if strings.HasPrefix(userName, "system:") || !p.users[userName] {
+		return fmt.Errorf("impersonating user %s is not allowed", userName)
+	}
+	for _, group := range groups {
+		if strings.HasPrefix(group, "system:") || !p.groups[group] {
+			return fmt.Errorf("impersonating group %s is not allowed", group)
+		}
+	}
+	return nil
+}
+
+// Returns the client acting on behalf of the Ocopea user the request is made for, so cluster RBAC and audit logs
+// reflect who deployed which app copy. Requests that don't name a user, or name one while impersonation is disabled,
+// are served with the credentials of k8spsb
+func requestClient(r *http.Request) (kubernetesClient.ClientInterface, *deployError) {
+	userName := r.Header.Get(ocopeaUserHeader)
+	if userName == "" {
+		return kClient, nil
+	}
+	if !gImpersonation.enabled {
+		log.Printf("Ignoring %s header of %s %s, impersonation is disabled\n", ocopeaUserHeader, r.Method, r.URL.Path)
+		return kClient, nil
+	}
+	groups := splitList(r.Header.Get(ocopeaGroupsHeader))
+	if err := gImpersonation.check(userName, groups); err != nil {
+		return nil, &deployError{httpStatusCode: http.StatusForbidden, message: err.Error()}
+	}
+	return kClient.Impersonate(userName, groups), nil
+}
+
 func getMandatoryHeader(r *http.Request, headerName string) (string, *deployError) {
 	var headerValue string = r.Header.Get(headerName)
 	if len(headerValue) == 0 {
@@ -499,11 +641,9 @@ func deployApp(w http.ResponseWriter, r *http.Request) *deployError {
 		if uErr != nil {
 			return uErr
 		}
-		dec := json.NewDecoder(r.Body)
-		var appManifest deployAppServiceManifestDTO
-		err := dec.Decode(&appManifest)
-		if err != nil {
-			return &deployError{httpStatusCode: http.StatusInternalServerError, message: "Failed decoding app manifest"}
+		appManifest, uErr := decodeAppManifest(r)
+		if uErr != nil {
+			return uErr
 		}
 
 		log.Printf("Running %s with image %s version %s on route %s and %d dsb types bindings",
@@ -513,104 +653,29 @@ func deployApp(w http.ResponseWriter, r *http.Request) *deployError {
 			appManifest.Route,
 			len(appManifest.ServiceBindings))
 
-		envVarFilters := make(map[string]string)
-		bindingsVar := v1.EnvVar{Name: "NAZ_MS_API_K8S_BINDINGS"}
-		if len(appManifest.ServiceBindings) > 0 {
-			//            for dsbName, dsbMap := range appManifest.ServiceBindings {
-			//
-			//            }
-			appBindingEnv, err := json.Marshal(appManifest.ServiceBindings)
-			if err != nil {
-				return &deployError{httpStatusCode: http.StatusInternalServerError, message: "Failed parsing bindings"}
-			}
-
-			strVar := string(appBindingEnv)
-			bindingsVar.Value = strVar
-			log.Println(strVar)
-
-			// Preparing binding keys to do the replace with env vars
-			for _, svcBindingsArr := range appManifest.ServiceBindings {
-				for _, svcBinding := range svcBindingsArr {
-					for k, v := range svcBinding.BindInfo {
-						envVarFilters[fmt.Sprintf("${%s.%s}", svcBinding.ServiceName, k)] = v
-					}
-				}
-			}
-		}
-
-		k8sDsbBindings := appManifest.ServiceBindings["k8s-dsb"]
-		if k8sDsbBindings != nil {
-			log.Println("Found k8s-dsb bindings")
-			//for
+		rc, uErr := buildAppServiceReplicationController(appManifest)
+		if uErr != nil {
+			return uErr
 		}
-
-		appUniqueName := appManifest.AppServiceId
-
-		envVar := []v1.EnvVar{bindingsVar}
-
-		// Filter and apply environment variables
-		if appManifest.EnvironmentVariables != nil {
-			for k, v := range appManifest.EnvironmentVariables {
-
-				// filter env var values
-				filteredValue := v
-
-				for filterKey, fValue := range envVarFilters {
-					filteredValue = strings.Replace(filteredValue, filterKey, fValue, -1)
-				}
-
-				envVar = append(
-					envVar,
-					v1.EnvVar{
-						Name:  k,
-						Value: filteredValue,
-					},
-				)
-			}
+		appUniqueName := rc.Name
+		if uErr = checkAppServiceFits(rc); uErr != nil {
+			return uErr
 		}
 
-		var replicas int = 1
-		// Building rc spec
-
-		spec := v1.ReplicationControllerSpec{}
-		spec.Replicas = &replicas
-		spec.Selector = make(map[string]string)
-		spec.Selector["app"] = appUniqueName
-		spec.Template = &v1.PodTemplateSpec{}
-		spec.Template.ObjectMeta = v1.ObjectMeta{}
-		spec.Template.ObjectMeta.Labels = make(map[string]string)
-		spec.Template.ObjectMeta.Labels["app"] = appUniqueName
-		spec.Template.ObjectMeta.Labels["nazKind"] = "app"
-
-		containerSpec := v1.Container{}
-		containerSpec.Name = appUniqueName
-
-		imageName := appManifest.ImageName
-		if len(appManifest.ImageVersion) != 0 {
-			imageName += ":" + appManifest.ImageVersion
+		client, uErr := requestClient(r)
+		if uErr != nil {
+			return uErr
 		}
-		containerSpec.Image = imageName
-		containerSpec.Env = envVar
-		//todo: 1) all ports
-		//todo: 2) allow no http port at all
-		//if (appManifest.HttpPort != nil){
-		containerSpec.Ports = []v1.ContainerPort{{ContainerPort: appManifest.HttpPort}}
-		//}
-
-		containers := []v1.Container{containerSpec}
-
-		spec.Template.Spec = v1.PodSpec{}
-		spec.Template.Spec.Containers = containers
-
-		// Create a replicationController object for running the app
-		rc := &v1.ReplicationController{}
-		rc.Name = appUniqueName
-		rc.Labels = make(map[string]string)
-		rc.Labels["nazKind"] = "app"
-		rc.Spec = spec
-
-		_, err = kClient.DeployReplicationController(appUniqueName, rc, false)
+		deployedRc, err := client.DeployReplicationController(appUniqueName, rc, false)
 		if err != nil {
+			recordAppServiceEvent(
+				&v1.ObjectReference{Kind: "ReplicationController", Name: appUniqueName},
+				v1.EventTypeWarning,
+				"AppServiceDeployFailed",
+				"Failed deploying app service %s with image %s - %s",
+				appUniqueName,
+				appManifest.ImageName,
+				err.Error())
 			return &deployError{httpStatusCode: http.StatusInternalServerError, message: err.Error()}
 		}
 
@@ -635,11 +700,19 @@ func deployApp(w http.ResponseWriter, r *http.Request) *deployError {
 
 		svc.Spec.Selector = map[string]string{"app": appUniqueName}
 
-		svc, err = kClient.CreateService(svc, false)
+		svc, err = client.CreateService(svc, false)
 		if err != nil {
 			return &deployError{httpStatusCode: http.StatusInternalServerError, message: "failed creating service " + appUniqueName + " : " + err.Error()}
 		}
 
+		recordAppServiceEvent(
+			kubernetesClient.ObjectReferenceTo("ReplicationController", &deployedRc.ObjectMeta),
+			v1.EventTypeNormal,
+			"AppServiceDeployed",
+			"App service %s deployed with image %s",
+			appUniqueName,
+			appManifest.ImageName)
+
 		// Track and print async...
 		go PrintService(svc)
 
@@ -652,6 +725,255 @@ func deployApp(w http.ResponseWriter, r *http.Request) *deployError {
 
 }
 
+// Decodes and validates the app service manifest of the request
+func decodeAppManifest(r *http.Request) (*deployAppServiceManifestDTO, *deployError) {
+	dec := json.NewDecoder(r.Body)
+	var appManifest deployAppServiceManifestDTO
+	err := dec.Decode(&appManifest)
+	if err != nil {
+		return nil, &deployError{httpStatusCode: http.StatusInternalServerError, message: "Failed decoding app manifest"}
+	}
+
+	// App service id is used for naming the k8s service and replication controller
+	idErrors := validation.ValidateName(
+		appManifest.AppServiceId,
+		gPsbInfo.AppServiceIdMaxLength,
+		validation.IsDNS1035Label,
+		"appServiceId")
+	if len(idErrors) > 0 {
+		return nil, &deployError{
+			httpStatusCode: http.StatusBadRequest,
+			message:        "invalid app service id - " + idErrors.ToError().Error(),
+		}
+	}
+	return &appManifest, nil
+}
+
+// Builds the replication controller running the app service described by the manifest
+func buildAppServiceReplicationController(appManifest *deployAppServiceManifestDTO) (*v1.ReplicationController, *deployError) {
+	envVarFilters := make(map[string]string)
+	bindingsVar := v1.EnvVar{Name: "NAZ_MS_API_K8S_BINDINGS"}
+	if len(appManifest.ServiceBindings) > 0 {
+		//            for dsbName, dsbMap := range appManifest.ServiceBindings {
+		//
+		//            }
+		appBindingEnv, err := json.Marshal(appManifest.ServiceBindings)
+		if err != nil {
+			return nil, &deployError{httpStatusCode: http.StatusInternalServerError, message: "Failed parsing bindings"}
+		}
+
+		strVar := string(appBindingEnv)
+		bindingsVar.Value = strVar
+		log.Println(strVar)
+
+		// Preparing binding keys to do the replace with env vars
+		for _, svcBindingsArr := range appManifest.ServiceBindings {
+			for _, svcBinding := range svcBindingsArr {
+				for k, v := range svcBinding.BindInfo {
+					envVarFilters[fmt.Sprintf("${%s.%s}", svcBinding.ServiceName, k)] = v
+				}
+			}
+		}
+	}
+
+	k8sDsbBindings := appManifest.ServiceBindings["k8s-dsb"]
+	if k8sDsbBindings != nil {
+		log.Println("Found k8s-dsb bindings")
+		//for
+	}
+
+	appUniqueName := appManifest.AppServiceId
+
+	envVar := []v1.EnvVar{bindingsVar}
+
+	// Filter and apply environment variables
+	if appManifest.EnvironmentVariables != nil {
+		for k, v := range appManifest.EnvironmentVariables {
+
+			// filter env var values
+			filteredValue := v
+
+			for filterKey, fValue := range envVarFilters {
+				filteredValue = strings.Replace(filteredValue, filterKey, fValue, -1)
+			}
+
+			envVar = append(
+				envVar,
+				v1.EnvVar{
+					Name:  k,
+					Value: filteredValue,
+				},
+			)
+		}
+	}
+
+	requests, uErr := appServiceRequests(appManifest.PsbSettings)
+	if uErr != nil {
+		return nil, uErr
+	}
+
+	var replicas int = 1
+	// Building rc spec
+
+	spec := v1.ReplicationControllerSpec{}
+	spec.Replicas = &replicas
+	spec.Selector = make(map[string]string)
+	spec.Selector["app"] = appUniqueName
+	spec.Template = &v1.PodTemplateSpec{}
+	spec.Template.ObjectMeta = v1.ObjectMeta{}
+	spec.Template.ObjectMeta.Labels = make(map[string]string)
+	spec.Template.ObjectMeta.Labels["app"] = appUniqueName
+	spec.Template.ObjectMeta.Labels["nazKind"] = "app"
+
+	containerSpec := v1.Container{}
+	containerSpec.Name = appUniqueName
+
+	imageName := appManifest.ImageName
+	if len(appManifest.ImageVersion) != 0 {
+		imageName += ":" + appManifest.ImageVersion
+	}
+	containerSpec.Image = imageName
+	containerSpec.Env = envVar
+	//todo: 1) all ports
+	//todo: 2) allow no http port at all
+	//if (appManifest.HttpPort != nil){
+	containerSpec.Ports = []v1.ContainerPort{{ContainerPort: appManifest.HttpPort}}
+	//}
+	containerSpec.Resources.Requests = requests
+
+	containers := []v1.Container{containerSpec}
+
+	spec.Template.Spec = v1.PodSpec{}
+	spec.Template.Spec.Containers = containers
+
+	// Create a replicationController object for running the app
+	rc := &v1.ReplicationController{}
+	rc.Name = appUniqueName
+	rc.Labels = make(map[string]string)
+	rc.Labels["nazKind"] = "app"
+	rc.Spec = spec
+	return rc, nil
+}
+
+// Psb settings holding the resources requested by the app service container, e.g. "500m" cpu and "512Mi" memory
+var appServiceResourceSettings = map[string]v1.ResourceName{
+	"cpu":    v1.ResourceCPU,
+	"memory": v1.ResourceMemory,
+}
+
+// Returns the resources requested by the app service according to the psb settings of the manifest
+func appServiceRequests(psbSettings map[string]string) (v1.ResourceList, *deployError) {
+	requests := v1.ResourceList{}
+	for setting, resourceName := range appServiceResourceSettings {
+		value, found := psbSettings[setting]
+		if !found {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, &deployError{
+				httpStatusCode: http.StatusBadRequest,
+				message:        fmt.Sprintf("invalid %s setting %s - %s", setting, value, err.Error()),
+			}
+		}
+		requests[resourceName] = *quantity
+	}
+	return requests, nil
+}
+
+// Refuses deploying an app service that could never be scheduled, since either no node has enough
+// allocatable resources for it even when nothing else runs on the node, or a resource quota of the space does not
+// leave room for it. The pods of a previous deployment of the app service are not counted against the quotas.
+// Failing to read nodes, pods or quotas does not fail the deployment, the scheduler will have its say then
+func checkAppServiceFits(rc *v1.ReplicationController) *deployError {
+	appUniqueName := rc.Name
+	requests := rc.Spec.Template.Spec.Containers[0].Resources.Requests
+	nodes, err := kClient.ListNodes(nil)
+	if err != nil {
+		log.Printf("Failed listing nodes, skipping capacity check of %s - %s", appUniqueName, err.Error())
+	} else if len(nodes) > 0 && !kubernetesClient.IsZeroResourceList(requests) {
+		var insufficient []v1.ResourceName
+		fits := false
+		for _, node := range nodes {
+			if node.Spec.Unschedulable {
+				continue
+			}
+			var nodeInsufficient []v1.ResourceName
+			if fits, nodeInsufficient = kubernetesClient.FitsNode(requests, node); fits {
+				break
+			}
+			insufficient = nodeInsufficient
+		}
+		if !fits && len(insufficient) > 0 {
+			return &deployError{
+				httpStatusCode: http.StatusUnprocessableEntity,
+				message: fmt.Sprintf(
+					"app service %s can never be scheduled, no node has enough allocatable %v even when nothing else runs on it",
+					appUniqueName,
+					insufficient),
+			}
+		}
+	}
+
+	quotas, err := kClient.ListResourceQuotas(nil)
+	if err != nil {
+		log.Printf("Failed listing resource quotas, skipping quota check of %s - %s", appUniqueName, err.Error())
+		return nil
+	}
+	if len(quotas) == 0 {
+		return nil
+	}
+	previousUsage, err := appServiceQuotaUsage(rc)
+	if err != nil {
+		log.Printf("Failed listing pods of %s, skipping quota check - %s", appUniqueName, err.Error())
+		return nil
+	}
+	podRequests := kubernetesClient.AddResourceLists(requests, v1.ResourceList{v1.ResourcePods: *resource.NewQuantity(1, resource.DecimalSI)})
+	for _, quota := range quotas {
+		quota = quota.DeepCopy()
+		quota.Status.Used = kubernetesClient.SubtractResourceLists(quota.Status.Used, previousUsage)
+		if fits, insufficient := kubernetesClient.FitsQuota(podRequests, quota); !fits {
+			return &deployError{
+				httpStatusCode: http.StatusUnprocessableEntity,
+				message:        fmt.Sprintf("app service %s exceeds resource quota %s, not enough %v left", appUniqueName, quota.Name, insufficient),
+			}
+		}
+	}
+	return nil
+}
+
+// Returns the quota usage of the running pods of a previous deployment of the app service, counting both the
+// plain resources and their requests. prefixed form
+func appServiceQuotaUsage(rc *v1.ReplicationController) (v1.ResourceList, error) {
+	pods, err := kClient.ListPodsInfo(rc.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	usage := v1.ResourceList{}
+	for _, pod := range pods {
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		podUsage := v1.ResourceList{v1.ResourcePods: *resource.NewQuantity(1, resource.DecimalSI)}
+		for name, quantity := range kubernetesClient.ContainerRequests(pod.Spec.Containers) {
+			podUsage[name] = quantity
+			podUsage["requests."+name] = quantity
+		}
+		usage = kubernetesClient.AddResourceLists(usage, podUsage)
+	}
+	return usage, nil
+}
+
+// Records an event about an app service, failing to record the event does not fail the operation
+func recordAppServiceEvent(ref *v1.ObjectReference, eventType string, reason string, messageFmt string, args ...interface{}) {
+	if eventRecorder == nil {
+		return
+	}
+	if _, err := eventRecorder.Eventf(ref, eventType, reason, messageFmt, args...); err != nil {
+		log.Printf("Failed recording event %s - %s", reason, err.Error())
+	}
+}
+
 func PrintService(s *v1.Service) {
 	s, err := kClient.WaitForServiceToStart(s.Name, 100, time.Second*3)
 	if err != nil {
@@ -693,7 +1015,12 @@ func main() {
 	// Parsing flags
 	k8sURL := flag.String("url", "https://kubernetes:443", "K8S remote api url")
 	k8sNamespace := flag.String("namespace", "ocopea", "K8S namespace to use")
+	k8sProtobuf := flag.Bool("protobuf", false, "List and watch pods and events in the protobuf wire format")
+	impersonate := flag.Bool("impersonate", false, "Act on behalf of the users named by the Ocopea-User header")
+	impersonateUsers := flag.String("impersonate-users", "", "Comma separated users that may be impersonated")
+	impersonateGroups := flag.String("impersonate-groups", "", "Comma separated groups that may be impersonated")
 	flag.Parse()
+	gImpersonation = newImpersonationPolicy(*impersonate, *impersonateUsers, *impersonateGroups)
 
 	host, hb := os.LookupEnv("KUBERNETES_SERVICE_HOST")
 	port, pb := os.LookupEnv("KUBERNETES_SERVICE_PORT")
@@ -715,23 +1042,31 @@ func main() {
 	}
 	fmt.Printf("url %s\nnamespace:%s\n", *k8sURL, nazNS)
 
-	if deploymentType == "local" &&
-		(!lcb || len(gLocalClusterIp) == 0) {
-		panic("on local deployments LOCAL_CLUSTER_IP must be defined")
+	// Building "secure" http client. The service account token is rotated by kubernetes, so the token file is read
+	// again whenever it changes. K8S_USERNAME and K8S_PASSWORD are used when there is no service account token
+	var credentials kubernetesClient.CredentialProvider = kubernetesClient.NewFileTokenProvider(serviceAccountTokenPath)
+	if _, err := os.Stat(serviceAccountTokenPath); os.IsNotExist(err) && k8sUserName != "" {
+		credentials = &kubernetesClient.BasicAuthProvider{UserName: k8sUserName, Password: k8sPassword}
 	}
-
-	// Building "secure" http client
-	var err error
-	kClient, err = kubernetesClient.NewClient(
-		*k8sURL,
-		nazNS,
-		k8sUserName,
-		k8sPassword,
-		"/var/run/secrets/kubernetes.io/serviceaccount/token")
-
+	client, err := kubernetesClient.NewClientWithCredentials(*k8sURL, nazNS, credentials)
 	if err != nil {
 		panic(err)
 	}
+	client.Protobuf = *k8sProtobuf
+	kClient = client
+	eventRecorder = kubernetesClient.NewEventRecorder(kClient, "k8spsb")
+
+	// On local deployments, when LOCAL_CLUSTER_IP is not defined we discover it using the cluster nodes
+	if deploymentType == "local" &&
+		(!lcb || len(gLocalClusterIp) == 0) {
+		gLocalClusterIp, err = kClient.FindClusterAddress()
+		if err != nil {
+			log.Printf("LOCAL_CLUSTER_IP is not defined and discovering the cluster address failed - %s\n", err.Error())
+			log.Println("Set LOCAL_CLUSTER_IP to the address of a cluster node on local deployments, or allow the service account to list nodes")
+			os.Exit(1)
+		}
+		fmt.Printf("LOCAL_CLUSTER_IP not defined, using discovered cluster ip %s\n", gLocalClusterIp)
+	}
 
 	k8sPsbHost := os.Getenv("K8SPSB_SERVICE_HOST")
 	k8sPsbPort := os.Getenv("K8SPSB_SERVICE_PORT")
@@ -745,6 +1080,7 @@ func main() {
 	router.HandleFunc("/k8spsb-api/psb/app-services", deployAppHandler)
 	router.HandleFunc("/k8spsb-api/psb/app-services/{space}/{appServiceId}", appServiceInfoHandler)
 	router.HandleFunc("/k8spsb-api/psb/app-services/{space}/{appServiceId}/logs", appServiceLogsHandler)
+	router.HandleFunc("/k8spsb-api/psb/app-services/{space}/{appServiceId}/diff", appServiceDiffHandler)
 	router.HandleFunc("/k8spsb-api/psb/app-services/{space}/{appServiceId}/logs/data", logsDataHandler)
 	router.HandleFunc("/k8spsb-api/psb/spaces", listSpacesHandler)
 	router.HandleFunc("/k8spsb-api/state", serviceStateHandler)
@@ -3,11 +3,15 @@ package main
 
 import (
 	"encoding/json"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"ocopea/kubernetes/client"
+	"ocopea/kubernetes/client/resource"
 	"ocopea/kubernetes/client/v1"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -219,3 +223,373 @@ func TestHandleAppServiceInfoServiceDoesNotReady(t *testing.T) {
 		t.Errorf("invalid app instance info returned: %s, want %s", appInstanceInfoResult.Status, "running")
 	}
 }
+
+// Testing deploy app with an app service id that can't be used for naming k8s objects
+func TestDeployAppInvalidAppServiceId(t *testing.T) {
+
+	kClient = &client.ClientMock{
+		MockDeployReplicationController: func(serviceName string, rc *v1.ReplicationController, force bool) (*v1.ReplicationController, error) {
+			t.Errorf("replication controller should not be deployed for an invalid app service id")
+			return rc, nil
+		},
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(deployAppHandler))
+	defer ts.Close()
+
+	for _, appServiceId := range []string{"My_App", "1app", "app-service-id-longer-than-24"} {
+		res, err := http.Post(
+			ts.URL,
+			"application/json",
+			strings.NewReader(`{"appServiceId":"`+appServiceId+`","imageName":"nginx","httpPort":80}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		if res.StatusCode != http.StatusBadRequest {
+			t.Errorf("invalid status %d for app service id %s, expected %d", res.StatusCode, appServiceId, http.StatusBadRequest)
+		}
+	}
+}
+
+// Testing the app service life cycle - deploy, info and delete
+func TestDeployAppLifeCycle(t *testing.T) {
+
+	parseRequestVars = func(r *http.Request) map[string]string {
+		return map[string]string{
+			"appServiceId": "app1",
+			"space":        "space1",
+		}
+	}
+
+	fakeClient := client.NewFakeClient("space1")
+	kClient = fakeClient
+	eventRecorder = client.NewEventRecorder(fakeClient, "k8spsb")
+	deploymentType = "local"
+	gLocalClusterIp = "192.168.99.100"
+	defer func() {
+		eventRecorder = nil
+		deploymentType = ""
+		gLocalClusterIp = ""
+	}()
+
+	deployServer := httptest.NewServer(http.HandlerFunc(deployAppHandler))
+	defer deployServer.Close()
+	res, err := http.Post(
+		deployServer.URL,
+		"application/json",
+		strings.NewReader(`{"appServiceId":"app1","imageName":"nginx","httpPort":80}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		t.Fatalf("invalid status %d, expected %d", res.StatusCode, http.StatusCreated)
+	}
+
+	svc, err := fakeClient.GetServiceInfo("app1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if svc.Spec.Type != v1.ServiceTypeNodePort || svc.Spec.Selector["app"] != "app1" {
+		t.Errorf("unexpected service spec %+v", svc.Spec)
+	}
+
+	infoServer := httptest.NewServer(http.HandlerFunc(appServiceInfoHandler))
+	defer infoServer.Close()
+	res, err = http.Get(infoServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var info appInstanceInfo
+	err = json.NewDecoder(res.Body).Decode(&info)
+	res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedURL := "http://192.168.99.100:" + strconv.Itoa(svc.Spec.Ports[0].NodePort)
+	if info.Status != "running" || info.EntryPointURL != expectedURL {
+		t.Errorf("invalid app instance info returned: %v, want running on %s", info, expectedURL)
+	}
+
+	req, err := http.NewRequest("DELETE", infoServer.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("invalid status %d, expected %d", res.StatusCode, http.StatusOK)
+	}
+	if exists, _ := fakeClient.CheckServiceExists("app1"); exists {
+		t.Errorf("expected service app1 to be deleted")
+	}
+	if _, err = fakeClient.GetReplicationControllerInfo("app1"); !client.IsNotFound(err) {
+		t.Errorf("expected replication controller app1 to be deleted, got %v", err)
+	}
+	if pods, _ := fakeClient.ListPodsInfo(map[string]string{"app": "app1"}); len(pods) != 0 {
+		t.Errorf("expected the pods of app1 to be deleted, got %d pods", len(pods))
+	}
+
+	events, err := fakeClient.ListEvents(client.EventQuery{InvolvedObjectKind: "ReplicationController", InvolvedObjectName: "app1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[0].Reason != "AppServiceDeployed" || events[1].Reason != "AppServiceDeleted" {
+		t.Errorf("expected deployed and deleted events, got %+v", events)
+	}
+}
+
+// App services that can't be scheduled are refused instead of staying pending
+func TestDeployAppInsufficientResources(t *testing.T) {
+	fakeClient := client.NewFakeClient("space1",
+		&v1.Node{
+			ObjectMeta: v1.ObjectMeta{Name: "node1"},
+			Status: v1.NodeStatus{Allocatable: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("2"),
+				v1.ResourceMemory: resource.MustParse("4Gi"),
+			}},
+		},
+		&v1.ResourceQuota{
+			ObjectMeta: v1.ObjectMeta{Name: "quota1"},
+			Spec:       v1.ResourceQuotaSpec{Hard: v1.ResourceList{v1.ResourceMemory: resource.MustParse("1Gi")}},
+		})
+	kClient = fakeClient
+
+	ts := httptest.NewServer(http.HandlerFunc(deployAppHandler))
+	defer ts.Close()
+
+	for _, settings := range []string{`{"cpu":"4"}`, `{"memory":"2Gi"}`} {
+		res, err := http.Post(
+			ts.URL,
+			"application/json",
+			strings.NewReader(`{"appServiceId":"app1","imageName":"nginx","httpPort":80,"psbSettings":`+settings+`}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		if res.StatusCode != http.StatusUnprocessableEntity {
+			t.Errorf("invalid status %d for settings %s, expected %d", res.StatusCode, settings, http.StatusUnprocessableEntity)
+		}
+	}
+	if _, err := fakeClient.GetReplicationControllerInfo("app1"); !client.IsNotFound(err) {
+		t.Errorf("expected replication controller app1 not to be deployed, got %v", err)
+	}
+
+	res, err := http.Post(
+		ts.URL,
+		"application/json",
+		strings.NewReader(`{"appServiceId":"app1","imageName":"nginx","httpPort":80,"psbSettings":{"cpu":"500m","memory":"512Mi"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		t.Fatalf("invalid status %d, expected %d", res.StatusCode, http.StatusCreated)
+	}
+	rc, err := fakeClient.GetReplicationControllerInfo("app1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	memory := rc.Spec.Template.Spec.Containers[0].Resources.Requests[v1.ResourceMemory]
+	if memory.String() != "512Mi" {
+		t.Errorf("expected the container to request 512Mi memory, got %s", memory.String())
+	}
+}
+
+// The pods of a previous deployment of an app service are not counted against the quota when redeploying it
+func TestDeployAppRedeployWithinQuota(t *testing.T) {
+	previousPod := &v1.Pod{
+		ObjectMeta: v1.ObjectMeta{Name: "app1-0", Labels: map[string]string{"app": "app1", "nazKind": "app"}},
+		Spec: v1.PodSpec{Containers: []v1.Container{{
+			Name:      "app1",
+			Image:     "nginx",
+			Resources: v1.ResourceRequirements{Requests: v1.ResourceList{v1.ResourceMemory: resource.MustParse("768Mi")}},
+		}}},
+		Status: v1.PodStatus{Phase: v1.PodRunning},
+	}
+	fakeClient := client.NewFakeClient("space1",
+		previousPod,
+		&v1.ResourceQuota{
+			ObjectMeta: v1.ObjectMeta{Name: "quota1"},
+			Status: v1.ResourceQuotaStatus{
+				Hard: v1.ResourceList{v1.ResourceMemory: resource.MustParse("1Gi"), v1.ResourcePods: resource.MustParse("1")},
+				Used: v1.ResourceList{v1.ResourceMemory: resource.MustParse("768Mi"), v1.ResourcePods: resource.MustParse("1")},
+			},
+		})
+	kClient = fakeClient
+
+	ts := httptest.NewServer(http.HandlerFunc(deployAppHandler))
+	defer ts.Close()
+
+	for _, test := range []struct {
+		appServiceId   string
+		expectedStatus int
+	}{{"app2", http.StatusUnprocessableEntity}, {"app1", http.StatusCreated}} {
+		res, err := http.Post(
+			ts.URL,
+			"application/json",
+			strings.NewReader(`{"appServiceId":"`+test.appServiceId+`","imageName":"nginx","httpPort":80,"psbSettings":{"memory":"768Mi"}}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		if res.StatusCode != test.expectedStatus {
+			t.Errorf("invalid status %d deploying %s, expected %d", res.StatusCode, test.appServiceId, test.expectedStatus)
+		}
+	}
+}
+
+// Diff reports how a posted manifest differs from the deployed app service
+func TestAppServiceDiff(t *testing.T) {
+	parseRequestVars = func(r *http.Request) map[string]string {
+		return map[string]string{
+			"appServiceId": "app1",
+			"space":        "space1",
+		}
+	}
+	kClient = client.NewFakeClient("space1")
+
+	diffServer := httptest.NewServer(http.HandlerFunc(appServiceDiffHandler))
+	defer diffServer.Close()
+	postDiff := func(manifest string, accept string) *http.Response {
+		req, err := http.NewRequest("POST", diffServer.URL, strings.NewReader(manifest))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Accept", accept)
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	manifest := `{"appServiceId":"app1","imageName":"nginx","httpPort":80}`
+	res := postDiff(manifest, "application/json")
+	var diff client.ObjectDiff
+	err := json.NewDecoder(res.Body).Decode(&diff)
+	res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.Exists || diff.Kind != "ReplicationController" || diff.Name != "app1" {
+		t.Errorf("expected app1 not to exist, got %+v", diff)
+	}
+
+	deployServer := httptest.NewServer(http.HandlerFunc(deployAppHandler))
+	defer deployServer.Close()
+	res, err = http.Post(deployServer.URL, "application/json", strings.NewReader(manifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		t.Fatalf("invalid status %d, expected %d", res.StatusCode, http.StatusCreated)
+	}
+
+	res = postDiff(manifest, "application/json")
+	err = json.NewDecoder(res.Body).Decode(&diff)
+	res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !diff.Empty() {
+		t.Errorf("expected no differences, got %+v", diff)
+	}
+
+	res = postDiff(`{"appServiceId":"app1","imageName":"nginx:1.13","httpPort":80}`, "text/plain")
+	body, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Tagging the image changes the pull policy the server defaults the container to
+	expected := "ReplicationController app1:\n" +
+		"  ~ spec.template.spec.containers[name=app1].image: \"nginx\" -> \"nginx:1.13\"\n" +
+		"  ~ spec.template.spec.containers[name=app1].imagePullPolicy: \"Always\" -> \"IfNotPresent\"\n"
+	if string(body) != expected {
+		t.Errorf("unexpected diff %q, expected %q", string(body), expected)
+	}
+}
+
+// Posts an app service manifest on behalf of the user and groups, returning the status code and the impersonation
+// and create actions of the client
+func deployAppAs(t *testing.T, userName string, groups string) (int, []string) {
+	fakeClient := client.NewFakeClient("space1")
+	kClient = fakeClient
+
+	ts := httptest.NewServer(http.HandlerFunc(deployAppHandler))
+	defer ts.Close()
+	req, err := http.NewRequest("POST", ts.URL, strings.NewReader(`{"appServiceId":"app1","imageName":"nginx","httpPort":80}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Ocopea-User", userName)
+	req.Header.Set("Ocopea-Groups", groups)
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	var actions []string
+	for _, action := range fakeClient.Actions() {
+		if action.Verb == "impersonate" || action.Verb == "create" {
+			actions = append(actions, action.String())
+		}
+	}
+	return res.StatusCode, actions
+}
+
+// App services are deployed on behalf of the Ocopea user of the request
+func TestDeployAppImpersonatesOcopeaUser(t *testing.T) {
+	gImpersonation = newImpersonationPolicy(true, "alice, bob", "developers,testers")
+	defer func() { gImpersonation = impersonationPolicy{} }()
+
+	statusCode, actions := deployAppAs(t, "alice", "developers, testers")
+	if statusCode != http.StatusCreated {
+		t.Fatalf("invalid status %d, expected %d", statusCode, http.StatusCreated)
+	}
+	expected := []string{"impersonate users/alice", "create replicationcontrollers/app1", "create services/app1"}
+	if !reflect.DeepEqual(actions, expected) {
+		t.Errorf("expected the app service to be created on behalf of alice %v, got %v", expected, actions)
+	}
+}
+
+// The headers are not authenticated, so users and groups that are not allowlisted are rejected
+func TestDeployAppRejectsImpersonation(t *testing.T) {
+	gImpersonation = newImpersonationPolicy(true, "alice,system:admin", "developers,system:masters")
+	defer func() { gImpersonation = impersonationPolicy{} }()
+
+	for _, test := range []struct {
+		userName string
+		groups   string
+	}{
+		{"mallory", ""},
+		{"alice", "admins"},
+		{"alice", "developers,system:masters"},
+		{"system:admin", ""},
+	} {
+		statusCode, actions := deployAppAs(t, test.userName, test.groups)
+		if statusCode != http.StatusForbidden || len(actions) != 0 {
+			t.Errorf("expected impersonating %s of %q to be forbidden, got %d %v", test.userName, test.groups, statusCode, actions)
+		}
+	}
+}
+
+// Without enabling impersonation requests are served with the credentials of k8spsb
+func TestDeployAppIgnoresUserWhenImpersonationDisabled(t *testing.T) {
+	statusCode, actions := deployAppAs(t, "alice", "system:masters")
+	if statusCode != http.StatusCreated {
+		t.Fatalf("invalid status %d, expected %d", statusCode, http.StatusCreated)
+	}
+	expected := []string{"create replicationcontrollers/app1", "create services/app1"}
+	if !reflect.DeepEqual(actions, expected) {
+		t.Errorf("expected the app service to be created without impersonation %v, got %v", expected, actions)
+	}
+}